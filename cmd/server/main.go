@@ -1,8 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 	"vetblock/internal/api"
+	"vetblock/internal/db"
+	"vetblock/internal/db/model"
+	"vetblock/internal/service"
 	
 
 	"github.com/gofiber/fiber/v2"
@@ -11,24 +16,29 @@ import (
 )
 
 
-var animalRepo repository.AnimalRepositoryInterface
-func init() {
-	animalRepo = repository.NewAnimalRepository()
-
-    if animalRepo == nil {
-        log.Fatal("Failed to initialize animalRepo")
-    }
-}
-
-
 func main() {
 	
 	loadEnv()
+
+	// Abre a conexão com o banco uma única vez; rotas e rotinas compartilham o mesmo pool
+	database := db.GetDB()
+
 	// Inicializar o Fiber e as rotas
 	app := fiber.New()
 
-	// Configurar as rotas, passando o serviço
-	api.SetupRoutes(app)
+	// Configurar as rotas, passando a conexão
+	api.SetupRoutes(app, database)
+
+	// Expurga diariamente os animais que estão na lixeira há mais tempo que a retenção configurada
+	go service.NewAnimalService(repository.NewAnimalRepository(database)).RunAnimalTrashPurge(context.Background(), 24*time.Hour, service.AnimalTrashRetention())
+
+	// Envia diariamente os lembretes de vacina aos tutores que autorizaram o contato por e-mail
+	if email := service.EmailSenderFromEnv(); email != nil {
+		consents := service.NewConsentService(repository.NewConsentRepository(database), repository.NewTutorRepository(database))
+		notifications := service.NewNotificationService(consents, map[string]service.NotificationSender{model.ConsentChannelEmail: email})
+		vaccinations := service.NewVaccinationService(repository.NewVaccinationRepository(database), repository.NewAnimalRepository(database))
+		go service.NewVaccinationReminderService(vaccinations, notifications).RunVaccinationReminders(context.Background(), 24*time.Hour, service.DefaultReminderDays)
+	}
 
 	log.Println("Servidor iniciado na porta 8080...")
 	log.Fatal(app.Listen(":8081"))
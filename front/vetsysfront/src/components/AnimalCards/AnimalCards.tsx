@@ -1,4 +1,4 @@
-import React, { useState, useEffect } from 'react';
+import React, { useState, useEffect, useRef } from 'react';
 import axios from 'axios';
 import { Card, CardContent } from '../ui/card';
 import { Button } from '../ui/button';
@@ -24,28 +24,47 @@ interface AnimalCardsProps {
 const AnimalCards: React.FC<AnimalCardsProps> = ({ searchTerm, currentPage, onPageChange }) => {
   const [animals, setAnimals] = useState<Animal[]>([]);
   const [isLoading, setIsLoading] = useState(true);
-  const [totalPages, setTotalPages] = useState(1);
+  const [hasNextPage, setHasNextPage] = useState(false);
   const [selectedAnimal, setSelectedAnimal] = useState<Animal | null>(null);
   const [isModalOpen, setIsModalOpen] = useState(false);
   const itemsPerPage = 12;
+  // Cursor de cada página já visitada para a busca atual; pages[0] é a primeira página
+  const cursorsRef = useRef<{ search: string; pages: string[] }>({ search: '', pages: [''] });
 
   useEffect(() => {
     fetchAnimals();
   }, [searchTerm, currentPage]);
 
   const fetchAnimals = async () => {
+    if (cursorsRef.current.search !== searchTerm) {
+      cursorsRef.current = { search: searchTerm, pages: [''] };
+    }
+    // A API pagina por cursor: só é possível abrir páginas já alcançadas pela navegação
+    const cursor = cursorsRef.current.pages[currentPage - 1];
+    if (cursor === undefined) {
+      onPageChange(1);
+      return;
+    }
+
     setIsLoading(true);
     try {
-      const response = await axios.get<Animal[]>('http://localhost:8081/api/v1/animals');
-      const filteredAnimals = response.data.filter((animal) =>
-        animal.name.toLowerCase().includes(searchTerm.toLowerCase())
+      const params: Record<string, string | number> = { sort_by: 'name', limit: itemsPerPage };
+      if (searchTerm.trim()) {
+        params.name = searchTerm.trim();
+      }
+      if (cursor) {
+        params.cursor = cursor;
+      }
+      const response = await axios.get<{ animals: Animal[]; next_cursor: string }>(
+        'http://localhost:8081/api/v1/animals',
+        { params }
       );
-      
-      const startIndex = (currentPage - 1) * itemsPerPage;
-      const paginatedAnimals = filteredAnimals.slice(startIndex, startIndex + itemsPerPage);
 
-      setAnimals(paginatedAnimals);
-      setTotalPages(Math.ceil(filteredAnimals.length / itemsPerPage));
+      if (response.data.next_cursor) {
+        cursorsRef.current.pages[currentPage] = response.data.next_cursor;
+      }
+      setAnimals(response.data.animals ?? []);
+      setHasNextPage(Boolean(response.data.next_cursor));
     } catch (error) {
       console.error('Erro ao buscar animais:', error);
     } finally {
@@ -70,7 +89,7 @@ const AnimalCards: React.FC<AnimalCardsProps> = ({ searchTerm, currentPage, onPa
   };
 
   const handleNextPage = () => {
-    if (currentPage < totalPages) {
+    if (hasNextPage) {
       onPageChange(currentPage + 1);
     }
   };
@@ -127,9 +146,9 @@ const AnimalCards: React.FC<AnimalCardsProps> = ({ searchTerm, currentPage, onPa
               Anterior
             </Button>
             <Button 
-              className={`px-4 py-2 rounded-lg mx-2 ${currentPage === totalPages ? 'bg-gray-300 cursor-not-allowed' : 'bg-gray-400 text-white hover:bg-gray-500'} transition-all duration-200`} 
+              className={`px-4 py-2 rounded-lg mx-2 ${!hasNextPage ? 'bg-gray-300 cursor-not-allowed' : 'bg-gray-400 text-white hover:bg-gray-500'} transition-all duration-200`} 
               onClick={handleNextPage}
-              disabled={currentPage === totalPages}
+              disabled={!hasNextPage}
             >
               Próximo
             </Button>
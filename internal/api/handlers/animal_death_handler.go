@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+	"vetblock/internal/db/model"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DeathRecordRequest struct {
+	DateOfDeath     string `json:"date_of_death"` // 2006-01-02; padrão: hoje
+	Cause           string `json:"cause"`
+	Euthanasia      bool   `json:"euthanasia"`
+	CRVM            string `json:"crvm"`
+	BodyDisposition string `json:"body_disposition"` // cremation ou burial
+	Notes           string `json:"notes"`
+	AuthorizedBy    string `json:"authorized_by"` // CPF do tutor principal que autorizou a eutanásia
+}
+
+// Handler para registrar o óbito do animal; as consultas agendadas são canceladas
+func RecordAnimalDeathHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		var request DeathRecordRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		dateOfDeath := time.Now().Truncate(24 * time.Hour)
+		if request.DateOfDeath != "" {
+			dateOfDeath, err = time.Parse("2006-01-02", request.DateOfDeath)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid date format")
+			}
+		}
+
+		record := model.DeathRecord{
+			DateOfDeath:     model.CustomDate{Time: dateOfDeath},
+			Cause:           request.Cause,
+			Euthanasia:      request.Euthanasia,
+			CRVM:            model.CleanCRMV(request.CRVM),
+			BodyDisposition: request.BodyDisposition,
+			Notes:           request.Notes,
+			AuthorizedBy:    CleanCpf(request.AuthorizedBy),
+		}
+
+		saved, cancelled, err := animal_service.RecordDeath(id, record, currentUserID(c), veterinary_service.FindVeterinary, time.Now())
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to record animal death")
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"death_record":            saved,
+			"cancelled_consultations": cancelled,
+		})
+	}
+}
+
+// Handler para obter o registro de óbito do animal
+func GetAnimalDeathHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		record, err := animal_service.GetDeathRecord(id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).SendString("Death record not found")
+		}
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to get death record")
+		}
+
+		return c.JSON(record)
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"log"
 	"strings"
 	"time"
@@ -12,19 +13,21 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
-var animal_service = service.NewAnimalService(repository.NewAnimalRepository())
-
 type AnimalResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
-	Species     string    `json:"species" gorm:"not null" validate:"required"`
-	Breed       string    `json:"breed" gorm:"not null" validate:"required"`
-	Weight      float64   `json:"weight" validate:"gte=0"`
-	Age         int       `json:"age" validate:"gte=0"`
-	Description string    `json:"description"`
-	CPFTutor    string    `gorm:"type:char(11);not null" json:"cpf_tutor" validate:"required,len=11"`
+	ID                 uuid.UUID `json:"id"`
+	Name               string    `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
+	Species            string    `json:"species" gorm:"not null" validate:"required"`
+	Breed              string    `json:"breed" gorm:"not null" validate:"required"`
+	Weight             float64   `json:"weight" validate:"gte=0"`
+	Age                int       `json:"age" validate:"gte=0"` // Legado: usado apenas quando birth_date não é informado
+	BirthDate          string    `json:"birth_date"`           // 2006-01-02, 2006-01 ou 2006
+	BirthDatePrecision string    `json:"birth_date_precision"`
+	Description        string    `json:"description"`
+	CPFTutor           string    `gorm:"type:varchar(14);not null" json:"cpf_tutor" validate:"required,len=11|len=14"`
+	Microchip          string    `json:"microchip"`
 }
 
 type DosageResponse struct {
@@ -37,6 +40,8 @@ type DosageResponse struct {
 	Dosage            string           `json:"dosage" validate:"required"`
 	ConsultationID    *uuid.UUID       `json:"consultation_id"`    // Relacionamento opcional
 	HospitalizationID *uuid.UUID       `json:"hospitalization_id"` // Relacionamento opcional
+	OverrideReason    string           `json:"override_reason"`    // Obrigatório quando o medicamento é contraindicado para o animal
+	CRVM              string           `json:"crvm"`               // Veterinário que assina a prescrição
 }
 
 func AddAnimalHandler() fiber.Handler {
@@ -51,16 +56,12 @@ func AddAnimalHandler() fiber.Handler {
 		animal.CPFTutor = CleanCpf(animal.CPFTutor)
 
 		// Convert the AnimalResponse to Animal
-		animalModel := model.Animal{
-			ID:          uuid.New(),
-			Name:        animal.Name,
-			Species:     animal.Species,
-			Breed:       animal.Breed,
-			Weight:      animal.Weight,
-			Age:         animal.Age,
-			Description: animal.Description,
-			CPFTutor:    animal.CPFTutor,
+		animalModel, err := animal.toModel()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
 		}
+		animalModel.ID = uuid.New()
+		animal.ID = animalModel.ID
 
 		// Validação dos campos do Animal
 		if err := service.ValidateAnimal(animalModel); err != nil {
@@ -68,7 +69,12 @@ func AddAnimalHandler() fiber.Handler {
 		}
 
 		// Adiciona o animal usando o serviço
-		err := animal_service.AddAnimal(animalModel)
+		err = animal_service.AddAnimal(animalModel)
+		if errors.Is(err, model.ErrInvalidMicrochip) || errors.Is(err, service.ErrMicrochipInUse) ||
+			errors.Is(err, service.ErrUnknownSpecies) || errors.Is(err, service.ErrUnknownBreed) ||
+			errors.Is(err, service.ErrUnknownTutor) || errors.Is(err, service.ErrTutorInactive) {
+			return animalErrorResponse(c, err, "Failed to add animal")
+		}
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"message": err.Error(),
@@ -79,10 +85,41 @@ func AddAnimalHandler() fiber.Handler {
 	}
 }
 
+// toModel converte o corpo da requisição no modelo Animal, transformando a data de
+// nascimento (ou a idade enviada por clientes antigos) em BirthDate
+func (a AnimalResponse) toModel() (model.Animal, error) {
+	var age *int
+	if a.Age > 0 {
+		age = &a.Age
+	}
+	birthDate, precision, err := service.ResolveBirthDate(a.BirthDate, a.BirthDatePrecision, age, time.Now())
+	if err != nil {
+		return model.Animal{}, err
+	}
+
+	animal := model.Animal{
+		Name:               a.Name,
+		Species:            a.Species,
+		Breed:              a.Breed,
+		Weight:             a.Weight,
+		BirthDate:          birthDate,
+		BirthDatePrecision: precision,
+		Description:        a.Description,
+		CPFTutor:           a.CPFTutor,
+	}
+	if a.Microchip != "" {
+		microchip := a.Microchip
+		animal.Microchip = &microchip
+	}
+	animal.RefreshAge(time.Now())
+	return animal, nil
+}
+
 func CleanCpf(cpf string) string {
 	cpf = strings.ReplaceAll(cpf, ".", "")
 	cpf = strings.ReplaceAll(cpf, "-", "")
-	return cpf
+	cpf = strings.ReplaceAll(cpf, "/", "")
+	return strings.ToUpper(cpf)
 }
 
 func UpdateAnimalHandler() fiber.Handler {
@@ -92,20 +129,115 @@ func UpdateAnimalHandler() fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
 		}
 
-		var animal model.Animal
+		var animal AnimalResponse
 		if err := c.BodyParser(&animal); err != nil {
 			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
 		}
 
+		animal.CPFTutor = CleanCpf(animal.CPFTutor)
+		animalModel, err := animal.toModel()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+		// Sem birth_date, a idade legada segue para o serviço, que só a usa se o
+		// animal ainda não tiver data de nascimento
+		if animal.BirthDate == "" {
+			animalModel.BirthDate, animalModel.BirthDatePrecision, animalModel.Age = nil, "", animal.Age
+		}
+
 		// Atualiza o animal
-		if err := animal_service.UpdateAnimal(id, animal); err != nil {
-			return c.Status(fiber.StatusInternalServerError).SendString("Failed to update animal")
+		updated, err := animal_service.UpdateAnimal(id, animalModel, currentUserID(c))
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to update animal")
+		}
+
+		return c.Status(fiber.StatusOK).JSON(updated)
+	}
+}
+
+// Atualização parcial: apenas os campos enviados no corpo são alterados
+func PatchAnimalHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		var patch service.AnimalPatch
+		if err := c.BodyParser(&patch); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+		if patch.CPFTutor != nil {
+			cpf := CleanCpf(*patch.CPFTutor)
+			patch.CPFTutor = &cpf
 		}
 
-		return c.Status(fiber.StatusOK).JSON(animal)
+		updated, err := animal_service.PatchAnimal(id, patch, currentUserID(c))
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to update animal")
+		}
+
+		return c.Status(fiber.StatusOK).JSON(updated)
 	}
 }
 
+func GetAnimalHistoryHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		history, err := animal_service.GetAnimalHistory(id)
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to get animal history")
+		}
+
+		return c.Status(fiber.StatusOK).JSON(history)
+	}
+}
+
+// animalErrorResponse traduz erros do serviço de animais para o status HTTP adequado
+func animalErrorResponse(c *fiber.Ctx, err error, message string) error {
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": validationErrorMessages(validationErrs),
+		})
+	case errors.Is(err, model.ErrInvalidMicrochip), errors.Is(err, service.ErrMergeSameAnimal), errors.Is(err, service.ErrMergeMismatch),
+		errors.Is(err, service.ErrUnknownSpecies), errors.Is(err, service.ErrUnknownBreed):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, service.ErrFutureDeathDate), errors.Is(err, service.ErrDeathBeforeBirth), errors.Is(err, service.ErrUnknownVeterinarian):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, service.ErrUnknownTutor), errors.Is(err, service.ErrTutorInactive),
+		errors.Is(err, service.ErrInvalidTutorRole), errors.Is(err, service.ErrInvalidCPF), errors.Is(err, service.ErrTutorChangeNotAllowed):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, service.ErrTutorIsPrimaryOwner):
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	case errors.Is(err, service.ErrNotPrimaryOwner):
+		return c.Status(fiber.StatusForbidden).SendString(err.Error())
+	case errors.Is(err, service.ErrAnimalTutorNotFound):
+		return c.Status(fiber.StatusNotFound).SendString(err.Error())
+	case errors.Is(err, service.ErrMicrochipInUse), errors.Is(err, service.ErrAnimalExists),
+		errors.Is(err, service.ErrAnimalDeceased), errors.Is(err, service.ErrAnimalAlreadyDeceased), errors.Is(err, service.ErrAnimalMerged):
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).SendString("Animal not found")
+	default:
+		log.Printf("%s: %v", message, err)
+		return c.Status(fiber.StatusInternalServerError).SendString(message)
+	}
+}
+
+func validationErrorMessages(errs validator.ValidationErrors) []string {
+	var errorMessages []string
+	for _, e := range errs {
+		errorMessages = append(errorMessages, e.Field()+" is "+e.Tag())
+	}
+	return errorMessages
+}
+
 func DeleteAnimalHandler() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id, err := uuid.Parse(c.Params("id"))
@@ -172,10 +304,27 @@ func AddDosageHandler(dosageService *service.DosageService) fiber.Handler {
 			Dosage:            dosage.Dosage,
 			ConsultationID:    nilIfEmpty(dosage.ConsultationID),
 			HospitalizationID: nilIfEmpty(dosage.HospitalizationID),
+			OverrideReason:    dosage.OverrideReason,
+			OverriddenBy:      currentUserID(c),
+			CRVM:              model.CleanCRMV(dosage.CRVM),
 		}
 
 		// Chama o serviço para adicionar a dosagem
 		err = dosageService.AddDosage(context.Background(), &dosageModel)
+		var contraindicationErr *service.ContraindicationError
+		if errors.As(err, &contraindicationErr) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":             err.Error(),
+				"contraindications": contraindicationErr.Contraindications,
+				"message":           "override_reason is required to prescribe this medication",
+			})
+		}
+		if errors.Is(err, service.ErrAnimalDeceased) || errors.Is(err, service.ErrNoActiveLicense) {
+			return c.Status(fiber.StatusConflict).SendString(err.Error())
+		}
+		if errors.Is(err, service.ErrPrescriberRequired) || errors.Is(err, service.ErrUnknownVeterinarian) {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).SendString("Failed to add dosage transaction")
 		}
@@ -194,6 +343,10 @@ func nilIfEmpty(id *uuid.UUID) *uuid.UUID {
 	return id
 }
 
+// Lista animais com filtros, ordenação e paginação por cursor.
+// Parâmetros: species, breed, cpf_tutor (em qualquer papel), tutor_role, name (prefixo),
+// created_from, created_to, sort_by (name, species, breed, created_at), order (asc, desc),
+// cursor e limit.
 func GetAllAnimalsHandler() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Check if context is nil (unlikely but possible in middleware scenarios)
@@ -201,12 +354,67 @@ func GetAllAnimalsHandler() fiber.Handler {
 			log.Println("fiber.Ctx is nil")
 			return fiber.ErrInternalServerError
 		}
-		animals, err := animal_service.GetAllAnimals()
+
+		query := repository.AnimalQuery{
+			Species:    c.Query("species"),
+			Breed:      c.Query("breed"),
+			CPFTutor:   CleanCpf(c.Query("cpf_tutor")),
+			TutorRole:  c.Query("tutor_role"),
+			NamePrefix: c.Query("name"),
+			SortBy:     c.Query("sort_by"),
+			Cursor:     c.Query("cursor"),
+			Limit:      c.QueryInt("limit"),
+		}
+
+		switch strings.ToLower(c.Query("order", "asc")) {
+		case "asc":
+		case "desc":
+			query.SortDesc = true
+		default:
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid order, use asc or desc")
+		}
+
+		if from := c.Query("created_from"); from != "" {
+			parsed, err := parseDateQuery(from, false)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid created_from date")
+			}
+			query.CreatedFrom = &parsed
+		}
+		if to := c.Query("created_to"); to != "" {
+			parsed, err := parseDateQuery(to, true)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid created_to date")
+			}
+			query.CreatedTo = &parsed
+		}
+
+		page, err := animal_service.ListAnimals(query)
 		if err != nil {
+			if errors.Is(err, repository.ErrInvalidCursor) || errors.Is(err, repository.ErrInvalidSortField) || errors.Is(err, service.ErrInvalidTutorRole) ||
+				errors.Is(err, service.ErrInvalidDateRange) {
+				return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+			}
 			return c.Status(fiber.StatusInternalServerError).SendString("Failed to get all animals")
 		}
-		return c.JSON(animals)
+		return c.JSON(page)
+	}
+}
+
+// parseDateQuery aceita datas no formato 2006-01-02 ou RFC3339. Quando endOfDay é
+// verdadeiro, uma data sem horário passa a valer até o fim daquele dia.
+func parseDateQuery(value string, endOfDay bool) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
 	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return parsed, nil
 }
 
 func GetAnimalByIDHandler() fiber.Handler {
@@ -227,3 +435,15 @@ func GetAnimalByIDHandler() fiber.Handler {
 		return c.JSON(animal)
 	}
 }
+
+// Busca o paciente pelo número do microchip lido na recepção
+func GetAnimalByMicrochipHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		animal, err := animal_service.GetAnimalByMicrochip(c.Params("number"))
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to get animal")
+		}
+
+		return c.JSON(animal)
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type MergeAnimalRequest struct {
+	SourceID string `json:"source_id"`
+}
+
+// Lista todos os pares de animais que provavelmente são o mesmo paciente
+func GetDuplicateAnimalsHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		pairs, err := animal_service.ListDuplicateCandidates()
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to list duplicate animals")
+		}
+
+		return c.JSON(pairs)
+	}
+}
+
+// Lista os prováveis duplicados de um animal (mesmo tutor, mesma espécie e nome parecido)
+func GetAnimalDuplicatesHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		candidates, err := animal_service.FindDuplicateCandidates(id)
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to find duplicate animals")
+		}
+
+		return c.JSON(candidates)
+	}
+}
+
+// Funde o animal informado em source_id no animal da rota, que é mantido
+func MergeAnimalHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		targetID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		var req MergeAnimalRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+		sourceID, err := uuid.Parse(req.SourceID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid source_id format")
+		}
+
+		merge, err := animal_service.MergeAnimals(targetID, sourceID, currentUserID(c))
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to merge animals")
+		}
+
+		return c.JSON(merge)
+	}
+}
+
+// Lista as fusões das quais o animal participou
+func GetAnimalMergesHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		merges, err := animal_service.GetAnimalMerges(id)
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to get animal merges")
+		}
+
+		return c.JSON(merges)
+	}
+}
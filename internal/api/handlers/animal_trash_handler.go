@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"time"
+	"vetblock/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Lista os animais excluídos que ainda estão na lixeira
+func GetAnimalTrashHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		animals, err := animal_service.ListDeletedAnimals()
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to list deleted animals")
+		}
+
+		return c.JSON(animals)
+	}
+}
+
+// Restaura um animal da lixeira
+func RestoreAnimalHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		animal, err := animal_service.RestoreAnimal(id, currentUserID(c))
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to restore animal")
+		}
+
+		return c.JSON(animal)
+	}
+}
+
+// Remove definitivamente os animais que estão na lixeira há mais tempo que o período de retenção
+func PurgeAnimalTrashHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ids, err := animal_service.PurgeAnimalTrash(service.AnimalTrashRetention(), time.Now(), currentUserID(c))
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to purge deleted animals")
+		}
+
+		return c.JSON(fiber.Map{
+			"purged": len(ids),
+			"ids":    ids,
+		})
+	}
+}
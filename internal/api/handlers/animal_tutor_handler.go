@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type AnimalTutorRequest struct {
+	Role string `json:"role"` // co_owner, emergency_contact ou authorized_pickup
+}
+
+// Lista os tutores do animal: o tutor principal e os demais, com seus papéis
+func GetAnimalTutorsHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		tutors, err := animal_service.ListAnimalTutors(id)
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to get animal tutors")
+		}
+		return c.JSON(tutors)
+	}
+}
+
+// Vincula um tutor ao animal com um papel, ou altera o papel de um tutor já vinculado
+func SetAnimalTutorHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		var request AnimalTutorRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		link, err := animal_service.SetAnimalTutor(id, CleanCpf(c.Params("cpf")), request.Role, currentUserID(c))
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to set animal tutor")
+		}
+		return c.JSON(link)
+	}
+}
+
+// Desvincula do animal um tutor que não é o tutor principal
+func RemoveAnimalTutorHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		if err := animal_service.RemoveAnimalTutor(id, CleanCpf(c.Params("cpf")), currentUserID(c)); err != nil {
+			return animalErrorResponse(c, err, "Failed to remove animal tutor")
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"firebase.google.com/go/v4/auth"
 	"github.com/gofiber/fiber/v2"
 	"google.golang.org/api/option"
+	"gorm.io/gorm"
 )
 
 type LoginRequest struct {
@@ -30,6 +32,23 @@ type LoginResponse struct {
 
 var firebaseAuth *auth.Client
 
+// RegisterAddress aceita o endereço estruturado ou, de clientes anteriores ao
+// endereço estruturado, o endereço em texto livre, gravado em AddressText para a
+// migração dos endereços antigos
+type RegisterAddress struct {
+	model.Address
+	Text string
+}
+
+func (a *RegisterAddress) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		a.Text = strings.TrimSpace(text)
+		return nil
+	}
+	return json.Unmarshal(data, &a.Address)
+}
+
 func init() {
 	// Inicializando o Firebase Admin SDK
 	opt := option.WithCredentialsFile("vetsys.json")
@@ -48,15 +67,15 @@ func init() {
 
 func RegisterUser(c *fiber.Ctx) error {
 	var req struct {
-		UserType  string `json:"user_type"`
-		Email     string `json:"email"`
-		Password  string `json:"password"`
-		CPF       string `json:"cpf,omitempty"`
-		CRMV      string `json:"crmv,omitempty"`
-		Phone     string `json:"phone"`
-		Name      string `json:"name"`
-		Address   string `json:"address,omitempty"`
-		Specialty string `json:"specialty,omitempty"`
+		UserType  string          `json:"user_type"`
+		Email     string          `json:"email"`
+		Password  string          `json:"password"`
+		CPF       string          `json:"cpf,omitempty"`
+		CRMV      string          `json:"crmv,omitempty"`
+		Phone     string          `json:"phone"`
+		Name      string          `json:"name"`
+		Address   RegisterAddress `json:"address,omitempty"`
+		Specialty string          `json:"specialty,omitempty"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -82,12 +101,14 @@ func RegisterUser(c *fiber.Ctx) error {
 
 	// Criar e salvar o usuário no banco de dados dependendo do tipo
 	if req.UserType == "tutor" {
-		tutor, err := model.NewTutor(req.CPF, req.Name, req.Email, req.Phone, req.Address, req.Password)
+		tutor, err := model.NewTutor(req.CPF, req.Name, req.Email, req.Phone, req.Address.Address, req.Password)
 		if err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 				"message": "Failed to create tutor",
 			})
 		}
+		tutor.AddressText = req.Address.Text
+		tutor.FirebaseUID = &userRecord.UID
 
 		// Salvar tutor no banco de dados
 		dbErr = service.CreateUser(tutor)
@@ -99,6 +120,7 @@ func RegisterUser(c *fiber.Ctx) error {
 				"message": "Failed to create veterinarian",
 			})
 		}
+		vet.FirebaseUID = &userRecord.UID
 
 		// Salvar veterinário no banco de dados
 		dbErr = service.CreateUser(vet)
@@ -202,5 +224,98 @@ func Auth(c *fiber.Ctx) error {
 	}
 
 	fmt.Println("User Info:", decodedToken.UID)
+	c.Locals("uid", decodedToken.UID)
+	if email, ok := decodedToken.Claims["email"].(string); ok {
+		c.Locals("email", email)
+	}
+	admin, _ := decodedToken.Claims["admin"].(bool)
+	c.Locals("admin", admin)
+
+	verifiedEmail := ""
+	if verified, _ := decodedToken.Claims["email_verified"].(bool); verified {
+		verifiedEmail, _ = decodedToken.Claims["email"].(string)
+	}
+
+	// A equipe da clínica é identificada pelas custom claims "admin" e "staff" ou por
+	// um veterinário cadastrado vinculado ao usuário
+	staff, _ := decodedToken.Claims["staff"].(bool)
+	staff = staff || admin
+	if !staff {
+		vet, err := veterinary_service.ResolveVeterinary(context.Background(), decodedToken.UID, verifiedEmail)
+		switch {
+		case err == nil:
+			staff = true
+			c.Locals("crvm", vet.CRVM)
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			log.Printf("Failed to resolve veterinarian: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"message": "Failed to resolve user",
+			})
+		}
+	}
+	c.Locals("staff", staff)
+
+	// Os demais usuários só são identificados se forem tutores
+	if !staff {
+		tutor, err := tutor_service.ResolveTutor(context.Background(), decodedToken.UID, verifiedEmail)
+		switch {
+		case err == nil:
+			c.Locals("tutor_cpf", tutor.CPFTutor)
+		case errors.Is(err, service.ErrTutorInactive):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"message": "Inactive tutor",
+			})
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			log.Printf("Failed to resolve tutor: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"message": "Failed to resolve user",
+			})
+		}
+	}
 	return c.Next()
 }
+
+// RequireAdmin só deixa passar usuários com a custom claim "admin" do Firebase.
+// Deve ser usado depois de Auth.
+func RequireAdmin(c *fiber.Ctx) error {
+	if admin, _ := c.Locals("admin").(bool); !admin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "Forbidden",
+		})
+	}
+	return c.Next()
+}
+
+// RequireStaff só deixa passar a equipe da clínica: usuários com as custom claims
+// "admin" ou "staff" ou vinculados a um veterinário cadastrado. Tutores e usuários
+// sem cadastro são recusados. Deve ser usado depois de Auth.
+func RequireStaff(c *fiber.Ctx) error {
+	if staff, _ := c.Locals("staff").(bool); !staff {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "Forbidden",
+		})
+	}
+	return c.Next()
+}
+
+// RequireTutor só deixa passar usuários identificados como tutores. Deve ser usado depois de Auth.
+func RequireTutor(c *fiber.Ctx) error {
+	if currentTutorCPF(c) == "" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "Forbidden",
+		})
+	}
+	return c.Next()
+}
+
+// currentTutorCPF devolve o CPF do tutor autenticado, ou vazio para a equipe da clínica
+func currentTutorCPF(c *fiber.Ctx) string {
+	cpf, _ := c.Locals("tutor_cpf").(string)
+	return cpf
+}
+
+// currentUserID retorna o UID do Firebase do usuário autenticado na requisição
+func currentUserID(c *fiber.Ctx) string {
+	uid, _ := c.Locals("uid").(string)
+	return uid
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"vetblock/internal/db/model"
+	"vetblock/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Lista o catálogo de espécies e raças, usado para preencher os campos de seleção
+func GetSpeciesCatalogHandler(catalogService *service.CatalogService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		species, err := catalogService.ListSpecies(context.Background())
+		if err != nil {
+			return catalogErrorResponse(c, err, "Failed to list species")
+		}
+		return c.JSON(species)
+	}
+}
+
+// Cadastra uma nova espécie no catálogo (somente administradores)
+func CreateSpeciesHandler(catalogService *service.CatalogService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var species model.Species
+		if err := c.BodyParser(&species); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		if err := catalogService.CreateSpecies(context.Background(), &species); err != nil {
+			return catalogErrorResponse(c, err, "Failed to create species")
+		}
+		return c.Status(fiber.StatusCreated).JSON(species)
+	}
+}
+
+// Altera uma espécie do catálogo (somente administradores)
+func UpdateSpeciesHandler(catalogService *service.CatalogService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+		var input model.Species
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		species, err := catalogService.UpdateSpecies(context.Background(), id, input)
+		if err != nil {
+			return catalogErrorResponse(c, err, "Failed to update species")
+		}
+		return c.JSON(species)
+	}
+}
+
+// Remove uma espécie e suas raças do catálogo (somente administradores)
+func DeleteSpeciesHandler(catalogService *service.CatalogService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		if err := catalogService.DeleteSpecies(context.Background(), id); err != nil {
+			return catalogErrorResponse(c, err, "Failed to delete species")
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// Cadastra uma raça para a espécie (somente administradores)
+func CreateBreedHandler(catalogService *service.CatalogService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		speciesID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+		var breed model.Breed
+		if err := c.BodyParser(&breed); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		if err := catalogService.AddBreed(context.Background(), speciesID, &breed); err != nil {
+			return catalogErrorResponse(c, err, "Failed to create breed")
+		}
+		return c.Status(fiber.StatusCreated).JSON(breed)
+	}
+}
+
+// Altera uma raça do catálogo (somente administradores)
+func UpdateBreedHandler(catalogService *service.CatalogService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+		var input model.Breed
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		breed, err := catalogService.UpdateBreed(context.Background(), id, input)
+		if err != nil {
+			return catalogErrorResponse(c, err, "Failed to update breed")
+		}
+		return c.JSON(breed)
+	}
+}
+
+// Remove uma raça do catálogo (somente administradores)
+func DeleteBreedHandler(catalogService *service.CatalogService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		if err := catalogService.DeleteBreed(context.Background(), id); err != nil {
+			return catalogErrorResponse(c, err, "Failed to delete breed")
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func catalogErrorResponse(c *fiber.Ctx, err error, message string) error {
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": validationErrorMessages(validationErrs),
+		})
+	case errors.Is(err, service.ErrCatalogConflict):
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).SendString("Catalog entry not found")
+	default:
+		log.Printf("%s: %v", message, err)
+		return c.Status(fiber.StatusInternalServerError).SendString(message)
+	}
+}
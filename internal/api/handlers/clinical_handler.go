@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"vetblock/internal/db/model"
+	"vetblock/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Registra uma alergia do animal
+func AddAllergyHandler(clinicalService *service.ClinicalRecordService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		animalID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		// Verifica se o animal existe
+		if _, err := animal_service.GetAnimalByID(animalID); err != nil {
+			return animalErrorResponse(c, err, "Error retrieving animal")
+		}
+
+		var allergy model.Allergy
+		if err := c.BodyParser(&allergy); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+		allergy.AnimalID = animalID
+		allergy.RecordedBy = currentUserID(c)
+
+		if err := clinicalService.AddAllergy(context.Background(), &allergy); err != nil {
+			return clinicalErrorResponse(c, err, "Failed to add allergy")
+		}
+		return c.Status(fiber.StatusCreated).JSON(allergy)
+	}
+}
+
+// Lista as alergias do animal
+func GetAllergiesHandler(clinicalService *service.ClinicalRecordService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		animalID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		allergies, err := clinicalService.ListAllergies(context.Background(), animalID)
+		if err != nil {
+			return clinicalErrorResponse(c, err, "Failed to get allergies")
+		}
+		return c.JSON(allergies)
+	}
+}
+
+// Remove uma alergia do animal
+func DeleteAllergyHandler(clinicalService *service.ClinicalRecordService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		animalID, recordID, err := parseClinicalRecordIDs(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		if err := clinicalService.RemoveAllergy(context.Background(), animalID, recordID); err != nil {
+			return clinicalErrorResponse(c, err, "Failed to delete allergy")
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// Registra uma condição crônica do animal
+func AddConditionHandler(clinicalService *service.ClinicalRecordService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		animalID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		// Verifica se o animal existe
+		if _, err := animal_service.GetAnimalByID(animalID); err != nil {
+			return animalErrorResponse(c, err, "Error retrieving animal")
+		}
+
+		var condition model.ChronicCondition
+		if err := c.BodyParser(&condition); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+		condition.AnimalID = animalID
+		condition.RecordedBy = currentUserID(c)
+
+		if err := clinicalService.AddCondition(context.Background(), &condition); err != nil {
+			return clinicalErrorResponse(c, err, "Failed to add chronic condition")
+		}
+		return c.Status(fiber.StatusCreated).JSON(condition)
+	}
+}
+
+// Lista as condições crônicas do animal
+func GetConditionsHandler(clinicalService *service.ClinicalRecordService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		animalID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		conditions, err := clinicalService.ListConditions(context.Background(), animalID)
+		if err != nil {
+			return clinicalErrorResponse(c, err, "Failed to get chronic conditions")
+		}
+		return c.JSON(conditions)
+	}
+}
+
+// Remove uma condição crônica do animal
+func DeleteConditionHandler(clinicalService *service.ClinicalRecordService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		animalID, recordID, err := parseClinicalRecordIDs(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		if err := clinicalService.RemoveCondition(context.Background(), animalID, recordID); err != nil {
+			return clinicalErrorResponse(c, err, "Failed to delete chronic condition")
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func parseClinicalRecordIDs(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	animalID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	recordID, err := uuid.Parse(c.Params("record_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return animalID, recordID, nil
+}
+
+func clinicalErrorResponse(c *fiber.Ctx, err error, message string) error {
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": validationErrorMessages(validationErrs),
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).SendString("Record not found")
+	default:
+		log.Printf("%s: %v", message, err)
+		return c.Status(fiber.StatusInternalServerError).SendString(message)
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type ConsentRequest struct {
+	Source string `json:"source"`
+}
+
+// Retorna a situação atual dos consentimentos do tutor e o histórico
+func GetConsentsHandler(consentService *service.ConsentService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		state, err := consentService.GetConsents(context.Background(), consentTutorCPF(c))
+		if err != nil {
+			return consentErrorResponse(c, err, "Failed to get consents")
+		}
+		return c.JSON(state)
+	}
+}
+
+// Registra o consentimento do tutor para o canal e a finalidade da rota. Pela
+// clínica, o corpo informa a origem; pelo portal, a origem é sempre "portal".
+func GrantConsentHandler(consentService *service.ConsentService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		source, err := consentSource(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		consent, err := consentService.GrantConsent(context.Background(), consentTutorCPF(c), c.Params("channel"), c.Params("purpose"), source, currentUserID(c), time.Now())
+		if err != nil {
+			return consentErrorResponse(c, err, "Failed to grant consent")
+		}
+		return c.JSON(consent)
+	}
+}
+
+// Revoga o consentimento em vigor para o canal e a finalidade da rota
+func RevokeConsentHandler(consentService *service.ConsentService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		source, err := consentSource(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		consent, err := consentService.RevokeConsent(context.Background(), consentTutorCPF(c), c.Params("channel"), c.Params("purpose"), source, currentUserID(c), time.Now())
+		if err != nil {
+			return consentErrorResponse(c, err, "Failed to revoke consent")
+		}
+		return c.JSON(consent)
+	}
+}
+
+// consentTutorCPF usa o tutor autenticado no portal ou, nas rotas da clínica, o CPF da rota
+func consentTutorCPF(c *fiber.Ctx) string {
+	if cpf := currentTutorCPF(c); cpf != "" {
+		return cpf
+	}
+	return CleanCpf(c.Params("cpf"))
+}
+
+func consentSource(c *fiber.Ctx) (string, error) {
+	if currentTutorCPF(c) != "" {
+		return model.ConsentSourcePortal, nil
+	}
+	var input ConsentRequest
+	if err := c.BodyParser(&input); err != nil {
+		return "", err
+	}
+	return input.Source, nil
+}
+
+func consentErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidConsent):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, service.ErrNoConsent):
+		return c.Status(fiber.StatusNotFound).SendString(err.Error())
+	case errors.Is(err, service.ErrTutorAnonymized):
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).SendString("Tutor not found")
+	default:
+		log.Printf("%s: %v", message, err)
+		return c.Status(fiber.StatusInternalServerError).SendString(message)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // Defina o validador global
@@ -28,6 +30,7 @@ type ConsultationRequest struct {
     Consultation_Status string    `json:"consultation_status"`
     Observation      string    `json:"observation"`
     Consultation_Price float64    `json:"consultation_price"`
+    AuthorizedBy     string    `json:"authorized_by"` // CPF do tutor principal que autorizou o procedimento
 }
 
 func AddConsultationHandler(repo repository.ConsultationRepository) fiber.Handler {
@@ -66,7 +69,7 @@ func AddConsultationHandler(repo repository.ConsultationRepository) fiber.Handle
         consultationModel := model.Consultation{
             ID:               consultation.ID,
             AnimalID:         consultation.AnimalID,
-            CRVM:             consultation.VeterinaryCRVM,
+            CRVM:             model.CleanCRMV(consultation.VeterinaryCRVM),
             ConsultationDate: model.CustomDate{Time: parsedDate},
             Reason:           consultation.Reason,
             Observation:      consultation.Observation,
@@ -75,15 +78,7 @@ func AddConsultationHandler(repo repository.ConsultationRepository) fiber.Handle
             ConsultationPrescription: consultation.Consultation_Prescription,
             ConsultationStatus:     consultation.Consultation_Status,
             ConsultationPrice:      consultation.Consultation_Price,
-        }
-
-        // Função para buscar o veterinário pelo CRVM
-        getVeterinaryByCRVM := func(crvm string) (*model.Veterinary, error) {
-            vet, err := service.GetVeterinaryByCRVM(crvm)
-            if err != nil {
-                return nil, err
-            }
-            return vet, nil
+            AuthorizedBy:           CleanCpf(consultation.AuthorizedBy),
         }
 
         // Função para buscar o animal pelo ID
@@ -95,8 +90,25 @@ func AddConsultationHandler(repo repository.ConsultationRepository) fiber.Handle
             return animal, nil
         }
 
+        // A inscrição do veterinário precisa estar ativa na data da consulta
+        getLicensedVeterinary := func(crvm string) (*model.Veterinary, error) {
+            return veterinary_service.FindVeterinaryLicensedOn(crvm, parsedDate)
+        }
+
         // Chame a função AddConsultation com todos os parâmetros necessários
-        err = service.AddConsultation(repo, &consultationModel, getVeterinaryByCRVM, getAnimalByID)
+        err = service.AddConsultation(repo, &consultationModel, getLicensedVeterinary, getAnimalByID, schedule_service.CheckAvailability)
+        if errors.Is(err, service.ErrUnknownVeterinarian) || errors.Is(err, service.ErrConsultationNotScheduled) {
+            return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+        }
+        if errors.Is(err, service.ErrOutsideWorkingHours) || errors.Is(err, service.ErrVeterinaryUnavailable) || errors.Is(err, service.ErrNoActiveLicense) {
+            return c.Status(fiber.StatusConflict).SendString(err.Error())
+        }
+        if errors.Is(err, service.ErrAnimalDeceased) {
+            return c.Status(fiber.StatusConflict).SendString(err.Error())
+        }
+        if errors.Is(err, service.ErrNotPrimaryOwner) {
+            return c.Status(fiber.StatusForbidden).SendString(err.Error())
+        }
         if err != nil {
             return c.Status(fiber.StatusInternalServerError).SendString("Failed to add consultation transaction")
         }
@@ -107,16 +119,52 @@ func AddConsultationHandler(repo repository.ConsultationRepository) fiber.Handle
     }
 }
 
+type ConsultationStatusRequest struct {
+    Status string `json:"consultation_status"`
+}
+
+// Altera a situação da consulta: scheduled, completed, canceled ou no_show (falta).
+// clinicLocation é o fuso em que os horários das consultas foram agendados.
+func UpdateConsultationStatusHandler(repo repository.ConsultationRepository, clinicLocation *time.Location) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        id, err := uuid.Parse(c.Params("id"))
+        if err != nil {
+            return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+        }
+        var request ConsultationStatusRequest
+        if err := c.BodyParser(&request); err != nil {
+            return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+        }
+
+        consultation, err := service.UpdateConsultationStatus(repo, id, request.Status, time.Now().In(clinicLocation))
+        if errors.Is(err, service.ErrInvalidConsultationStatus) {
+            return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+        }
+        if errors.Is(err, service.ErrConsultationNotStarted) || errors.Is(err, service.ErrConsultationNotReopenable) {
+            return c.Status(fiber.StatusConflict).SendString(err.Error())
+        }
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return c.Status(fiber.StatusNotFound).SendString("Consultation not found")
+        }
+        if err != nil {
+            return c.Status(fiber.StatusInternalServerError).SendString("Failed to update consultation status")
+        }
+
+        return c.Status(fiber.StatusOK).JSON(consultation)
+    }
+}
+
 //all consultations by vet 
 func GetAllConsultationsByVeterinaryHandler(repo repository.ConsultationRepository) fiber.Handler {
     return func(c *fiber.Ctx) error {
-        crvm := c.Params("crvm")
+        crvm := model.CleanCRMV(c.Params("crvm"))
         consultations, err := service.GetAllConsultationsByVeterinary(repo, crvm)
         if err != nil {
             return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
                 "message": err.Error(),
             })
         }
+        service.AnnotateAnimalAge(consultations, animal_service.GetAnimalByID)
 
         return c.Status(fiber.StatusOK).JSON(consultations)
     }
@@ -125,7 +173,7 @@ func GetAllConsultationsByVeterinaryHandler(repo repository.ConsultationReposito
 //get next vet(using crvm) consultation
 func GetNextConsultationHandler(repo repository.ConsultationRepository) fiber.Handler {
     return func(c *fiber.Ctx) error {
-        crvm := c.Params("crvm")
+        crvm := model.CleanCRMV(c.Params("crvm"))
         log.Println("CRVM: ", crvm)
         consultation, err := service.GetNextConsultationByVeterinaryCRVM(repo, crvm)
         if err != nil {
@@ -133,6 +181,9 @@ func GetNextConsultationHandler(repo repository.ConsultationRepository) fiber.Ha
                 "message": err.Error(),
             })
         }
+        if animal, err := animal_service.GetAnimalByID(consultation.AnimalID); err == nil {
+            consultation.AnimalAge = animal.AgeAt(consultation.ConsultationDate.Time)
+        }
 
         return c.Status(fiber.StatusOK).JSON(consultation)
     }
@@ -151,6 +202,7 @@ func GetConsultsByAnimalIDHandler(repo repository.ConsultationRepository) fiber.
                 "message": err.Error(),
             })
         }
+        service.AnnotateAnimalAge(consultations, animal_service.GetAnimalByID)
 
         return c.Status(fiber.StatusOK).JSON(consultations)
     }
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"time"
+	"vetblock/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Importa tutores e animais de um CSV. O arquivo pode vir no campo "file" de um
+// formulário multipart ou como corpo da requisição (text/csv). Query params
+// opcionais: dry_run=true, para apenas gerar o relatório, e mapping, um JSON que
+// associa colunas do arquivo aos campos (ex.: {"Dono":"tutor_name"}).
+func ImportCSVHandler(importService *service.ImportService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		options := service.ImportOptions{DryRun: c.QueryBool("dry_run")}
+
+		mapping := c.Query("mapping")
+		if mapping == "" {
+			mapping = c.FormValue("mapping")
+		}
+		if mapping != "" {
+			if err := json.Unmarshal([]byte(mapping), &options.Mapping); err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid mapping")
+			}
+		}
+
+		var file io.Reader = bytes.NewReader(c.Body())
+		if header, err := c.FormFile("file"); err == nil {
+			opened, err := header.Open()
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid file")
+			}
+			defer opened.Close()
+			file = opened
+		}
+
+		report, err := importService.ImportCSV(context.Background(), file, options, time.Now())
+		if err != nil {
+			if errors.Is(err, service.ErrEmptyImport) || errors.Is(err, service.ErrImportTooLarge) ||
+				errors.Is(err, service.ErrMissingImportColumn) || errors.Is(err, service.ErrUnknownImportField) {
+				return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+			}
+			log.Printf("Failed to import CSV: %v", err)
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to import CSV")
+		}
+
+		status := fiber.StatusOK
+		if !options.DryRun && report.Created > 0 {
+			status = fiber.StatusCreated
+		}
+		return c.Status(status).JSON(report)
+	}
+}
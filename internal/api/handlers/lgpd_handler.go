@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"vetblock/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Exporta os dados pessoais do tutor e dos seus animais em um arquivo zip (somente administradores)
+func ExportTutorDataHandler(lgpdService *service.LGPDService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return sendTutorExport(c, lgpdService, CleanCpf(c.Params("cpf")))
+	}
+}
+
+// Exporta os dados do tutor autenticado (portal do tutor)
+func ExportMyDataHandler(lgpdService *service.LGPDService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return sendTutorExport(c, lgpdService, currentTutorCPF(c))
+	}
+}
+
+// Anonimiza os dados pessoais do tutor e remove o seu usuário do Firebase (somente administradores)
+func AnonymizeTutorHandler(lgpdService *service.LGPDService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		result, err := lgpdService.AnonymizeTutor(context.Background(), CleanCpf(c.Params("cpf")), currentUserID(c), time.Now())
+		if err != nil {
+			return lgpdErrorResponse(c, err, "Failed to anonymize tutor")
+		}
+
+		if result.FirebaseUID != "" {
+			if err := firebaseAuth.DeleteUser(context.Background(), result.FirebaseUID); err != nil {
+				log.Printf("Failed to delete Firebase user of anonymized tutor %s: %v", result.Pseudonym, err)
+			}
+		}
+		return c.JSON(result)
+	}
+}
+
+func sendTutorExport(c *fiber.Ctx, lgpdService *service.LGPDService, cpf string) error {
+	var archive bytes.Buffer
+	if err := lgpdService.ExportTutorData(context.Background(), cpf, currentUserID(c), &archive, time.Now()); err != nil {
+		return lgpdErrorResponse(c, err, "Failed to export tutor data")
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="tutor-%s.zip"`, cpf))
+	return c.Send(archive.Bytes())
+}
+
+func lgpdErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).SendString("Tutor not found")
+	case errors.Is(err, service.ErrTutorAnonymized), errors.Is(err, service.ErrNotNaturalPerson):
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	default:
+		log.Printf("%s: %v", message, err)
+		return c.Status(fiber.StatusInternalServerError).SendString(message)
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+	"vetblock/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type OwnershipTransferRequest struct {
+	NewCPFTutor   string `json:"new_cpf_tutor"`
+	EffectiveDate string `json:"effective_date"` // 2006-01-02; padrão: hoje
+	Reason        string `json:"reason"`
+}
+
+// Handler para transferir um animal para outro tutor
+func TransferAnimalHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		var request OwnershipTransferRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		effectiveDate := time.Now().Truncate(24 * time.Hour)
+		if request.EffectiveDate != "" {
+			effectiveDate, err = time.Parse("2006-01-02", request.EffectiveDate)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid date format")
+			}
+		}
+
+		transfer, err := animal_service.TransferOwnership(id, CleanCpf(request.NewCPFTutor), effectiveDate, request.Reason, currentUserID(c))
+		if err != nil {
+			return ownershipErrorResponse(c, err)
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(transfer)
+	}
+}
+
+// Handler para listar os tutores atuais e anteriores de um animal
+func GetAnimalOwnersHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		periods, err := animal_service.GetOwnershipPeriods(id)
+		if err != nil {
+			return animalErrorResponse(c, err, "Failed to get animal owners")
+		}
+
+		return c.Status(fiber.StatusOK).JSON(periods)
+	}
+}
+
+// ownershipErrorResponse trata como 400 as regras de negócio violadas na transferência
+func ownershipErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidCPF),
+		errors.Is(err, service.ErrSameTutor),
+		errors.Is(err, service.ErrFutureEffectiveDate),
+		errors.Is(err, service.ErrEffectiveDateOutdated):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+	return animalErrorResponse(c, err, "Failed to transfer animal")
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WorkingHoursRequest struct {
+	Weekday   int    `json:"weekday"` // 0 = domingo ... 6 = sábado
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+type AvailabilityExceptionRequest struct {
+	StartDate string `json:"start_date"` // 2006-01-02
+	EndDate   string `json:"end_date"`   // 2006-01-02; padrão: a data de início
+	StartTime string `json:"start_time"` // Opcional: sem horário, vale para os dias inteiros
+	EndTime   string `json:"end_time"`
+	Reason    string `json:"reason"`
+	Notes     string `json:"notes"`
+}
+
+// Retorna os horários de atendimento semanais do veterinário
+func GetWorkingHoursHandler(scheduleService *service.ScheduleService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		hours, err := scheduleService.GetWorkingHours(context.Background(), c.Params("crvm"))
+		if err != nil {
+			return scheduleErrorResponse(c, err, "Failed to get working hours")
+		}
+		return c.JSON(hours)
+	}
+}
+
+// Substitui os horários de atendimento semanais do veterinário pela lista enviada
+func SetWorkingHoursHandler(scheduleService *service.ScheduleService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var request []WorkingHoursRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		hours := make([]model.WorkingHours, 0, len(request))
+		for _, h := range request {
+			hours = append(hours, model.WorkingHours{Weekday: h.Weekday, StartTime: h.StartTime, EndTime: h.EndTime})
+		}
+		saved, err := scheduleService.SetWorkingHours(context.Background(), c.Params("crvm"), hours)
+		if err != nil {
+			return scheduleErrorResponse(c, err, "Failed to set working hours")
+		}
+		return c.JSON(saved)
+	}
+}
+
+// Lista as ausências do veterinário entre from e to (2006-01-02). Padrão: de hoje
+// até 90 dias depois.
+func GetAvailabilityExceptionsHandler(scheduleService *service.ScheduleService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		from := time.Now().Truncate(24 * time.Hour)
+		if c.Query("from") != "" {
+			parsed, err := time.Parse("2006-01-02", c.Query("from"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid date format")
+			}
+			from = parsed
+		}
+		to := from.AddDate(0, 0, 90)
+		if c.Query("to") != "" {
+			parsed, err := time.Parse("2006-01-02", c.Query("to"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid date format")
+			}
+			to = parsed
+		}
+
+		exceptions, err := scheduleService.ListExceptions(context.Background(), c.Params("crvm"), from, to)
+		if err != nil {
+			return scheduleErrorResponse(c, err, "Failed to get availability exceptions")
+		}
+		return c.JSON(exceptions)
+	}
+}
+
+// Registra uma ausência do veterinário: férias, curso, atestado ou outro motivo
+func AddAvailabilityExceptionHandler(scheduleService *service.ScheduleService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var request AvailabilityExceptionRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+		startDate, err := time.Parse("2006-01-02", request.StartDate)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid date format")
+		}
+		endDate := startDate
+		if request.EndDate != "" {
+			if endDate, err = time.Parse("2006-01-02", request.EndDate); err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid date format")
+			}
+		}
+
+		exception, err := scheduleService.AddException(context.Background(), c.Params("crvm"), model.AvailabilityException{
+			StartDate: model.CustomDate{Time: startDate},
+			EndDate:   model.CustomDate{Time: endDate},
+			StartTime: request.StartTime,
+			EndTime:   request.EndTime,
+			Reason:    request.Reason,
+			Notes:     request.Notes,
+		}, currentUserID(c))
+		if err != nil {
+			return scheduleErrorResponse(c, err, "Failed to add availability exception")
+		}
+		return c.Status(fiber.StatusCreated).JSON(exception)
+	}
+}
+
+// Remove uma ausência do veterinário
+func DeleteAvailabilityExceptionHandler(scheduleService *service.ScheduleService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+		if err := scheduleService.DeleteException(context.Background(), c.Params("crvm"), id); err != nil {
+			return scheduleErrorResponse(c, err, "Failed to delete availability exception")
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// Retorna os horários em que o veterinário atende na data (query param date,
+// 2006-01-02; padrão: hoje), descontadas as ausências
+func GetDayAvailabilityHandler(scheduleService *service.ScheduleService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		date := time.Now().Truncate(24 * time.Hour)
+		if c.Query("date") != "" {
+			parsed, err := time.Parse("2006-01-02", c.Query("date"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid date format")
+			}
+			date = parsed
+		}
+
+		availability, err := scheduleService.GetDayAvailability(context.Background(), c.Params("crvm"), date)
+		if err != nil {
+			return scheduleErrorResponse(c, err, "Failed to get availability")
+		}
+		return c.JSON(availability)
+	}
+}
+
+func scheduleErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidWorkingHours), errors.Is(err, service.ErrOverlappingWorkingHours),
+		errors.Is(err, service.ErrInvalidException):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, service.ErrExceptionNotFound):
+		return c.Status(fiber.StatusNotFound).SendString(err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).SendString("Veterinary not found")
+	default:
+		log.Printf("%s: %v", message, err)
+		return c.Status(fiber.StatusInternalServerError).SendString(message)
+	}
+}
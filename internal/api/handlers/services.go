@@ -0,0 +1,21 @@
+package handlers
+
+import "vetblock/internal/service"
+
+// Serviços compartilhados pelos handlers sem parâmetros e pelo middleware Auth,
+// configurados por SetupServices
+var (
+	tutor_service      *service.TutorService
+	animal_service     *service.AnimalService
+	veterinary_service *service.VeterinaryService // Confere o veterinário das consultas e dos óbitos
+	schedule_service   *service.ScheduleService   // Confere a disponibilidade do veterinário nas consultas
+)
+
+// SetupServices configura os serviços usados pelos handlers sem parâmetros e pelo
+// middleware Auth. Deve ser chamado antes de registrar as rotas.
+func SetupServices(animals *service.AnimalService, tutors *service.TutorService, veterinaries *service.VeterinaryService, schedule *service.ScheduleService) {
+	animal_service = animals
+	tutor_service = tutors
+	veterinary_service = veterinaries
+	schedule_service = schedule
+}
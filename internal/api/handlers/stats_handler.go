@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"vetblock/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Retorna os indicadores do painel do veterinário entre from e to (2006-01-02).
+// Padrão: os últimos 30 dias, até hoje.
+func GetVeterinaryStatsHandler(statsService *service.VeterinaryStatsService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		to := time.Now()
+		if c.Query("to") != "" {
+			parsed, err := time.Parse("2006-01-02", c.Query("to"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid date format")
+			}
+			to = parsed
+		}
+		from := to.AddDate(0, 0, -(service.DefaultStatsDays - 1))
+		if c.Query("from") != "" {
+			parsed, err := time.Parse("2006-01-02", c.Query("from"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid date format")
+			}
+			from = parsed
+		}
+
+		stats, err := statsService.GetVeterinaryStats(context.Background(), c.Params("crvm"), from, to)
+		if err != nil {
+			return statsErrorResponse(c, err, "Failed to get veterinary stats")
+		}
+		return c.JSON(stats)
+	}
+}
+
+func statsErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidStatsRange):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).SendString("Veterinary not found")
+	default:
+		log.Printf("%s: %v", message, err)
+		return c.Status(fiber.StatusInternalServerError).SendString(message)
+	}
+}
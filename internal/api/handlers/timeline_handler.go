@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"vetblock/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Linha do tempo clínica do animal. Query params opcionais: types (lista
+// separada por vírgulas: consultation, dosage, hospitalization, weight, image),
+// cursor e limit.
+func GetAnimalTimelineHandler(timelineService *service.TimelineService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		animalID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		if _, err := animal_service.GetAnimalByID(animalID); err != nil {
+			return animalErrorResponse(c, err, "Error retrieving animal")
+		}
+
+		query := service.TimelineQuery{
+			Cursor: c.Query("cursor"),
+			Limit:  c.QueryInt("limit"),
+		}
+		if types := c.Query("types"); types != "" {
+			for _, t := range strings.Split(types, ",") {
+				if t = strings.TrimSpace(t); t != "" {
+					query.Types = append(query.Types, t)
+				}
+			}
+		}
+
+		page, err := timelineService.GetTimeline(context.Background(), animalID, query)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidTimelineCursor) || errors.Is(err, service.ErrUnknownTimelineType) {
+				return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+			}
+			log.Printf("Failed to get animal timeline: %v", err)
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to get animal timeline")
+		}
+
+		return c.JSON(page)
+	}
+}
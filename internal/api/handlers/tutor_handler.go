@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TutorRequest struct {
+	CPFTutor string        `json:"cpf_tutor"`
+	Name     string        `json:"name"`
+	Email    string        `json:"email"`
+	Phone    string        `json:"phone"`
+	Address  model.Address `json:"address"`
+}
+
+// Cadastra um tutor
+func CreateTutorHandler(tutorService *service.TutorService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input TutorRequest
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		tutor, err := tutorService.CreateTutor(context.Background(), input.CPFTutor, input.Name, input.Email, input.Phone, input.Address)
+		if err != nil {
+			return tutorErrorResponse(c, err, "Failed to create tutor")
+		}
+		return c.Status(fiber.StatusCreated).JSON(tutor)
+	}
+}
+
+// Busca tutores por nome, telefone ou e-mail. Query params: name, phone, email,
+// owner_type (person ou organization), uf, city, neighborhood, include_inactive=true
+// para incluir desativados e limit.
+func SearchTutorsHandler(tutorService *service.TutorService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tutors, err := tutorService.SearchTutors(context.Background(), repository.TutorQuery{
+			Name:            c.Query("name"),
+			Phone:           c.Query("phone"),
+			Email:           c.Query("email"),
+			OwnerType:       c.Query("owner_type"),
+			UF:              strings.ToUpper(c.Query("uf")),
+			City:            c.Query("city"),
+			Neighborhood:    c.Query("neighborhood"),
+			IncludeInactive: c.QueryBool("include_inactive"),
+			Limit:           c.QueryInt("limit"),
+		})
+		if err != nil {
+			return tutorErrorResponse(c, err, "Failed to search tutors")
+		}
+		return c.JSON(tutors)
+	}
+}
+
+// Retorna o tutor pelo CPF, inclusive desativado
+func GetTutorHandler(tutorService *service.TutorService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tutor, err := tutorService.GetTutor(context.Background(), CleanCpf(c.Params("cpf")))
+		if err != nil {
+			return tutorErrorResponse(c, err, "Failed to get tutor")
+		}
+		return c.JSON(tutor)
+	}
+}
+
+// Altera nome, e-mail, telefone ou endereço do tutor; campos ausentes não são alterados
+func UpdateTutorHandler(tutorService *service.TutorService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var patch service.TutorPatch
+		if err := c.BodyParser(&patch); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		tutor, err := tutorService.UpdateTutor(context.Background(), CleanCpf(c.Params("cpf")), patch)
+		if err != nil {
+			return tutorErrorResponse(c, err, "Failed to update tutor")
+		}
+		return c.JSON(tutor)
+	}
+}
+
+// Desativa o tutor. O cadastro é mantido, mas ele deixa de receber novos animais.
+func DeactivateTutorHandler(tutorService *service.TutorService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tutor, err := tutorService.SetTutorActive(context.Background(), CleanCpf(c.Params("cpf")), false)
+		if err != nil {
+			return tutorErrorResponse(c, err, "Failed to deactivate tutor")
+		}
+		return c.JSON(tutor)
+	}
+}
+
+// Reativa um tutor desativado
+func ReactivateTutorHandler(tutorService *service.TutorService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tutor, err := tutorService.SetTutorActive(context.Background(), CleanCpf(c.Params("cpf")), true)
+		if err != nil {
+			return tutorErrorResponse(c, err, "Failed to reactivate tutor")
+		}
+		return c.JSON(tutor)
+	}
+}
+
+// Lista os animais em que o tutor tem qualquer papel, ou apenas o papel informado em
+// tutor_role, com a mesma paginação da listagem de animais (cursor e limit)
+func GetTutorAnimalsHandler(tutorService *service.TutorService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tutor, err := tutorService.GetTutor(context.Background(), CleanCpf(c.Params("cpf")))
+		if err != nil {
+			return tutorErrorResponse(c, err, "Failed to get tutor")
+		}
+
+		page, err := animal_service.ListAnimals(repository.AnimalQuery{
+			CPFTutor:  tutor.CPFTutor,
+			TutorRole: c.Query("tutor_role"),
+			Cursor:    c.Query("cursor"),
+			Limit:     c.QueryInt("limit"),
+		})
+		if err != nil {
+			if errors.Is(err, repository.ErrInvalidCursor) || errors.Is(err, service.ErrInvalidTutorRole) {
+				return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+			}
+			log.Printf("Failed to get tutor animals: %v", err)
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to get tutor animals")
+		}
+		return c.JSON(page)
+	}
+}
+
+// Lista os representantes legais de um tutor organização
+func GetLegalRepresentativesHandler(tutorService *service.TutorService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		representatives, err := tutorService.ListLegalRepresentatives(context.Background(), CleanCpf(c.Params("cpf")))
+		if err != nil {
+			return tutorErrorResponse(c, err, "Failed to get legal representatives")
+		}
+		return c.JSON(representatives)
+	}
+}
+
+// Cadastra um representante legal de um tutor organização
+func AddLegalRepresentativeHandler(tutorService *service.TutorService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input model.LegalRepresentative
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		representative, err := tutorService.AddLegalRepresentative(context.Background(), CleanCpf(c.Params("cpf")), input)
+		if err != nil {
+			return tutorErrorResponse(c, err, "Failed to add legal representative")
+		}
+		return c.Status(fiber.StatusCreated).JSON(representative)
+	}
+}
+
+// Remove um representante legal de um tutor organização
+func RemoveLegalRepresentativeHandler(tutorService *service.TutorService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		if err := tutorService.RemoveLegalRepresentative(context.Background(), CleanCpf(c.Params("cpf")), id); err != nil {
+			return tutorErrorResponse(c, err, "Failed to remove legal representative")
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// Conta os tutores ativos por UF, cidade e bairro. Query params opcionais: uf e city.
+func GetTutorRegionsHandler(tutorService *service.TutorService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		regions, err := tutorService.TutorRegions(context.Background(), c.Query("uf"), c.Query("city"))
+		if err != nil {
+			return tutorErrorResponse(c, err, "Failed to count tutors by region")
+		}
+		return c.JSON(regions)
+	}
+}
+
+// Busca o endereço de um CEP na tabela local de CEPs
+func LookupCEPHandler(tutorService *service.TutorService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		address, err := tutorService.LookupCEP(c.Params("cep"))
+		if err != nil {
+			return tutorErrorResponse(c, err, "Failed to look up CEP")
+		}
+		return c.JSON(address)
+	}
+}
+
+// Converte os endereços em texto livre dos cadastros antigos em endereços
+// estruturados (somente administradores). Com dry_run=true, apenas gera o relatório.
+func MigrateLegacyAddressesHandler(tutorService *service.TutorService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		report, err := tutorService.MigrateLegacyAddresses(context.Background(), c.QueryBool("dry_run"))
+		if err != nil {
+			return tutorErrorResponse(c, err, "Failed to migrate legacy addresses")
+		}
+		return c.JSON(report)
+	}
+}
+
+func tutorErrorResponse(c *fiber.Ctx, err error, message string) error {
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": validationErrorMessages(validationErrs),
+		})
+	case errors.Is(err, service.ErrInvalidCPF), errors.Is(err, service.ErrInvalidOwnerType),
+		errors.Is(err, service.ErrNotOrganization), errors.Is(err, service.ErrRepresentativeContact),
+		errors.Is(err, service.ErrInvalidCEP), errors.Is(err, service.ErrInvalidUF),
+		errors.Is(err, service.ErrIncompleteAddress), errors.Is(err, service.ErrCEPMismatch):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, service.ErrRepresentativeNotFound), errors.Is(err, service.ErrCEPNotFound):
+		return c.Status(fiber.StatusNotFound).SendString(err.Error())
+	case errors.Is(err, service.ErrTutorExists), errors.Is(err, service.ErrTutorAnonymized):
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).SendString("Tutor not found")
+	default:
+		log.Printf("%s: %v", message, err)
+		return c.Status(fiber.StatusInternalServerError).SendString(message)
+	}
+}
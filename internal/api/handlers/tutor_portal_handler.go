@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"vetblock/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Retorna o cadastro do tutor autenticado
+func GetTutorProfileHandler(portal *service.TutorPortalService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tutor, err := portal.GetProfile(context.Background(), currentTutorCPF(c))
+		if err != nil {
+			return tutorPortalErrorResponse(c, err, "Failed to get tutor")
+		}
+		return c.JSON(tutor)
+	}
+}
+
+// Lista os animais do tutor autenticado (como tutor principal ou co-tutor)
+func GetTutorPetsHandler(portal *service.TutorPortalService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		pets, err := portal.ListPets(context.Background(), currentTutorCPF(c))
+		if err != nil {
+			return tutorPortalErrorResponse(c, err, "Failed to get pets")
+		}
+		return c.JSON(pets)
+	}
+}
+
+// Retorna um animal do tutor autenticado
+func GetTutorPetHandler(portal *service.TutorPortalService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		pet, err := portal.GetPet(context.Background(), currentTutorCPF(c), id)
+		if err != nil {
+			return tutorPortalErrorResponse(c, err, "Failed to get pet")
+		}
+		return c.JSON(pet)
+	}
+}
+
+// Lista as próximas consultas dos animais do tutor; include_past=true inclui o histórico
+func GetTutorConsultationsHandler(portal *service.TutorPortalService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		consultations, err := portal.ListConsultations(context.Background(), currentTutorCPF(c), c.QueryBool("include_past"), time.Now())
+		if err != nil {
+			return tutorPortalErrorResponse(c, err, "Failed to get consultations")
+		}
+		return c.JSON(consultations)
+	}
+}
+
+// Lista as prescrições dos animais do tutor; active=true mostra apenas as que ainda não terminaram
+func GetTutorPrescriptionsHandler(portal *service.TutorPortalService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		prescriptions, err := portal.ListPrescriptions(context.Background(), currentTutorCPF(c), c.QueryBool("active"), time.Now())
+		if err != nil {
+			return tutorPortalErrorResponse(c, err, "Failed to get prescriptions")
+		}
+		return c.JSON(prescriptions)
+	}
+}
+
+// Retorna a carteira de vacinação de um animal do tutor
+func GetTutorPetVaccinationsHandler(portal *service.TutorPortalService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		records, err := portal.GetVaccinationCard(context.Background(), currentTutorCPF(c), id)
+		if err != nil {
+			return tutorPortalErrorResponse(c, err, "Failed to get vaccination card")
+		}
+		return c.JSON(records)
+	}
+}
+
+func tutorPortalErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrAnimalNotOwned):
+		return c.Status(fiber.StatusNotFound).SendString("Animal not found")
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).SendString("Tutor not found")
+	default:
+		log.Printf("%s: %v", message, err)
+		return c.Status(fiber.StatusInternalServerError).SendString(message)
+	}
+}
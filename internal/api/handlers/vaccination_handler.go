@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type VaccinationRequest struct {
+	ProductID      uuid.UUID  `json:"product_id"`
+	MedicationID   *uuid.UUID `json:"medication_id"` // Lote do estoque; quando informado, lot_number vem dele
+	DoseNumber     int        `json:"dose_number"`   // Opcional: por padrão segue a última dose do protocolo
+	LotNumber      string     `json:"lot_number"`
+	AppliedAt      string     `json:"applied_at"` // 2006-01-02; padrão: hoje
+	ConsultationID *uuid.UUID `json:"consultation_id"`
+	Notes          string     `json:"notes"`
+}
+
+// Lista os protocolos vacinais, opcionalmente filtrando pela espécie (?species=Dog)
+func GetVaccinationProtocolsHandler(vaccinationService *service.VaccinationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		protocols, err := vaccinationService.ListProtocols(context.Background(), c.Query("species"))
+		if err != nil {
+			return vaccinationErrorResponse(c, err, "Failed to list vaccination protocols")
+		}
+		return c.JSON(protocols)
+	}
+}
+
+// Cadastra um protocolo vacinal (somente administradores)
+func CreateVaccinationProtocolHandler(vaccinationService *service.VaccinationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var protocol model.VaccinationProtocol
+		if err := c.BodyParser(&protocol); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		if err := vaccinationService.CreateProtocol(context.Background(), &protocol); err != nil {
+			return vaccinationErrorResponse(c, err, "Failed to create vaccination protocol")
+		}
+		return c.Status(fiber.StatusCreated).JSON(protocol)
+	}
+}
+
+// Lista os produtos vacinais
+func GetVaccineProductsHandler(vaccinationService *service.VaccinationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		products, err := vaccinationService.ListProducts(context.Background())
+		if err != nil {
+			return vaccinationErrorResponse(c, err, "Failed to list vaccine products")
+		}
+		return c.JSON(products)
+	}
+}
+
+// Cadastra um produto vacinal
+func CreateVaccineProductHandler(vaccinationService *service.VaccinationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var product model.VaccineProduct
+		if err := c.BodyParser(&product); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		if err := vaccinationService.CreateProduct(context.Background(), &product); err != nil {
+			return vaccinationErrorResponse(c, err, "Failed to create vaccine product")
+		}
+		return c.Status(fiber.StatusCreated).JSON(product)
+	}
+}
+
+// Registra a aplicação de uma vacina no animal
+func AddVaccinationHandler(vaccinationService *service.VaccinationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		animalID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		var request VaccinationRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		record := model.VaccinationRecord{
+			AnimalID:       animalID,
+			ProductID:      request.ProductID,
+			MedicationID:   nilIfEmpty(request.MedicationID),
+			DoseNumber:     request.DoseNumber,
+			LotNumber:      request.LotNumber,
+			AppliedBy:      currentUserID(c),
+			ConsultationID: nilIfEmpty(request.ConsultationID),
+			Notes:          request.Notes,
+		}
+		if request.AppliedAt != "" {
+			appliedAt, err := time.Parse("2006-01-02", request.AppliedAt)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid date format")
+			}
+			record.AppliedAt = model.CustomDate{Time: appliedAt}
+		}
+
+		if err := vaccinationService.RecordVaccination(context.Background(), &record, time.Now()); err != nil {
+			return vaccinationErrorResponse(c, err, "Failed to record vaccination")
+		}
+		return c.Status(fiber.StatusCreated).JSON(record)
+	}
+}
+
+// Carteira de vacinação do animal
+func GetVaccinationsHandler(vaccinationService *service.VaccinationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		animalID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		records, err := vaccinationService.GetVaccinationCard(context.Background(), animalID)
+		if err != nil {
+			return vaccinationErrorResponse(c, err, "Failed to get vaccinations")
+		}
+		return c.JSON(records)
+	}
+}
+
+// Lista as vacinas atrasadas e a vencer, da clínica toda ou de um tutor (?cpf_tutor=)
+func GetDueVaccinationsHandler(vaccinationService *service.VaccinationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		days := service.DefaultVaccinationDueDays
+		if value := c.Query("days"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 0 {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid days")
+			}
+			days = parsed
+		}
+
+		due, err := vaccinationService.ListDue(context.Background(), CleanCpf(c.Query("cpf_tutor")), days, time.Now())
+		if err != nil {
+			return vaccinationErrorResponse(c, err, "Failed to list due vaccinations")
+		}
+		return c.JSON(due)
+	}
+}
+
+func vaccinationErrorResponse(c *fiber.Ctx, err error, message string) error {
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": validationErrorMessages(validationErrs),
+		})
+	case errors.Is(err, service.ErrProtocolSpeciesMismatch), errors.Is(err, service.ErrBatchNotOfProduct),
+		errors.Is(err, service.ErrBatchExpired), errors.Is(err, service.ErrBatchOutOfStock),
+		errors.Is(err, service.ErrFutureVaccination):
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(err.Error())
+	case errors.Is(err, service.ErrAnimalDeceased):
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).SendString("Record not found")
+	default:
+		log.Printf("%s: %v", message, err)
+		return c.Status(fiber.StatusInternalServerError).SendString(message)
+	}
+}
@@ -1,46 +1,195 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"log"
+	"time"
 	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
 	"vetblock/internal/service"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 )
 
-type VeterinaryResponse struct {
-	Name        string `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
-	CRVM        string `json:"crvm" gorm:"not null" validate:"required"`
-	PhoneNumber string `json:"phone_number" gorm:"not null" validate:"required"`
-	LastName   string `json:"last_name" gorm:"not null" validate:"required"`
-	Email       string `json:"email" gorm:"not null" validate:"required,email"`	
+type VeterinaryRequest struct {
+	CRVM        string `json:"crvm"`
+	Name        string `json:"name"`
+	LastName    string `json:"last_name"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	PhoneNumber string `json:"phone_number"` // Legado: aceito quando phone não é informado
+	Specialty   string `json:"specialty"`
 }
 
+// Cadastra um veterinário
+func CreateVeterinaryHandler(veterinaryService *service.VeterinaryService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input VeterinaryRequest
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+		if input.Phone == "" {
+			input.Phone = input.PhoneNumber
+		}
+
+		veterinary, err := veterinaryService.CreateVeterinary(context.Background(), &model.Veterinary{
+			User:      model.User{Email: input.Email, Phone: input.Phone},
+			CRVM:      input.CRVM,
+			Name:      input.Name,
+			LastName:  input.LastName,
+			Specialty: input.Specialty,
+		})
+		if err != nil {
+			return veterinaryErrorResponse(c, err, "Failed to create veterinary")
+		}
+		return c.Status(fiber.StatusCreated).JSON(veterinary)
+	}
+}
+
+// Lista os veterinários ativos. Query params: name (nome ou sobrenome) e specialty.
+func ListVeterinariesHandler(veterinaryService *service.VeterinaryService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		veterinaries, err := veterinaryService.ListVeterinaries(context.Background(), repository.VeterinaryQuery{
+			Name:      c.Query("name"),
+			Specialty: c.Query("specialty"),
+		})
+		if err != nil {
+			return veterinaryErrorResponse(c, err, "Failed to list veterinaries")
+		}
+		return c.JSON(veterinaries)
+	}
+}
+
+// Retorna o veterinário pelo CRMV
+func GetVeterinaryHandler(veterinaryService *service.VeterinaryService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		veterinary, err := veterinaryService.GetVeterinary(context.Background(), c.Params("crvm"))
+		if err != nil {
+			return veterinaryErrorResponse(c, err, "Failed to get veterinary")
+		}
+		return c.JSON(veterinary)
+	}
+}
+
+// Altera os dados do veterinário; campos ausentes não são alterados
+func UpdateVeterinaryHandler(veterinaryService *service.VeterinaryService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var patch service.VeterinaryPatch
+		if err := c.BodyParser(&patch); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		veterinary, err := veterinaryService.UpdateVeterinary(context.Background(), c.Params("crvm"), patch)
+		if err != nil {
+			return veterinaryErrorResponse(c, err, "Failed to update veterinary")
+		}
+		return c.JSON(veterinary)
+	}
+}
 
+// Remove o veterinário; o histórico de consultas e óbitos é mantido
+func DeleteVeterinaryHandler(veterinaryService *service.VeterinaryService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := veterinaryService.DeleteVeterinary(context.Background(), c.Params("crvm")); err != nil {
+			return veterinaryErrorResponse(c, err, "Failed to delete veterinary")
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
 
+// Restaura um veterinário removido
+func RestoreVeterinaryHandler(veterinaryService *service.VeterinaryService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		veterinary, err := veterinaryService.RestoreVeterinary(context.Background(), c.Params("crvm"))
+		if err != nil {
+			return veterinaryErrorResponse(c, err, "Failed to restore veterinary")
+		}
+		return c.JSON(veterinary)
+	}
+}
+
+type LicenseRequest struct {
+	Number    string `json:"number"`     // Inscrição no formato número-UF, como 678901-RJ
+	ExpiresAt string `json:"expires_at"` // 2006-01-02; vazio quando a validade não é conhecida
+}
+
+// Lista as inscrições do veterinário no CRMV de cada UF
+func GetLicensesHandler(veterinaryService *service.VeterinaryService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		licenses, err := veterinaryService.ListLicenses(context.Background(), c.Params("crvm"))
+		if err != nil {
+			return veterinaryErrorResponse(c, err, "Failed to get licenses")
+		}
+		return c.JSON(licenses)
+	}
+}
 
-func AddVeterinaryHandler() fiber.Handler {
+// Cadastra ou renova a inscrição do veterinário na UF do número informado
+func SetLicenseHandler(veterinaryService *service.VeterinaryService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		var veterinary VeterinaryResponse
-		if err := c.BodyParser(&veterinary); err != nil {
+		var request LicenseRequest
+		if err := c.BodyParser(&request); err != nil {
 			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
 		}
-		log.Print(veterinary)
-		// Convert the VeterinaryResponse to Veterinary
-		veterinaryModel := model.Veterinary{
-			CRVM:  veterinary.CRVM,
-			Name:  veterinary.Name,
-			LastName: veterinary.LastName,	
-			Email: veterinary.Email,
-			Phone: veterinary.PhoneNumber,
+		var expiresAt *time.Time
+		if request.ExpiresAt != "" {
+			parsed, err := time.Parse("2006-01-02", request.ExpiresAt)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid date format")
+			}
+			expiresAt = &parsed
 		}
-		
-		err := service.AddVeterinary(veterinaryModel)
+
+		license, err := veterinaryService.SetLicense(context.Background(), c.Params("crvm"), request.Number, expiresAt)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).SendString("Failed to add veterinary transaction")
+			return veterinaryErrorResponse(c, err, "Failed to save license")
 		}
+		return c.JSON(license)
+	}
+}
 
-		return c.Status(fiber.StatusCreated).JSON(veterinary)	
+// Remove a inscrição do veterinário na UF
+func DeleteLicenseHandler(veterinaryService *service.VeterinaryService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := veterinaryService.DeleteLicense(context.Background(), c.Params("crvm"), c.Params("uf")); err != nil {
+			return veterinaryErrorResponse(c, err, "Failed to delete license")
+		}
+		return c.SendStatus(fiber.StatusNoContent)
 	}
+}
 
+// Lista as inscrições vencidas, a vencer nos próximos days dias (padrão: 30) ou
+// sem validade informada
+func GetLicenseAlertsHandler(veterinaryService *service.VeterinaryService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		alerts, err := veterinaryService.LicenseAlerts(context.Background(), c.QueryInt("days", service.DefaultAlertDays), time.Now())
+		if err != nil {
+			return veterinaryErrorResponse(c, err, "Failed to get license alerts")
+		}
+		return c.JSON(alerts)
+	}
+}
+
+func veterinaryErrorResponse(c *fiber.Ctx, err error, message string) error {
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": validationErrorMessages(validationErrs),
+		})
+	case errors.Is(err, service.ErrInvalidCRMV), errors.Is(err, service.ErrInvalidLicense), errors.Is(err, service.ErrInvalidAlertDays):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, service.ErrLicenseNotFound):
+		return c.Status(fiber.StatusNotFound).SendString(err.Error())
+	case errors.Is(err, service.ErrVeterinaryExists):
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).SendString("Veterinary not found")
+	default:
+		log.Printf("%s: %v", message, err)
+		return c.Status(fiber.StatusInternalServerError).SendString(message)
+	}
 }
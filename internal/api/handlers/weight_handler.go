@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type WeightRecordRequest struct {
+	Date           string     `json:"date"` // 2006-01-02; padrão: hoje
+	Weight         float64    `json:"weight" validate:"gt=0"`
+	Unit           string     `json:"unit"`
+	ConsultationID *uuid.UUID `json:"consultation_id"`
+}
+
+// Handler para registrar uma nova pesagem do animal
+func AddWeightRecordHandler(weightService *service.WeightService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		animalID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		var request WeightRecordRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body")
+		}
+
+		date := time.Now().Truncate(24 * time.Hour)
+		if request.Date != "" {
+			date, err = time.Parse("2006-01-02", request.Date)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid date format")
+			}
+		}
+
+		// Verifica se o animal existe
+		if _, err := animal_service.GetAnimalByID(animalID); err != nil {
+			return animalErrorResponse(c, err, "Error retrieving animal")
+		}
+
+		record := model.WeightRecord{
+			ID:             uuid.New(),
+			AnimalID:       animalID,
+			Date:           model.CustomDate{Time: date},
+			Weight:         request.Weight,
+			Unit:           request.Unit,
+			RecordedBy:     currentUserID(c),
+			ConsultationID: nilIfEmpty(request.ConsultationID),
+		}
+
+		if err := weightService.AddWeightRecord(context.Background(), &record, time.Now()); err != nil {
+			var validationErrs validator.ValidationErrors
+			if errors.As(err, &validationErrs) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": validationErrorMessages(validationErrs),
+				})
+			}
+			if errors.Is(err, service.ErrFutureWeightRecord) {
+				return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+			}
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to add weight record")
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(record)
+	}
+}
+
+// Handler para obter a curva de peso do animal
+func GetWeightSeriesHandler(weightService *service.WeightService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		animalID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid ID format")
+		}
+
+		if _, err := animal_service.GetAnimalByID(animalID); err != nil {
+			return animalErrorResponse(c, err, "Error retrieving animal")
+		}
+
+		series, err := weightService.GetWeightSeries(context.Background(), animalID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to get weight series")
+		}
+
+		return c.Status(fiber.StatusOK).JSON(series)
+	}
+}
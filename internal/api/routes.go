@@ -2,19 +2,21 @@ package api
 
 import (
 	"vetblock/internal/api/handlers"
-	"vetblock/internal/db"
 	"vetblock/internal/db/repository"
 	"vetblock/internal/service"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"gorm.io/gorm"
 )
 
-func SetupRoutes(app *fiber.App) {
+// SetupRoutes registra as rotas. Todos os repositórios usam a conexão database,
+// aberta uma única vez na inicialização.
+func SetupRoutes(app *fiber.App, database *gorm.DB) {
 	// Middleware CORS para permitir requisições de outros domínios
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*", // Ajuste para origens específicas em produção por segurança
-		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
+		AllowMethods: "GET,POST,PUT,PATCH,DELETE,OPTIONS",
 		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
 	}))
 
@@ -26,23 +28,173 @@ func SetupRoutes(app *fiber.App) {
 	protected := app.Group("/api/v1")
 	protected.Use(handlers.Auth) // Adicionando middleware de autenticação para rotas protegidas
 
+	// Serviços compartilhados com os handlers sem parâmetros e com o middleware Auth.
+	// Consultas e prescrições exigem inscrição ativa do veterinário na UF da clínica.
+	animalRepo := repository.NewAnimalRepository(database)
+	catalogService := service.NewCatalogService(repository.NewCatalogRepository(database))
+	tutorService := service.NewTutorService(repository.NewTutorRepository(database), service.WithCEPDirectory(service.CEPDirectoryFromEnv()))
+	veterinaryService := service.NewVeterinaryService(repository.NewVeterinaryRepository(database), service.WithClinicUF(service.ClinicUFFromEnv()))
+	scheduleService := service.NewScheduleService(repository.NewScheduleRepository(database), repository.NewVeterinaryRepository(database))
+	handlers.SetupServices(
+		service.NewAnimalService(animalRepo, service.WithCatalog(catalogService), service.WithTutors(tutorService)),
+		tutorService,
+		veterinaryService,
+		scheduleService,
+	)
+
+	// Portal do tutor: restrito aos animais do tutor autenticado; o tutor só altera os próprios consentimentos
+	tutorPortal := service.NewTutorPortalService(
+		repository.NewTutorRepository(database),
+		animalRepo,
+		repository.NewConsultationRepository(database),
+		repository.NewDosageRepository(database),
+		repository.NewVaccinationRepository(database),
+	)
+	lgpdService := service.NewLGPDService(repository.NewTutorRepository(database), repository.NewLGPDRepository(database))
+	consentService := service.NewConsentService(repository.NewConsentRepository(database), repository.NewTutorRepository(database))
+	user := protected.Group("/user", handlers.RequireTutor)
+	user.Get("/", handlers.GetTutorProfileHandler(tutorPortal))
+	user.Get("/pets", handlers.GetTutorPetsHandler(tutorPortal))
+	user.Get("/pets/:id", handlers.GetTutorPetHandler(tutorPortal))
+	user.Get("/pets/:id/vaccinations", handlers.GetTutorPetVaccinationsHandler(tutorPortal))
+	user.Get("/consultations", handlers.GetTutorConsultationsHandler(tutorPortal))
+	user.Get("/prescriptions", handlers.GetTutorPrescriptionsHandler(tutorPortal))
+	user.Get("/export", handlers.ExportMyDataHandler(lgpdService))
+	user.Get("/consents", handlers.GetConsentsHandler(consentService))
+	user.Put("/consents/:channel/:purpose", handlers.GrantConsentHandler(consentService))
+	user.Delete("/consents/:channel/:purpose", handlers.RevokeConsentHandler(consentService))
+
+	// As rotas registradas a partir daqui são da clínica e recusam tokens de tutores
+	protected.Use(handlers.RequireStaff)
+
 	// Rotas para Animais
 	protected.Post("/animals", handlers.AddAnimalHandler())
 	protected.Get("/animals", handlers.GetAllAnimalsHandler())
+	protected.Get("/animals/trash", handlers.GetAnimalTrashHandler())
+	protected.Delete("/animals/trash", handlers.PurgeAnimalTrashHandler())
+	protected.Get("/animals/duplicates", handlers.GetDuplicateAnimalsHandler())
+	protected.Get("/animals/by-microchip/:number", handlers.GetAnimalByMicrochipHandler())
 	protected.Get("/animals/:id", handlers.GetAnimalByIDHandler())
+	protected.Put("/animals/:id", handlers.UpdateAnimalHandler())
+	protected.Patch("/animals/:id", handlers.PatchAnimalHandler())
+	protected.Get("/animals/:id/history", handlers.GetAnimalHistoryHandler())
+	protected.Post("/animals/:id/transfer", handlers.TransferAnimalHandler())
+	protected.Get("/animals/:id/owners", handlers.GetAnimalOwnersHandler())
+	protected.Get("/animals/:id/tutors", handlers.GetAnimalTutorsHandler())
+	protected.Put("/animals/:id/tutors/:cpf", handlers.SetAnimalTutorHandler())
+	protected.Delete("/animals/:id/tutors/:cpf", handlers.RemoveAnimalTutorHandler())
+	protected.Post("/animals/:id/restore", handlers.RestoreAnimalHandler())
+	protected.Get("/animals/:id/duplicates", handlers.GetAnimalDuplicatesHandler())
+	protected.Post("/animals/:id/merge", handlers.MergeAnimalHandler())
+	protected.Get("/animals/:id/merges", handlers.GetAnimalMergesHandler())
+	protected.Post("/animals/:id/death", handlers.RecordAnimalDeathHandler())
+	protected.Get("/animals/:id/death", handlers.GetAnimalDeathHandler())
+	clinicalService := service.NewClinicalRecordService(repository.NewClinicalRecordRepository(database))
 	protected.Post("/animals/dosage", handlers.AddDosageHandler(
 		service.NewDosageService(
 			repository.NewDosageRepository(
-				repository.GetDB(),
-			))))
+				database,
+			),
+			clinicalService,
+			animalRepo,
+			veterinaryService.FindLicensedVeterinary,
+		)))
+	protected.Post("/animals/:id/allergies", handlers.AddAllergyHandler(clinicalService))
+	protected.Get("/animals/:id/allergies", handlers.GetAllergiesHandler(clinicalService))
+	protected.Delete("/animals/:id/allergies/:record_id", handlers.DeleteAllergyHandler(clinicalService))
+	protected.Post("/animals/:id/conditions", handlers.AddConditionHandler(clinicalService))
+	protected.Get("/animals/:id/conditions", handlers.GetConditionsHandler(clinicalService))
+	protected.Delete("/animals/:id/conditions/:record_id", handlers.DeleteConditionHandler(clinicalService))
 	protected.Delete("/animals/:id", handlers.DeleteAnimalHandler())
 
+	// Rotas para Pesagens
+	weightService := service.NewWeightService(repository.NewWeightRepository(database))
+	protected.Post("/animals/:id/weights", handlers.AddWeightRecordHandler(weightService))
+	protected.Get("/animals/:id/weights", handlers.GetWeightSeriesHandler(weightService))
+
+	// Linha do tempo clínica do animal
+	timelineService := service.NewTimelineService(
+		repository.NewConsultationRepository(database),
+		repository.NewDosageRepository(database),
+		repository.NewHospitalizationRepository(),
+		repository.NewWeightRepository(database),
+		repository.NewImageRepository(database),
+	)
+	protected.Get("/animals/:id/timeline", handlers.GetAnimalTimelineHandler(timelineService))
+
+	// Rotas para Vacinas
+	vaccinationService := service.NewVaccinationService(repository.NewVaccinationRepository(database), animalRepo)
+	protected.Get("/vaccines/protocols", handlers.GetVaccinationProtocolsHandler(vaccinationService))
+	protected.Post("/vaccines/protocols", handlers.RequireAdmin, handlers.CreateVaccinationProtocolHandler(vaccinationService))
+	protected.Get("/vaccines/products", handlers.GetVaccineProductsHandler(vaccinationService))
+	protected.Post("/vaccines/products", handlers.CreateVaccineProductHandler(vaccinationService))
+	protected.Post("/animals/:id/vaccinations", handlers.AddVaccinationHandler(vaccinationService))
+	protected.Get("/animals/:id/vaccinations", handlers.GetVaccinationsHandler(vaccinationService))
+	protected.Get("/vaccinations/due", handlers.GetDueVaccinationsHandler(vaccinationService))
+
+	// Rotas para o catálogo de espécies e raças
+	protected.Get("/catalog/species", handlers.GetSpeciesCatalogHandler(catalogService))
+	protected.Post("/catalog/species", handlers.RequireAdmin, handlers.CreateSpeciesHandler(catalogService))
+	protected.Put("/catalog/species/:id", handlers.RequireAdmin, handlers.UpdateSpeciesHandler(catalogService))
+	protected.Delete("/catalog/species/:id", handlers.RequireAdmin, handlers.DeleteSpeciesHandler(catalogService))
+	protected.Post("/catalog/species/:id/breeds", handlers.RequireAdmin, handlers.CreateBreedHandler(catalogService))
+	protected.Put("/catalog/breeds/:id", handlers.RequireAdmin, handlers.UpdateBreedHandler(catalogService))
+	protected.Delete("/catalog/breeds/:id", handlers.RequireAdmin, handlers.DeleteBreedHandler(catalogService))
+
+	// Rotas para Tutores
+	protected.Post("/tutors", handlers.CreateTutorHandler(tutorService))
+	protected.Get("/tutors", handlers.SearchTutorsHandler(tutorService))
+	protected.Get("/tutors/regions", handlers.GetTutorRegionsHandler(tutorService))
+	protected.Post("/tutors/addresses/migrate", handlers.RequireAdmin, handlers.MigrateLegacyAddressesHandler(tutorService))
+	protected.Get("/addresses/cep/:cep", handlers.LookupCEPHandler(tutorService))
+	protected.Get("/tutors/:cpf", handlers.GetTutorHandler(tutorService))
+	protected.Put("/tutors/:cpf", handlers.UpdateTutorHandler(tutorService))
+	protected.Delete("/tutors/:cpf", handlers.DeactivateTutorHandler(tutorService))
+	protected.Post("/tutors/:cpf/reactivate", handlers.ReactivateTutorHandler(tutorService))
+	protected.Get("/tutors/:cpf/animals", handlers.GetTutorAnimalsHandler(tutorService))
+	protected.Get("/tutors/:cpf/representatives", handlers.GetLegalRepresentativesHandler(tutorService))
+	protected.Post("/tutors/:cpf/representatives", handlers.AddLegalRepresentativeHandler(tutorService))
+	protected.Delete("/tutors/:cpf/representatives/:id", handlers.RemoveLegalRepresentativeHandler(tutorService))
+	protected.Get("/tutors/:cpf/consents", handlers.GetConsentsHandler(consentService))
+	protected.Put("/tutors/:cpf/consents/:channel/:purpose", handlers.GrantConsentHandler(consentService))
+	protected.Delete("/tutors/:cpf/consents/:channel/:purpose", handlers.RevokeConsentHandler(consentService))
+
+	// Pedidos de titulares de dados (LGPD), somente administradores
+	protected.Get("/tutors/:cpf/export", handlers.RequireAdmin, handlers.ExportTutorDataHandler(lgpdService))
+	protected.Post("/tutors/:cpf/anonymize", handlers.RequireAdmin, handlers.AnonymizeTutorHandler(lgpdService))
+
+	// Importação de tutores e animais de sistemas legados (somente administradores)
+	importService := service.NewImportService(
+		repository.NewTutorRepository(database),
+		service.NewAnimalService(animalRepo, service.WithCatalog(catalogService)),
+	)
+	protected.Post("/import/csv", handlers.RequireAdmin, handlers.ImportCSVHandler(importService))
+
+	// Rotas para Veterinários
+	protected.Post("/veterinaries", handlers.CreateVeterinaryHandler(veterinaryService))
+	protected.Get("/veterinaries", handlers.ListVeterinariesHandler(veterinaryService))
+	protected.Get("/veterinaries/licenses/alerts", handlers.GetLicenseAlertsHandler(veterinaryService))
+	protected.Get("/veterinaries/:crvm", handlers.GetVeterinaryHandler(veterinaryService))
+	protected.Put("/veterinaries/:crvm", handlers.UpdateVeterinaryHandler(veterinaryService))
+	protected.Delete("/veterinaries/:crvm", handlers.RequireAdmin, handlers.DeleteVeterinaryHandler(veterinaryService))
+	protected.Post("/veterinaries/:crvm/restore", handlers.RequireAdmin, handlers.RestoreVeterinaryHandler(veterinaryService))
+	protected.Get("/veterinaries/:crvm/licenses", handlers.GetLicensesHandler(veterinaryService))
+	protected.Put("/veterinaries/:crvm/licenses", handlers.RequireAdmin, handlers.SetLicenseHandler(veterinaryService))
+	protected.Delete("/veterinaries/:crvm/licenses/:uf", handlers.RequireAdmin, handlers.DeleteLicenseHandler(veterinaryService))
+	protected.Get("/veterinaries/:crvm/working-hours", handlers.GetWorkingHoursHandler(scheduleService))
+	protected.Put("/veterinaries/:crvm/working-hours", handlers.SetWorkingHoursHandler(scheduleService))
+	protected.Get("/veterinaries/:crvm/exceptions", handlers.GetAvailabilityExceptionsHandler(scheduleService))
+	protected.Post("/veterinaries/:crvm/exceptions", handlers.AddAvailabilityExceptionHandler(scheduleService))
+	protected.Delete("/veterinaries/:crvm/exceptions/:id", handlers.DeleteAvailabilityExceptionHandler(scheduleService))
+	protected.Get("/veterinaries/:crvm/availability", handlers.GetDayAvailabilityHandler(scheduleService))
+
 	// Rotas para Consultas
-	protected.Post("/consultations", handlers.AddConsultationHandler(repository.NewConsultationRepository(db.GetDB())))
-	protected.Get("/veterinary/:crvm/next-consultation", handlers.GetNextConsultationHandler(repository.NewConsultationRepository(db.GetDB())))
-	protected.Get("/consultations/:crvm", handlers.GetAllConsultationsByVeterinaryHandler(repository.NewConsultationRepository(db.GetDB())))
-	protected.Post("/veterinaries", handlers.AddVeterinaryHandler())
-	protected.Get("/consultations/patient/:animal_id", handlers.GetConsultsByAnimalIDHandler(repository.NewConsultationRepository(db.GetDB())))
+	protected.Post("/consultations", handlers.AddConsultationHandler(repository.NewConsultationRepository(database)))
+	protected.Get("/veterinary/:crvm/next-consultation", handlers.GetNextConsultationHandler(repository.NewConsultationRepository(database)))
+	protected.Get("/veterinary/:crvm/stats", handlers.GetVeterinaryStatsHandler(service.NewVeterinaryStatsService(repository.NewConsultationRepository(database), repository.NewVeterinaryRepository(database))))
+	protected.Patch("/consultations/:id/status", handlers.UpdateConsultationStatusHandler(repository.NewConsultationRepository(database), service.ClinicLocationFromEnv()))
+	protected.Get("/consultations/:crvm", handlers.GetAllConsultationsByVeterinaryHandler(repository.NewConsultationRepository(database)))
+	protected.Get("/consultations/patient/:animal_id", handlers.GetConsultsByAnimalIDHandler(repository.NewConsultationRepository(database)))
 
 	// Rotas para Medicamentos
 	protected.Post("/medications", handlers.AddMedicationHandler())
@@ -58,8 +210,8 @@ func SetupRoutes(app *fiber.App) {
 	protected.Get("/medications/active-substance/:active_substance", handlers.GetMedicationByActiveSubstanceHandler())
 
 	// Rotas para Imagens
-	imageRepo := repository.NewImageRepository()          // Repositório de imagens
-	imageService := service.NewImageService(imageRepo)    // Serviço de imagens
+	imageRepo := repository.NewImageRepository(database)   // Repositório de imagens
+	imageService := service.NewImageService(imageRepo)     // Serviço de imagens
 	imageHandler := handlers.NewImageHandler(imageService) // Handler de imagens
 
 	protected.Get("/animals/:id/image", imageHandler.GetImageByIDHandler)
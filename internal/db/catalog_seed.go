@@ -0,0 +1,167 @@
+package db
+
+import (
+	"errors"
+	"log"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Raça usada para animais sem raça definida; toda espécie do catálogo inicial a possui
+var mixedBreed = model.Breed{Code: "mixed", NamePT: "Sem raça definida (SRD)", NameEN: "Mixed breed", Aliases: []string{"srd", "vira-lata", "viralata", "mestico", "mixed", "unknown"}}
+
+// Catálogo inicial de espécies e raças. Depois de carregado, os administradores
+// podem editá-lo pela API; a carga não recria registros alterados ou excluídos.
+var speciesSeed = []model.Species{
+	{Code: "dog", NamePT: "Cão", NameEN: "Dog", Aliases: []string{"cachorro", "cadela", "canino", "canina", "canine", "canis familiaris"}, Breeds: []model.Breed{
+		mixedBreed,
+		{Code: "labrador_retriever", NamePT: "Labrador", NameEN: "Labrador Retriever", Aliases: []string{"labrador"}},
+		{Code: "golden_retriever", NamePT: "Golden Retriever", NameEN: "Golden Retriever", Aliases: []string{"golden"}},
+		{Code: "german_shepherd", NamePT: "Pastor Alemão", NameEN: "German Shepherd"},
+		{Code: "poodle", NamePT: "Poodle", NameEN: "Poodle"},
+		{Code: "yorkshire_terrier", NamePT: "Yorkshire", NameEN: "Yorkshire Terrier", Aliases: []string{"yorkshire"}},
+		{Code: "shih_tzu", NamePT: "Shih Tzu", NameEN: "Shih Tzu", Aliases: []string{"shitzu"}},
+		{Code: "dachshund", NamePT: "Dachshund (Salsicha)", NameEN: "Dachshund", Aliases: []string{"salsicha", "teckel"}},
+		{Code: "bulldog", NamePT: "Buldogue Inglês", NameEN: "Bulldog", Aliases: []string{"buldogue", "english bulldog"}},
+		{Code: "french_bulldog", NamePT: "Buldogue Francês", NameEN: "French Bulldog"},
+		{Code: "beagle", NamePT: "Beagle", NameEN: "Beagle"},
+		{Code: "rottweiler", NamePT: "Rottweiler", NameEN: "Rottweiler"},
+		{Code: "pinscher", NamePT: "Pinscher", NameEN: "Miniature Pinscher", Aliases: []string{"pinscher miniatura"}},
+		{Code: "border_collie", NamePT: "Border Collie", NameEN: "Border Collie"},
+		{Code: "pit_bull", NamePT: "Pit Bull", NameEN: "American Pit Bull Terrier", Aliases: []string{"pitbull"}},
+		{Code: "lhasa_apso", NamePT: "Lhasa Apso", NameEN: "Lhasa Apso"},
+		{Code: "chihuahua", NamePT: "Chihuahua", NameEN: "Chihuahua"},
+		{Code: "fila_brasileiro", NamePT: "Fila Brasileiro", NameEN: "Fila Brasileiro", Aliases: []string{"fila"}},
+	}},
+	{Code: "cat", NamePT: "Gato", NameEN: "Cat", Aliases: []string{"gata", "felino", "felina", "feline", "felis catus"}, Breeds: []model.Breed{
+		mixedBreed,
+		{Code: "persian", NamePT: "Persa", NameEN: "Persian"},
+		{Code: "siamese", NamePT: "Siamês", NameEN: "Siamese"},
+		{Code: "maine_coon", NamePT: "Maine Coon", NameEN: "Maine Coon"},
+		{Code: "british_shorthair", NamePT: "British Shorthair", NameEN: "British Shorthair"},
+		{Code: "ragdoll", NamePT: "Ragdoll", NameEN: "Ragdoll"},
+		{Code: "sphynx", NamePT: "Sphynx", NameEN: "Sphynx"},
+		{Code: "bengal", NamePT: "Bengal", NameEN: "Bengal"},
+		{Code: "angora", NamePT: "Angorá", NameEN: "Turkish Angora", Aliases: []string{"angora turco"}},
+	}},
+	{Code: "bird", NamePT: "Ave", NameEN: "Bird", Aliases: []string{"passaro", "aves"}, Breeds: []model.Breed{
+		mixedBreed,
+		{Code: "budgerigar", NamePT: "Periquito", NameEN: "Budgerigar", Aliases: []string{"periquito australiano"}},
+		{Code: "cockatiel", NamePT: "Calopsita", NameEN: "Cockatiel"},
+		{Code: "canary", NamePT: "Canário", NameEN: "Canary"},
+		{Code: "lovebird", NamePT: "Agapornis", NameEN: "Lovebird"},
+	}},
+	{Code: "rabbit", NamePT: "Coelho", NameEN: "Rabbit", Aliases: []string{"coelha", "lagomorfo"}, Breeds: []model.Breed{
+		mixedBreed,
+		{Code: "mini_lop", NamePT: "Mini Lop", NameEN: "Mini Lop"},
+		{Code: "lionhead", NamePT: "Cabeça de Leão", NameEN: "Lionhead", Aliases: []string{"lion head"}},
+	}},
+	{Code: "hamster", NamePT: "Hamster", NameEN: "Hamster", Breeds: []model.Breed{
+		mixedBreed,
+		{Code: "syrian", NamePT: "Sírio", NameEN: "Syrian"},
+		{Code: "russian_dwarf", NamePT: "Anão Russo", NameEN: "Russian Dwarf"},
+	}},
+	{Code: "guinea_pig", NamePT: "Porquinho-da-índia", NameEN: "Guinea Pig", Aliases: []string{"porquinho da india", "cobaia", "preá"}, Breeds: []model.Breed{mixedBreed}},
+	{Code: "ferret", NamePT: "Furão", NameEN: "Ferret", Breeds: []model.Breed{mixedBreed}},
+	{Code: "reptile", NamePT: "Réptil", NameEN: "Reptile", Aliases: []string{"reptil", "repteis"}, Breeds: []model.Breed{
+		mixedBreed,
+		{Code: "bearded_dragon", NamePT: "Dragão-barbudo", NameEN: "Bearded Dragon", Aliases: []string{"pogona"}},
+		{Code: "leopard_gecko", NamePT: "Lagartixa-leopardo", NameEN: "Leopard Gecko", Aliases: []string{"gecko leopardo"}},
+		{Code: "tortoise", NamePT: "Jabuti", NameEN: "Tortoise"},
+	}},
+	{Code: "horse", NamePT: "Cavalo", NameEN: "Horse", Aliases: []string{"equino", "egua", "equine"}, Breeds: []model.Breed{
+		mixedBreed,
+		{Code: "mangalarga_marchador", NamePT: "Mangalarga Marchador", NameEN: "Mangalarga Marchador", Aliases: []string{"mangalarga"}},
+		{Code: "quarter_horse", NamePT: "Quarto de Milha", NameEN: "Quarter Horse"},
+		{Code: "arabian", NamePT: "Árabe", NameEN: "Arabian"},
+	}},
+	{Code: "cattle", NamePT: "Bovino", NameEN: "Cattle", Aliases: []string{"boi", "vaca", "bovine"}, Breeds: []model.Breed{
+		mixedBreed,
+		{Code: "nelore", NamePT: "Nelore", NameEN: "Nelore", Aliases: []string{"nellore"}},
+		{Code: "holstein", NamePT: "Holandesa", NameEN: "Holstein", Aliases: []string{"holstein-friesian"}},
+		{Code: "gir", NamePT: "Gir", NameEN: "Gyr", Aliases: []string{"gyr"}},
+	}},
+}
+
+// seedSpeciesCatalog carrega o catálogo inicial. Espécies e raças são procuradas pelo
+// código incluindo as excluídas, para não recriar o que um administrador removeu.
+func seedSpeciesCatalog(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, seed := range speciesSeed {
+			var species model.Species
+			err := tx.Unscoped().Where("code = ?", seed.Code).First(&species).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				species = model.Species{ID: uuid.New(), Code: seed.Code, NamePT: seed.NamePT, NameEN: seed.NameEN, Aliases: seed.Aliases}
+				if err := tx.Create(&species).Error; err != nil {
+					return err
+				}
+				log.Printf("Seeded species %s", species.Code)
+			} else if err != nil {
+				return err
+			}
+
+			for _, breedSeed := range seed.Breeds {
+				var count int64
+				if err := tx.Unscoped().Model(&model.Breed{}).Where("species_id = ? AND code = ?", species.ID, breedSeed.Code).Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					continue
+				}
+				breed := breedSeed
+				breed.ID = uuid.New()
+				breed.SpeciesID = species.ID
+				if err := tx.Create(&breed).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// canonicalizeAnimalSpecies troca as espécies e raças digitadas livremente nos animais
+// já cadastrados pelos nomes do catálogo. Valores que não correspondem a nenhuma
+// entrada são mantidos como estão.
+func canonicalizeAnimalSpecies(db *gorm.DB) error {
+	var catalog []model.Species
+	if err := db.Preload("Breeds").Find(&catalog).Error; err != nil {
+		return err
+	}
+
+	var pairs []struct {
+		Species string
+		Breed   string
+	}
+	if err := db.Unscoped().Model(&model.Animal{}).Distinct("species", "breed").Find(&pairs).Error; err != nil {
+		return err
+	}
+
+	for _, pair := range pairs {
+		for _, species := range catalog {
+			if !species.Matches(pair.Species) {
+				continue
+			}
+			breedName := pair.Breed
+			for _, breed := range species.Breeds {
+				if breed.Matches(pair.Breed) {
+					breedName = breed.NameEN
+					break
+				}
+			}
+			if pair.Species == species.NameEN && pair.Breed == breedName {
+				break
+			}
+			result := db.Unscoped().Model(&model.Animal{}).Where("species = ? AND breed = ?", pair.Species, pair.Breed).
+				Updates(map[string]interface{}{"species": species.NameEN, "breed": breedName})
+			if result.Error != nil {
+				return result.Error
+			}
+			log.Printf("Mapped %d animals from %q/%q to the catalog", result.RowsAffected, pair.Species, pair.Breed)
+			break
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sync"
 	"vetblock/internal/db/model"
 
 	"github.com/joho/godotenv"
@@ -12,11 +13,22 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	sharedDB   *gorm.DB
+	sharedOnce sync.Once
+)
 
+// GetDB retorna a conexão da aplicação. A conexão é aberta, e as migrações são
+// executadas, apenas na primeira chamada; as seguintes reutilizam o mesmo pool.
 func GetDB() *gorm.DB {
-	return NewDb()
+	sharedOnce.Do(func() {
+		sharedDB = NewDb()
+	})
+	return sharedDB
 }
 
+// NewDb abre uma nova conexão e executa as migrações. Use GetDB para reutilizar
+// a conexão da aplicação.
 func NewDb() *gorm.DB {
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", "localhost", 5432, "vetblock", "vet113password", "vetblock")
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
@@ -25,12 +37,20 @@ func NewDb() *gorm.DB {
 		panic("failed to connect database")
 	}
 
+	if err := runSchemaMigrations(db); err != nil {
+		log.Fatalf("failed to run schema migrations: %v", err)
+	}
+
 	// Verifica o retorno de erro da migração
-	errMigrate := db.AutoMigrate(&model.User{}, &model.Animal{}, &model.Hospitalization{}, &model.Consultation{}, &model.ConsultationHistory{}, &model.Veterinary{}, &model.Medication{}, &model.Dosage{}, &model.ImageModel{})
+	errMigrate := db.AutoMigrate(&model.User{}, &model.Animal{}, &model.Hospitalization{}, &model.Consultation{}, &model.ConsultationHistory{}, &model.AnimalHistory{}, &model.Veterinary{}, &model.Medication{}, &model.Dosage{}, &model.ImageModel{}, &model.WeightRecord{}, &model.OwnershipTransfer{}, &model.AuditLog{}, &model.AnimalMerge{}, &model.Species{}, &model.Breed{}, &model.Allergy{}, &model.ChronicCondition{}, &model.VaccinationProtocol{}, &model.VaccineProduct{}, &model.VaccinationRecord{}, &model.DeathRecord{}, &model.Tutor{}, &model.AnimalTutor{}, &model.TutorConsent{}, &model.LegalRepresentative{}, &model.WorkingHours{}, &model.AvailabilityException{}, &model.VetLicense{})
 	if errMigrate != nil {
 		log.Fatalf("failed to auto migrate: %v", errMigrate)
 	}
 
+	if err := runDataMigrations(db); err != nil {
+		log.Fatalf("failed to run data migrations: %v", err)
+	}
+
 	return db
 }
 
@@ -0,0 +1,371 @@
+package db
+
+import (
+	"fmt"
+	"log"
+	"vetblock/internal/db/model"
+
+	"gorm.io/gorm"
+)
+
+// dataMigration é uma migração de dados que o AutoMigrate não cobre. Cada uma
+// deve ser idempotente, pois todas rodam a cada inicialização.
+type dataMigration struct {
+	name string
+	run  func(db *gorm.DB) error
+}
+
+// schemaMigrations rodam antes do AutoMigrate, para alterações de esquema que ele
+// não consegue fazer sozinho. Também devem ser idempotentes.
+var schemaMigrations = []dataMigration{
+	{"widen tutor document columns", widenTutorDocumentColumns},
+	{"prepare veterinaries table", prepareVeterinariesTable},
+}
+
+var dataMigrations = []dataMigration{
+	{"animal age to birth date", migrateAnimalAgeToBirthDate},
+	{"seed species catalog", seedSpeciesCatalog},
+	{"map animal species to catalog", canonicalizeAnimalSpecies},
+	{"seed vaccination protocols", seedVaccinationProtocols},
+	{"animal tutor foreign key", addAnimalTutorForeignKey},
+	{"animal tutors foreign keys", addAnimalTutorsForeignKeys},
+	{"tutor consents foreign key", addTutorConsentsForeignKey},
+	{"legal representatives foreign key", addLegalRepresentativesForeignKey},
+	{"merge legacy veterinarians", mergeLegacyVeterinarians},
+	{"veterinary foreign keys", addVeterinaryForeignKeys},
+	{"veterinary schedule foreign keys", addVeterinaryScheduleForeignKeys},
+	{"seed primary veterinary licenses", seedPrimaryVetLicenses},
+	{"mark configured working hours", markConfiguredWorkingHours},
+}
+
+func runSchemaMigrations(db *gorm.DB) error {
+	return runMigrations(db, schemaMigrations)
+}
+
+func runDataMigrations(db *gorm.DB) error {
+	return runMigrations(db, dataMigrations)
+}
+
+func runMigrations(db *gorm.DB, migrations []dataMigration) error {
+	for _, migration := range migrations {
+		if err := migration.run(db); err != nil {
+			return fmt.Errorf("%s: %w", migration.name, err)
+		}
+	}
+	return nil
+}
+
+// migrateAnimalAgeToBirthDate converte a antiga coluna inteira "age" em uma data de
+// nascimento estimada, contada a partir da data de cadastro do animal. Idade 0 era
+// o valor padrão para "não informada" e por isso não gera data de nascimento. A
+// idade convertida é zerada na mesma transação, para que uma data de nascimento
+// removida depois não seja recriada a partir dela na próxima inicialização.
+func migrateAnimalAgeToBirthDate(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("animals", "age") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`UPDATE animals
+			SET birth_date = (created_at - make_interval(years => age::int))::date,
+			    birth_date_precision = ?
+			WHERE birth_date IS NULL AND age > 0`, model.BirthDatePrecisionEstimated)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("Migrated age to estimated birth date for %d animals", result.RowsAffected)
+		}
+		return tx.Exec(`UPDATE animals SET age = 0 WHERE age <> 0`).Error
+	})
+}
+
+const animalTutorForeignKey = "fk_animals_tutor"
+
+// addAnimalTutorForeignKey liga animals.cpf_tutor a tutors.cpf_tutor. A chave é
+// criada como NOT VALID, valendo para novos animais e trocas de tutor sem exigir
+// que os animais cadastrados antes da tabela de tutores tenham o tutor registrado.
+// Quando não restar nenhum animal sem tutor, a chave passa a valer para todas as linhas.
+func addAnimalTutorForeignKey(db *gorm.DB) error {
+	exists, err := hasConstraint(db, animalTutorForeignKey)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := db.Exec(`ALTER TABLE animals ADD CONSTRAINT ` + animalTutorForeignKey + `
+			FOREIGN KEY (cpf_tutor) REFERENCES tutors (cpf_tutor) ON UPDATE CASCADE NOT VALID`).Error; err != nil {
+			return err
+		}
+		log.Print("Added animal tutor foreign key")
+	}
+
+	var orphans int64
+	if err := db.Raw(`SELECT COUNT(*) FROM animals a
+		WHERE NOT EXISTS (SELECT 1 FROM tutors t WHERE t.cpf_tutor = a.cpf_tutor)`).Scan(&orphans).Error; err != nil {
+		return err
+	}
+	if orphans > 0 {
+		log.Printf("%d animals reference tutors that are not registered", orphans)
+		return nil
+	}
+	return db.Exec("ALTER TABLE animals VALIDATE CONSTRAINT " + animalTutorForeignKey).Error
+}
+
+// addAnimalTutorsForeignKeys liga os tutores adicionais ao animal e ao tutor. Os
+// vínculos são removidos junto com o animal quando ele é excluído definitivamente.
+func addAnimalTutorsForeignKeys(db *gorm.DB) error {
+	keys := []struct{ name, definition string }{
+		{"fk_animal_tutors_animal", "FOREIGN KEY (animal_id) REFERENCES animals (id) ON DELETE CASCADE"},
+		{"fk_animal_tutors_tutor", "FOREIGN KEY (cpf_tutor) REFERENCES tutors (cpf_tutor) ON UPDATE CASCADE"},
+	}
+	for _, key := range keys {
+		exists, err := hasConstraint(db, key.name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if err := db.Exec("ALTER TABLE animal_tutors ADD CONSTRAINT " + key.name + " " + key.definition).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addTutorConsentsForeignKey liga os consentimentos ao tutor; acompanham a troca
+// do CPF pelo pseudônimo na anonimização
+func addTutorConsentsForeignKey(db *gorm.DB) error {
+	exists, err := hasConstraint(db, "fk_tutor_consents_tutor")
+	if err != nil || exists {
+		return err
+	}
+	return db.Exec(`ALTER TABLE tutor_consents ADD CONSTRAINT fk_tutor_consents_tutor
+		FOREIGN KEY (cpf_tutor) REFERENCES tutors (cpf_tutor) ON UPDATE CASCADE`).Error
+}
+
+// addLegalRepresentativesForeignKey liga os representantes legais à organização;
+// são removidos junto com ela
+func addLegalRepresentativesForeignKey(db *gorm.DB) error {
+	exists, err := hasConstraint(db, "fk_legal_representatives_tutor")
+	if err != nil || exists {
+		return err
+	}
+	return db.Exec(`ALTER TABLE legal_representatives ADD CONSTRAINT fk_legal_representatives_tutor
+		FOREIGN KEY (cnpj) REFERENCES tutors (cpf_tutor) ON UPDATE CASCADE ON DELETE CASCADE`).Error
+}
+
+// mergeLegacyVeterinarians copia para veterinaries os veterinários cadastrados pelo
+// registro de usuários na antiga tabela veterinarians, que não são sobrescritos se o
+// CRMV já existir. Também marca como veterinário os cadastros anteriores ao tipo
+// de usuário e padroniza o CRMV das consultas e dos óbitos, como no cadastro.
+func mergeLegacyVeterinarians(db *gorm.DB) error {
+	if db.Migrator().HasTable("veterinarians") {
+		result := db.Exec(`INSERT INTO veterinaries (crvm, name, last_name, specialty, email, phone, user_type, created_at, updated_at)
+			SELECT DISTINCT ON (upper(btrim(v.crmv))) upper(btrim(v.crmv)), v.name, '', coalesce(v.specialty, ''),
+				v.email, v.phone, ?, now(), now()
+			FROM veterinarians v
+			WHERE NOT EXISTS (SELECT 1 FROM veterinaries d WHERE d.crvm = upper(btrim(v.crmv)))`, model.VeterinarianType)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("Merged %d legacy veterinarians into veterinaries", result.RowsAffected)
+		}
+	}
+
+	if err := db.Exec(`UPDATE veterinaries SET user_type = ? WHERE user_type IS NULL OR user_type = ''`,
+		model.VeterinarianType).Error; err != nil {
+		return err
+	}
+	for _, table := range []string{"veterinaries", "consultations", "death_records"} {
+		if err := db.Exec("UPDATE " + table + " SET crvm = upper(btrim(crvm)) WHERE crvm <> upper(btrim(crvm))").Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addVeterinaryForeignKeys liga consultas e óbitos ao veterinário pelo CRMV. Como em
+// addAnimalTutorForeignKey, as chaves são criadas como NOT VALID e só passam a valer
+// para as linhas antigas quando todas apontarem para um veterinário cadastrado.
+func addVeterinaryForeignKeys(db *gorm.DB) error {
+	for _, table := range []string{"consultations", "death_records"} {
+		name := "fk_" + table + "_veterinary"
+		exists, err := hasConstraint(db, name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if err := db.Exec(`ALTER TABLE ` + table + ` ADD CONSTRAINT ` + name + `
+				FOREIGN KEY (crvm) REFERENCES veterinaries (crvm) ON UPDATE CASCADE NOT VALID`).Error; err != nil {
+				return err
+			}
+			log.Printf("Added %s veterinary foreign key", table)
+		}
+
+		var orphans int64
+		if err := db.Raw(`SELECT COUNT(*) FROM ` + table + ` r
+			WHERE NOT EXISTS (SELECT 1 FROM veterinaries v WHERE v.crvm = r.crvm)`).Scan(&orphans).Error; err != nil {
+			return err
+		}
+		if orphans > 0 {
+			log.Printf("%d %s reference veterinarians that are not registered", orphans, table)
+			continue
+		}
+		if err := db.Exec("ALTER TABLE " + table + " VALIDATE CONSTRAINT " + name).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addVeterinaryScheduleForeignKeys liga os horários de atendimento, as ausências e
+// as inscrições no CRMV ao veterinário
+func addVeterinaryScheduleForeignKeys(db *gorm.DB) error {
+	for _, table := range []string{"working_hours", "availability_exceptions", "vet_licenses"} {
+		name := "fk_" + table + "_veterinary"
+		exists, err := hasConstraint(db, name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if err := db.Exec(`ALTER TABLE ` + table + ` ADD CONSTRAINT ` + name + `
+			FOREIGN KEY (crvm) REFERENCES veterinaries (crvm) ON UPDATE CASCADE ON DELETE CASCADE`).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedPrimaryVetLicenses registra o CRMV do cadastro como inscrição principal dos
+// veterinários que ainda não têm inscrição na UF dele. A validade fica em branco
+// até ser informada e a inscrição aparece nos alertas de vencimento.
+func seedPrimaryVetLicenses(db *gorm.DB) error {
+	var veterinaries []model.Veterinary
+	if err := db.Unscoped().Where(`NOT EXISTS (SELECT 1 FROM vet_licenses l
+		WHERE l.crvm = veterinaries.crvm AND l.uf = right(veterinaries.crvm, 2))`).Find(&veterinaries).Error; err != nil {
+		return err
+	}
+	for _, veterinary := range veterinaries {
+		if !model.IsValidCRMV(veterinary.CRVM) {
+			log.Printf("Veterinary %q has an invalid CRMV; register the license manually", veterinary.CRVM)
+			continue
+		}
+		license := model.NewPrimaryLicense(veterinary.CRVM)
+		if err := db.Create(&license).Error; err != nil {
+			return err
+		}
+		log.Printf("Seeded primary license for veterinary %s", veterinary.CRVM)
+	}
+	return nil
+}
+
+// prepareVeterinariesTable adapta a tabela veterinaries ao modelo unificado: o CRMV
+// passa de char(12), que completava com espaços, para varchar(12), e a tabela ganha
+// a coluna id de User, que o AutoMigrate não consegue acrescentar com valores.
+func prepareVeterinariesTable(db *gorm.DB) error {
+	if !db.Migrator().HasTable("veterinaries") {
+		return nil
+	}
+
+	var dataType string
+	if err := db.Raw(`SELECT data_type FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'veterinaries' AND column_name = 'crvm'`).
+		Scan(&dataType).Error; err != nil {
+		return err
+	}
+	if dataType == "character" {
+		if err := db.Exec("ALTER TABLE veterinaries ALTER COLUMN crvm TYPE varchar(12) USING upper(btrim(crvm))").Error; err != nil {
+			return err
+		}
+		log.Print("Changed veterinaries.crvm to varchar(12)")
+	}
+	if !db.Migrator().HasColumn("veterinaries", "id") {
+		if err := db.Exec("ALTER TABLE veterinaries ADD COLUMN id bigserial").Error; err != nil {
+			return err
+		}
+		log.Print("Added id column to veterinaries")
+	}
+	return nil
+}
+
+type tableColumn struct{ table, column string }
+
+// tutorDocumentColumns são as colunas que guardam o CPF ou o CNPJ de um tutor
+var tutorDocumentColumns = []tableColumn{
+	{"tutors", "cpf_tutor"},
+	{"animals", "cpf_tutor"},
+	{"animal_tutors", "cpf_tutor"},
+	{"tutor_consents", "cpf_tutor"},
+	{"ownership_transfers", "previous_cpf"},
+	{"ownership_transfers", "new_cpf"},
+	{"consultations", "authorized_by"},
+	{"death_records", "authorized_by"},
+}
+
+// widenTutorDocumentColumns troca char(11) por varchar(14) nas colunas com o
+// documento do tutor, para caber o CNPJ. As chaves estrangeiras para
+// tutors.cpf_tutor são removidas antes e recriadas pelas migrações de dados.
+func widenTutorDocumentColumns(db *gorm.DB) error {
+	var pending []tableColumn
+	for _, c := range tutorDocumentColumns {
+		var dataType string
+		if err := db.Raw(`SELECT data_type FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?`, c.table, c.column).
+			Scan(&dataType).Error; err != nil {
+			return err
+		}
+		if dataType == "character" {
+			pending = append(pending, c)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, key := range []struct{ table, name string }{
+			{"animals", animalTutorForeignKey},
+			{"animal_tutors", "fk_animal_tutors_tutor"},
+			{"tutor_consents", "fk_tutor_consents_tutor"},
+		} {
+			if tx.Migrator().HasTable(key.table) {
+				if err := tx.Exec("ALTER TABLE " + key.table + " DROP CONSTRAINT IF EXISTS " + key.name).Error; err != nil {
+					return err
+				}
+			}
+		}
+		for _, c := range pending {
+			if err := tx.Exec("ALTER TABLE " + c.table + " ALTER COLUMN " + c.column + " TYPE varchar(14)").Error; err != nil {
+				return err
+			}
+			log.Printf("Widened %s.%s to varchar(14)", c.table, c.column)
+		}
+		return nil
+	})
+}
+
+func hasConstraint(db *gorm.DB, name string) (bool, error) {
+	var exists bool
+	err := db.Raw("SELECT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = ?)", name).Scan(&exists).Error
+	return exists, err
+}
+
+// markConfiguredWorkingHours preenche veterinaries.hours_set_at dos veterinários
+// que já têm horário semanal. Os que esvaziaram o horário antes da coluna existir
+// não podem ser distinguidos dos que nunca o cadastraram e continuam atendendo o
+// dia todo até gravarem um horário.
+func markConfiguredWorkingHours(db *gorm.DB) error {
+	result := db.Exec(`UPDATE veterinaries SET hours_set_at = NOW()
+		WHERE hours_set_at IS NULL AND crvm IN (SELECT crvm FROM working_hours)`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Marked working hours as configured for %d veterinarians", result.RowsAffected)
+	}
+	return nil
+}
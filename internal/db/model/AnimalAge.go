@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Precisão da data de nascimento do animal
+const (
+	BirthDatePrecisionExact     = "exact"     // dia conhecido
+	BirthDatePrecisionMonth     = "month"     // apenas mês e ano conhecidos
+	BirthDatePrecisionYear      = "year"      // apenas o ano conhecido
+	BirthDatePrecisionEstimated = "estimated" // estimada a partir de uma idade informada
+)
+
+// AnimalAge é a idade do animal em uma data de referência
+type AnimalAge struct {
+	Years     int  `json:"years"`
+	Months    int  `json:"months"`
+	Estimated bool `json:"estimated"`
+}
+
+// AgeAt calcula a idade do animal na data informada. Retorna nil quando a data
+// de nascimento é desconhecida ou posterior à data de referência.
+func (a Animal) AgeAt(date time.Time) *AnimalAge {
+	if a.BirthDate == nil || a.BirthDate.IsZero() {
+		return nil
+	}
+	birth := a.BirthDate.Time
+	if date.Before(birth) {
+		return nil
+	}
+
+	months := (date.Year()-birth.Year())*12 + int(date.Month()) - int(birth.Month())
+	if date.Day() < birth.Day() {
+		months--
+	}
+	return &AnimalAge{
+		Years:     months / 12,
+		Months:    months % 12,
+		Estimated: a.BirthDatePrecision != "" && a.BirthDatePrecision != BirthDatePrecisionExact,
+	}
+}
+
+// RefreshAge atualiza os campos calculados Age e AgeMonths. A idade de um
+// animal falecido é a que ele tinha na data do óbito.
+func (a *Animal) RefreshAge(now time.Time) {
+	a.Age, a.AgeMonths = 0, 0
+	if a.DeceasedAt != nil && a.DeceasedAt.Before(now) {
+		now = a.DeceasedAt.Time
+	}
+	if age := a.AgeAt(now); age != nil {
+		a.Age, a.AgeMonths = age.Years, age.Months
+	}
+}
+
+// AfterFind calcula a idade sempre que o animal é carregado do banco
+func (a *Animal) AfterFind(tx *gorm.DB) error {
+	a.RefreshAge(time.Now())
+	return nil
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Papéis de um tutor em relação ao animal
+const (
+	TutorRolePrimaryOwner     = "primary_owner"     // Tutor principal (Animal.CPFTutor); o único que autoriza procedimentos
+	TutorRoleCoOwner          = "co_owner"          // Divide a guarda do animal
+	TutorRoleEmergencyContact = "emergency_contact" // Contato em emergências
+	TutorRoleAuthorizedPickup = "authorized_pickup" // Autorizado a buscar o animal
+)
+
+// AnimalTutor liga um tutor adicional a um animal com um papel. O tutor
+// principal continua em Animal.CPFTutor e não é gravado nesta tabela.
+type AnimalTutor struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;" json:"animal_tutor_id"`
+	AnimalID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_animal_tutors_animal_cpf" json:"animal_id"`
+	CPFTutor  string    `gorm:"type:varchar(14);not null;uniqueIndex:idx_animal_tutors_animal_cpf;index" json:"cpf_tutor" validate:"required,len=11|len=14"`
+	Role      string    `gorm:"type:varchar(20);not null" json:"role" validate:"required,oneof=co_owner emergency_contact authorized_pickup"`
+	AddedBy   string    `json:"added_by"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
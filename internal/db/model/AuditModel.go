@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Ações registradas no log de auditoria
+const (
+	AuditActionRestore   = "restore"
+	AuditActionPurge     = "purge"
+	AuditActionExport    = "lgpd_export"
+	AuditActionAnonymize = "lgpd_anonymize"
+)
+
+// AuditLog registra operações sensíveis sobre os dados (restauração, expurgo etc.).
+// Não tem soft delete: as entradas são mantidas mesmo depois que a entidade é removida.
+type AuditLog struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key" json:"audit_log_id"`
+	Action      string    `gorm:"type:varchar(30);not null;index" json:"action"`
+	EntityType  string    `gorm:"type:varchar(30);not null" json:"entity_type"`
+	EntityID    uuid.UUID `gorm:"type:uuid;not null;index" json:"entity_id"`
+	EntityKey   string    `gorm:"type:varchar(64);index" json:"entity_key,omitempty"` // Chave de entidades sem UUID, como o CPF do tutor
+	PerformedBy string    `json:"performed_by"`
+	Details     string    `json:"details"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// NewAuditLog cria uma entrada de auditoria com ID e horário preenchidos
+func NewAuditLog(action, entityType string, entityID uuid.UUID, performedBy, details string) *AuditLog {
+	return &AuditLog{
+		ID:          uuid.New(),
+		Action:      action,
+		EntityType:  entityType,
+		EntityID:    entityID,
+		PerformedBy: performedBy,
+		Details:     details,
+		Timestamp:   time.Now(),
+	}
+}
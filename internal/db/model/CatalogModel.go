@@ -0,0 +1,80 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Species é uma espécie do catálogo de referência. Animal.Species guarda o NameEN
+// da espécie; Code é o identificador estável usado pelas migrações de carga.
+type Species struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key;" json:"species_id"`
+	Code      string         `gorm:"type:varchar(50);not null;uniqueIndex" json:"code" validate:"required,max=50"`
+	NamePT    string         `gorm:"not null" json:"name_pt" validate:"required,min=2,max=100"`
+	NameEN    string         `gorm:"not null" json:"name_en" validate:"required,min=2,max=100"`
+	Aliases   pq.StringArray `gorm:"type:text[]" json:"aliases"` // Outros nomes aceitos na entrada (ex.: "canino", "cachorro")
+	Breeds    []Breed        `gorm:"foreignKey:SpeciesID" json:"breeds,omitempty"`
+	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+}
+
+// Breed é uma raça do catálogo, ligada à sua espécie. Animal.Breed guarda o NameEN da raça.
+type Breed struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key;" json:"breed_id"`
+	SpeciesID uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_breeds_species_code" json:"species_id"`
+	Code      string         `gorm:"type:varchar(50);not null;uniqueIndex:idx_breeds_species_code" json:"code" validate:"required,max=50"`
+	NamePT    string         `gorm:"not null" json:"name_pt" validate:"required,min=2,max=100"`
+	NameEN    string         `gorm:"not null" json:"name_en" validate:"required,min=2,max=100"`
+	Aliases   pq.StringArray `gorm:"type:text[]" json:"aliases"`
+	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+}
+
+// Matches indica se o nome informado corresponde à espécie (código, nomes ou apelidos)
+func (s Species) Matches(name string) bool {
+	return catalogNameMatches(name, s.Code, s.NamePT, s.NameEN, s.Aliases)
+}
+
+// Matches indica se o nome informado corresponde à raça (código, nomes ou apelidos)
+func (b Breed) Matches(name string) bool {
+	return catalogNameMatches(name, b.Code, b.NamePT, b.NameEN, b.Aliases)
+}
+
+func catalogNameMatches(name, code, namePT, nameEN string, aliases []string) bool {
+	normalized := NormalizeName(name)
+	if normalized == "" {
+		return false
+	}
+	for _, candidate := range append([]string{code, namePT, nameEN}, aliases...) {
+		if NormalizeName(candidate) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// NormalizeName deixa o nome em minúsculas, sem acentos e sem espaços extras,
+// para comparar nomes digitados de formas diferentes
+func NormalizeName(name string) string {
+	return accentReplacer.Replace(strings.Join(strings.Fields(strings.ToLower(name)), " "))
+}
+
+// CatalogCode gera um código a partir de um nome (ex.: "Golden Retriever" -> "golden_retriever")
+func CatalogCode(name string) string {
+	return strings.ReplaceAll(NormalizeName(name), " ", "_")
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Gravidade de alergias e condições crônicas
+const (
+	SeverityMild     = "mild"
+	SeverityModerate = "moderate"
+	SeveritySevere   = "severe"
+)
+
+// Allergy é uma alergia registrada para o animal, com os princípios ativos que a provocam
+type Allergy struct {
+	ID               uuid.UUID      `gorm:"type:uuid;primary_key;" json:"allergy_id"`
+	AnimalID         uuid.UUID      `gorm:"type:uuid;not null;index" json:"animal_id"`
+	Allergen         string         `gorm:"not null" json:"allergen" validate:"required,min=2,max=100"` // Ex.: "Penicilinas"
+	ActivePrinciples pq.StringArray `gorm:"type:text[]" json:"active_principles" validate:"required,min=1,dive,required"`
+	Severity         string         `gorm:"type:varchar(10);not null" json:"severity" validate:"required,oneof=mild moderate severe"`
+	Reaction         string         `json:"reaction" validate:"max=255"` // Reação observada (ex.: urticária, anafilaxia)
+	RecordedBy       string         `json:"recorded_by"`
+	CreatedAt        time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+}
+
+// ChronicCondition é uma condição crônica do animal (ex.: doença renal, epilepsia),
+// com os princípios ativos contraindicados por ela
+type ChronicCondition struct {
+	ID               uuid.UUID      `gorm:"type:uuid;primary_key;" json:"condition_id"`
+	AnimalID         uuid.UUID      `gorm:"type:uuid;not null;index" json:"animal_id"`
+	Name             string         `gorm:"not null" json:"name" validate:"required,min=2,max=100"`
+	ActivePrinciples pq.StringArray `gorm:"type:text[]" json:"active_principles" validate:"dive,required"` // Contraindicados pela condição
+	Severity         string         `gorm:"type:varchar(10);not null" json:"severity" validate:"required,oneof=mild moderate severe"`
+	DiagnosedAt      *CustomDate    `json:"diagnosed_at" gorm:"type:date"`
+	Notes            string         `json:"notes" validate:"max=255"`
+	RecordedBy       string         `json:"recorded_by"`
+	CreatedAt        time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Canais pelos quais o tutor pode ser contatado
+const (
+	ConsentChannelWhatsApp = "whatsapp"
+	ConsentChannelSMS      = "sms"
+	ConsentChannelEmail    = "email"
+)
+
+// Finalidades do contato
+const (
+	ConsentPurposeClinicalReminders = "clinical_reminders" // Lembretes de vacinas, retornos e consultas
+	ConsentPurposeMarketing         = "marketing"          // Promoções e novidades da clínica
+)
+
+// Origens do consentimento ou da revogação
+const (
+	ConsentSourceReception = "reception" // Registrado pela recepção
+	ConsentSourcePhone     = "phone"     // Por telefone
+	ConsentSourceForm      = "form"      // Termo assinado
+	ConsentSourcePortal    = "portal"    // Pelo próprio tutor, no portal
+	ConsentSourceImport    = "import"    // Importado de sistema anterior
+)
+
+// TutorConsent registra a autorização do tutor para ser contatado por um canal
+// com uma finalidade. Os registros não são alterados, exceto para registrar a
+// revogação; um novo consentimento depois da revogação gera outro registro.
+type TutorConsent struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key" json:"consent_id"`
+	CPFTutor         string     `gorm:"type:varchar(14);not null;index" json:"cpf_tutor" validate:"required,len=11|len=14"`
+	Channel          string     `gorm:"type:varchar(20);not null" json:"channel" validate:"required,oneof=whatsapp sms email"`
+	Purpose          string     `gorm:"type:varchar(30);not null" json:"purpose" validate:"required,oneof=clinical_reminders marketing"`
+	GrantedAt        time.Time  `gorm:"not null" json:"granted_at"`
+	Source           string     `gorm:"type:varchar(20);not null" json:"source" validate:"required,oneof=reception phone form portal import"`
+	RecordedBy       string     `json:"recorded_by"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RevocationSource string     `gorm:"type:varchar(20)" json:"revocation_source,omitempty" validate:"omitempty,oneof=reception phone form portal import"`
+	RevokedBy        string     `json:"revoked_by,omitempty"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Active indica se o consentimento está em vigor
+func (c *TutorConsent) Active() bool {
+	return c.RevokedAt == nil
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Destinação do corpo do animal
+const (
+	BodyDispositionCremation = "cremation"
+	BodyDispositionBurial    = "burial"
+)
+
+// DeathRecord registra o óbito do animal; cada animal tem no máximo um registro
+type DeathRecord struct {
+	ID              uuid.UUID      `gorm:"type:uuid;primary_key;" json:"death_record_id"`
+	AnimalID        uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex" json:"animal_id"`
+	DateOfDeath     CustomDate     `gorm:"type:date;not null" json:"date_of_death" validate:"required"`
+	Cause           string         `gorm:"not null" json:"cause" validate:"required,min=3,max=255"`
+	Euthanasia      bool           `json:"euthanasia"`
+	CRVM            string         `gorm:"column:crvm;not null" json:"crvm" validate:"required"` // Veterinário que atestou o óbito ou autorizou a eutanásia
+	BodyDisposition string         `gorm:"type:varchar(10);not null" json:"body_disposition" validate:"required,oneof=cremation burial"`
+	Notes           string         `json:"notes" validate:"max=255"`
+	AuthorizedBy    string         `gorm:"type:varchar(14)" json:"authorized_by,omitempty"` // CPF ou CNPJ do tutor principal que autorizou a eutanásia
+	RecordedBy      string         `json:"recorded_by"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+}
+
+// IsDeceased indica se o óbito do animal foi registrado
+func (a Animal) IsDeceased() bool {
+	return a.DeceasedAt != nil
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LegalRepresentative é uma pessoa que responde por um tutor organização (ONG,
+// abrigo, fazenda) e pode ser contatada em nome dela
+type LegalRepresentative struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"representative_id"`
+	CNPJ      string    `gorm:"column:cnpj;type:varchar(14);not null;index" json:"cnpj"` // Tutor organização; referencia tutors.cpf_tutor
+	Name      string    `json:"name" validate:"required,min=2,max=100"`
+	CPF       string    `gorm:"type:char(11)" json:"cpf" validate:"required,cpf"`
+	Position  string    `json:"position" validate:"max=100"`      // Cargo na organização
+	Email     string    `json:"email" validate:"omitempty,email"` // Ao menos e-mail ou telefone
+	Phone     string    `json:"phone" validate:"omitempty,min=10,max=15"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
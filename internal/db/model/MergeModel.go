@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnimalMerge registra a fusão de um cadastro duplicado (origem) em outro (destino).
+// O animal de origem é excluído e seus registros passam a apontar para o destino.
+type AnimalMerge struct {
+	ID                    uuid.UUID `gorm:"type:uuid;primary_key;" json:"merge_id"`
+	SourceAnimalID        uuid.UUID `gorm:"type:uuid;not null;index" json:"source_animal_id"`
+	TargetAnimalID        uuid.UUID `gorm:"type:uuid;not null;index" json:"target_animal_id"`
+	MergedBy              string    `json:"merged_by"`
+	MovedConsultations    int64     `json:"moved_consultations"`
+	MovedDosages          int64     `json:"moved_dosages"`
+	MovedHospitalizations int64     `json:"moved_hospitalizations"`
+	MovedWeightRecords    int64     `json:"moved_weight_records"`
+	MovedClinicalRecords  int64     `json:"moved_clinical_records"` // Alergias e condições crônicas
+	MovedVaccinations     int64     `json:"moved_vaccinations"`
+	MovedImage            bool      `json:"moved_image"`
+	Timestamp             time.Time `json:"timestamp"`
+	CreatedAt             time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OwnershipTransfer registra a passagem de um animal de um tutor para outro
+type OwnershipTransfer struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primary_key;" json:"transfer_id"`
+	AnimalID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"animal_id"`
+	PreviousCPF   string         `gorm:"type:varchar(14);not null" json:"previous_cpf_tutor"`
+	NewCPF        string         `gorm:"type:varchar(14);not null" json:"new_cpf_tutor"`
+	EffectiveDate CustomDate     `gorm:"not null" json:"effective_date"`
+	Reason        string         `json:"reason" validate:"required,min=3,max=255"`
+	TransferredBy string         `json:"transferred_by"`
+	CreatedAt     time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+}
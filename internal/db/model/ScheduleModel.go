@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Motivos de ausência do veterinário
+const (
+	AbsenceVacation  = "vacation"
+	AbsenceCourse    = "course"
+	AbsenceSickLeave = "sick_leave"
+	AbsenceOther     = "other"
+)
+
+// WorkingHours é um intervalo de atendimento semanal do veterinário. Um dia pode ter
+// mais de um intervalo, como manhã e tarde; dias sem intervalo são folga.
+type WorkingHours struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"working_hours_id"`
+	CRVM      string    `gorm:"column:crvm;type:varchar(12);not null;index" json:"crvm"` // Referencia veterinaries.crvm
+	Weekday   int       `gorm:"not null" json:"weekday" validate:"min=0,max=6"`          // 0 = domingo, como em time.Weekday
+	StartTime string    `gorm:"type:char(5);not null" json:"start_time" validate:"required,datetime=15:04"`
+	EndTime   string    `gorm:"type:char(5);not null" json:"end_time" validate:"required,datetime=15:04"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// AvailabilityException é uma ausência do veterinário entre duas datas, inclusive.
+// Sem horário, vale para os dias inteiros; com horário, apenas para esse intervalo
+// em cada dia.
+type AvailabilityException struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key" json:"exception_id"`
+	CRVM      string     `gorm:"column:crvm;type:varchar(12);not null;index" json:"crvm"` // Referencia veterinaries.crvm
+	StartDate CustomDate `gorm:"type:date;not null;index" json:"start_date" validate:"required"`
+	EndDate   CustomDate `gorm:"type:date;not null;index" json:"end_date" validate:"required"`
+	StartTime string     `gorm:"type:varchar(5)" json:"start_time,omitempty" validate:"omitempty,datetime=15:04"`
+	EndTime   string     `gorm:"type:varchar(5)" json:"end_time,omitempty" validate:"omitempty,datetime=15:04"`
+	Reason    string     `gorm:"type:varchar(20);not null" json:"reason" validate:"required,oneof=vacation course sick_leave other"`
+	Notes     string     `json:"notes" validate:"max=255"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// IsFullDay indica se a ausência vale para os dias inteiros
+func (e AvailabilityException) IsFullDay() bool {
+	return e.StartTime == "" && e.EndTime == ""
+}
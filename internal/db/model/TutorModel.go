@@ -3,27 +3,29 @@ package model
 import (
 	"errors"
 	"strconv"
+	"time"
 )
 
 
 
 type Tutor struct {
     User
-    CPFTutor string `json:"cpf_tutor" gorm:"type:char(11);primary_key" validate:"required,len=11,cpf"`
-    Name     string `json:"name" validate:"required,min=2,max=100"`
-    Address  string `json:"address" validate:"required,min=5,max=255"`
-}
-
-type Veterinarian struct {
-    User
-    CRMV     string `json:"crmv" gorm:"type:char(10);primary_key" validate:"required,len=10"`
-    Name     string `json:"name" validate:"required,min=2,max=100"`
-    Specialty string `json:"specialty" validate:"required,min=2,max=100"`
-}
-
-func NewTutor(cpf, name, email, phone, address, password string) (*Tutor, error) {
-    if !isValidCPF(cpf) {
-        return nil, errors.New("CPF inválido")
+    CPFTutor     string     `json:"cpf_tutor" gorm:"type:varchar(14);primary_key;uniqueIndex" validate:"required,document"` // CPF da pessoa ou CNPJ da organização; referenciado por animals.cpf_tutor
+    OwnerType    string     `json:"owner_type" gorm:"type:varchar(20);not null;default:person" validate:"required,oneof=person organization"`
+    Name         string     `json:"name" validate:"required,min=2,max=100"`
+    Address      Address    `json:"address" gorm:"embedded;embeddedPrefix:address_"`
+    AddressText  string     `json:"address_text,omitempty" gorm:"column:address" validate:"max=255"` // Endereço em texto livre, dos cadastros anteriores ao endereço estruturado
+    Active       bool       `json:"active" gorm:"not null;default:true"`     // Tutores desativados não recebem novos animais
+    FirebaseUID  *string    `json:"-" gorm:"type:varchar(128);uniqueIndex"` // Usuário do Firebase com que o tutor acessa o portal
+    AnonymizedAt *time.Time `json:"anonymized_at,omitempty"`                // Preenchido quando os dados pessoais são anonimizados (LGPD)
+    CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+    UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// NewTutor cria um tutor pessoa física (CPF) ou organização (CNPJ), conforme o documento
+func NewTutor(cpf, name, email, phone string, address Address, password string) (*Tutor, error) {
+    if !IsValidDocument(cpf) {
+        return nil, errors.New("CPF ou CNPJ inválido")
     }
     return &Tutor{
         User: User{
@@ -31,9 +33,11 @@ func NewTutor(cpf, name, email, phone, address, password string) (*Tutor, error)
             Phone:    phone,
             UserType: TutorType,
         },
-        CPFTutor: cpf,
-        Name:     name,
-        Address:  address,
+        CPFTutor:  cpf,
+        OwnerType: OwnerTypeOf(cpf),
+        Name:      name,
+        Address:   address.Normalize(),
+        Active:    true,
     }, nil
 }
 
@@ -53,7 +57,7 @@ func (t *Tutor) GetPhone() string {
 	return t.Phone
 }
 
-func (t *Tutor) GetAddress() string {
+func (t *Tutor) GetAddress() Address {
 	return t.Address
 }
 
@@ -74,8 +78,8 @@ func (t *Tutor) SetPhone(phone string) {
 	t.Phone = phone
 }
 
-func (t *Tutor) SetAddress(address string) {
-	t.Address = address
+func (t *Tutor) SetAddress(address Address) {
+	t.Address = address.Normalize()
 }
 
 func cleanCPF(cpf string) string {
@@ -103,7 +107,7 @@ func calculateDigit(cpf string, multiplier int) int {
 }
 
 // Valida o CPF
-func isValidCPF(cpf string) bool {
+func IsValidCPF(cpf string) bool {
     cpf = cleanCPF(cpf)
     
     if len(cpf) != 11 {
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VaccinationProtocol define o esquema de uma vacina para uma espécie: quantas doses
+// tem a série inicial, o intervalo entre elas e de quanto em quanto tempo é o reforço
+type VaccinationProtocol struct {
+	ID                  uuid.UUID      `gorm:"type:uuid;primary_key;" json:"protocol_id"`
+	Code                string         `gorm:"type:varchar(50);not null;uniqueIndex" json:"code" validate:"required,max=50"` // Ex.: "dog_v10"
+	Name                string         `gorm:"not null" json:"name" validate:"required,min=2,max=100"`
+	Species             string         `gorm:"not null;index" json:"species" validate:"required"` // Nome em inglês da espécie no catálogo
+	Doses               int            `json:"doses" validate:"gte=1"`                            // Doses da série inicial
+	DoseIntervalDays    int            `json:"dose_interval_days" validate:"gte=0"`               // Intervalo entre as doses da série inicial
+	BoosterIntervalDays int            `json:"booster_interval_days" validate:"gte=0"`            // Intervalo dos reforços; 0 quando não há reforço
+	Description         string         `json:"description" validate:"max=255"`
+	CreatedAt           time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt           gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+}
+
+// NextDueDate calcula quando a próxima dose vence a partir da dose aplicada. Devolve
+// nil quando a série terminou e o protocolo não tem reforço.
+func (p VaccinationProtocol) NextDueDate(doseNumber int, appliedAt time.Time) *CustomDate {
+	days := p.BoosterIntervalDays
+	if doseNumber < p.Doses {
+		days = p.DoseIntervalDays
+	}
+	if days <= 0 {
+		return nil
+	}
+	return &CustomDate{Time: appliedAt.AddDate(0, 0, days)}
+}
+
+// VaccineProduct é uma vacina comercial que segue um protocolo. Os lotes do produto
+// são medicamentos do estoque com VaccineProductID apontando para ele.
+type VaccineProduct struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primary_key;" json:"product_id"`
+	Name         string         `gorm:"not null" json:"name" validate:"required,min=2,max=100"`
+	Manufacturer string         `json:"manufacturer" validate:"max=100"`
+	ProtocolID   uuid.UUID      `gorm:"type:uuid;not null;index" json:"protocol_id" validate:"required"`
+	CreatedAt    time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+}
+
+// VaccinationRecord é uma dose de vacina aplicada no animal
+type VaccinationRecord struct {
+	ID             uuid.UUID      `gorm:"type:uuid;primary_key;" json:"vaccination_id"`
+	AnimalID       uuid.UUID      `gorm:"type:uuid;not null;index" json:"animal_id"`
+	ProductID      uuid.UUID      `gorm:"type:uuid;not null" json:"product_id" validate:"required"`
+	ProtocolID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"protocol_id"`
+	MedicationID   *uuid.UUID     `gorm:"type:uuid" json:"medication_id"` // Lote do estoque usado, quando houver
+	DoseNumber     int            `json:"dose_number" validate:"gte=1"`
+	LotNumber      string         `json:"lot_number" validate:"required,max=50"`
+	AppliedAt      CustomDate     `gorm:"type:date;not null" json:"applied_at"`
+	NextDueDate    *CustomDate    `gorm:"type:date;index" json:"next_due_date"` // Calculada pelo protocolo
+	AppliedBy      string         `json:"applied_by"`
+	ConsultationID *uuid.UUID     `gorm:"type:uuid" json:"consultation_id"`
+	Notes          string         `json:"notes" validate:"max=255"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+}
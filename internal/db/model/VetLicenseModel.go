@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LicenseGraceDays é o prazo, contado do cadastro da inscrição, para informar a
+// validade. Depois dele, a inscrição sem validade deixa de valer.
+const LicenseGraceDays = 30
+
+// VetLicense é uma inscrição do veterinário no conselho regional de uma UF. A
+// inscrição principal é o CRMV do cadastro; as secundárias permitem atuar em
+// outras UFs e têm número próprio.
+type VetLicense struct {
+	ID        uuid.UUID   `gorm:"type:uuid;primary_key" json:"license_id"`
+	CRVM      string      `gorm:"column:crvm;type:varchar(12);not null;uniqueIndex:idx_vet_licenses_crvm_uf" json:"crvm"` // Veterinário; referencia veterinaries.crvm
+	UF        string      `gorm:"type:char(2);not null;uniqueIndex:idx_vet_licenses_crvm_uf" json:"uf" validate:"required,uf"`
+	Number    string      `gorm:"type:varchar(12);not null" json:"number" validate:"required,crmv"` // Inscrição na UF, no formato número-UF
+	ExpiresAt *CustomDate `gorm:"type:date;index" json:"expires_at"`                                // Nulo quando a validade não foi informada
+	Active    bool        `gorm:"-" json:"active"`                                                  // Calculado na leitura
+	CreatedAt time.Time   `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time   `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// NewPrimaryLicense cria a inscrição principal do veterinário a partir do CRMV do
+// cadastro, já normalizado, com a validade a informar em até LicenseGraceDays dias
+func NewPrimaryLicense(crvm string) VetLicense {
+	return VetLicense{ID: uuid.New(), CRVM: crvm, UF: crvm[len(crvm)-2:], Number: crvm}
+}
+
+// ValidUntil retorna o último dia em que a inscrição vale: o vencimento ou, sem
+// validade informada, o fim do prazo de LicenseGraceDays a partir do cadastro
+func (l VetLicense) ValidUntil() time.Time {
+	if l.ExpiresAt == nil {
+		created := time.Date(l.CreatedAt.Year(), l.CreatedAt.Month(), l.CreatedAt.Day(), 0, 0, 0, 0, time.UTC)
+		return created.AddDate(0, 0, LicenseGraceDays)
+	}
+	return time.Date(l.ExpiresAt.Year(), l.ExpiresAt.Month(), l.ExpiresAt.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// IsActiveOn indica se a inscrição vale na data: até o dia de ValidUntil, inclusive
+func (l VetLicense) IsActiveOn(date time.Time) bool {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return !day.After(l.ValidUntil())
+}
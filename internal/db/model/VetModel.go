@@ -3,41 +3,71 @@ package model
 import (
 	"errors"
 	"regexp"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// Veterinary é o veterinário da clínica, identificado pelo CRMV. Os dados de
+// contato e o tipo de usuário vêm de User.
 type Veterinary struct {
-	CRVM      string         `gorm:"column:crvm;type:char(12);primary_key" json:"crvm" validate:"required,len=12"`
-	Name      string         `json:"name" validate:"required,min=2,max=100"`
-	LastName  string         `json:"last_name" validate:"required,min=2,max=100"`
-	Email     string         `json:"email" validate:"required,email"`
-	Phone     string         `json:"phone" validate:"required,min=10,max=15"`
-	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+	User
+	CRVM        string         `gorm:"column:crvm;type:varchar(12);primary_key;uniqueIndex" json:"crvm" validate:"required,crmv"` // Número do CRMV e UF do conselho (ex.: 123456-SP); referenciado por consultations.crvm
+	Name        string         `json:"name" validate:"required,min=2,max=100"`
+	LastName    string         `json:"last_name" validate:"max=100"`
+	Specialty   string         `json:"specialty" validate:"max=100"`
+	FirebaseUID *string        `json:"-" gorm:"type:varchar(128);uniqueIndex"` // Usuário do Firebase com que o veterinário acessa o sistema
+	HoursSetAt  *time.Time     `json:"-"`                                      // Última gravação do horário semanal; nulo enquanto ele nunca foi cadastrado
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
 }
 
-func isValidCRVM(crvm string) bool {
-	re := regexp.MustCompile(`^[0-9]{6,8}-[A-Z]{2}$`)
-	return re.MatchString(crvm)
+var crmvPattern = regexp.MustCompile(`^[0-9]{6,8}-[A-Z]{2}$`)
+
+// CleanCRMV padroniza o CRMV no formato número-UF. Aceita as formas usuais de
+// escrita, como "CRMV-SP 123456", "SP-123456" e "123456/SP".
+func CleanCRMV(crmv string) string {
+	upper := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(crmv)), "CRMV")
+	var digits, letters strings.Builder
+	for _, char := range upper {
+		switch {
+		case char >= '0' && char <= '9':
+			digits.WriteRune(char)
+		case char >= 'A' && char <= 'Z':
+			letters.WriteRune(char)
+		case char != '-' && char != '/' && char != ' ' && char != '.':
+			return upper
+		}
+	}
+	if digits.Len() == 0 || letters.Len() != 2 {
+		return upper
+	}
+	return digits.String() + "-" + letters.String()
+}
+
+// IsValidCRMV valida o CRMV no formato número-UF, com a UF de um conselho regional
+func IsValidCRMV(crmv string) bool {
+	return crmvPattern.MatchString(crmv) && IsValidUF(crmv[len(crmv)-2:])
 }
 
-func NewVeterinarian(crmv, name, email, phone, specialty string) (*Veterinarian, error) {
-    if !isValidCRVM(crmv) {
-        return nil, errors.New("CRMV inválido")
-    }
-    return &Veterinarian{
-        User: User{
-            Email:    email,
-            Phone:    phone,
-            UserType: VeterinarianType,
-        },
-        CRMV:      crmv,
-        Name:      name,
-        Specialty: specialty,
-    }, nil
+// NewVeterinarian cria um veterinário com o CRMV padronizado
+func NewVeterinarian(crmv, name, email, phone, specialty string) (*Veterinary, error) {
+	crmv = CleanCRMV(crmv)
+	if !IsValidCRMV(crmv) {
+		return nil, errors.New("CRMV inválido")
+	}
+	return &Veterinary{
+		User: User{
+			Email:    email,
+			Phone:    phone,
+			UserType: VeterinarianType,
+		},
+		CRVM:      crmv,
+		Name:      name,
+		Specialty: specialty,
+	}, nil
 }
 
 func (v *Veterinary) GetCRVM() string {
@@ -61,7 +91,8 @@ func (v *Veterinary) GetPhone() string {
 }
 
 func (v *Veterinary) SetCRVM(crvm string) error {
-	if !isValidCRVM(crvm) {
+	crvm = CleanCRMV(crvm)
+	if !IsValidCRMV(crvm) {
 		return errors.New("CRVM inválido")
 	}
 	v.CRVM = crvm
@@ -90,19 +121,3 @@ func (v *Veterinary) Update(name, lastName, email, phone string) {
 	v.Email = email
 	v.Phone = phone
 }
-
-func (v *Veterinary) Validate() error {
-	if v.Name == "" {
-		return errors.New("nome é obrigatório")
-	}
-	if v.LastName == "" {
-		return errors.New("sobrenome é obrigatório")
-	}
-	if v.Email == "" {
-		return errors.New("email é obrigatório")
-	}
-	if v.Phone == "" {
-		return errors.New("telefone é obrigatório")
-	}
-	return nil
-}
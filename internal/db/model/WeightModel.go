@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	WeightUnitKg = "kg"
+	WeightUnitG  = "g"
+	WeightUnitLb = "lb"
+)
+
+const poundInKg = 0.45359237
+
+// WeightRecord é uma pesagem do animal; a série de pesagens forma a curva de peso
+type WeightRecord struct {
+	ID             uuid.UUID      `gorm:"type:uuid;primary_key;" json:"weight_record_id"`
+	AnimalID       uuid.UUID      `gorm:"type:uuid;not null;index" json:"animal_id" validate:"required"`
+	Date           CustomDate     `gorm:"not null" json:"date" validate:"required"`
+	Weight         float64        `gorm:"not null" json:"weight" validate:"gt=0"`
+	Unit           string         `gorm:"type:varchar(2);not null" json:"unit" validate:"required,oneof=kg g lb"`
+	RecordedBy     string         `json:"recorded_by"`
+	ConsultationID *uuid.UUID     `gorm:"type:uuid" json:"consultation_id"` // Relacionamento opcional
+	CreatedAt      time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+}
+
+// WeightInKg converte o peso registrado para quilogramas, unidade usada em Animal.Weight
+func (w WeightRecord) WeightInKg() float64 {
+	switch w.Unit {
+	case WeightUnitG:
+		return w.Weight / 1000
+	case WeightUnitLb:
+		return w.Weight * poundInKg
+	default:
+		return w.Weight
+	}
+}
@@ -0,0 +1,102 @@
+package model
+
+import "strings"
+
+// Address é o endereço estruturado de um tutor. O CEP é gravado só com os
+// dígitos e a UF com a sigla em maiúsculas.
+type Address struct {
+	CEP          string `json:"cep" gorm:"type:char(8);index" validate:"omitempty,cep"`
+	Street       string `json:"street" validate:"max=255"`                    // Logradouro
+	Number       string `json:"number" validate:"max=20"`                     // Vazio ou "S/N" quando não há número
+	Complement   string `json:"complement" validate:"max=100"`                // Apartamento, bloco, referência
+	Neighborhood string `json:"neighborhood" gorm:"index" validate:"max=100"` // Bairro
+	City         string `json:"city" gorm:"index" validate:"max=100"`
+	UF           string `json:"uf" gorm:"type:char(2);index" validate:"omitempty,uf"`
+}
+
+// UFs são as siglas das 27 unidades da federação
+var UFs = []string{
+	"AC", "AL", "AP", "AM", "BA", "CE", "DF", "ES", "GO", "MA", "MT", "MS", "MG", "PA",
+	"PB", "PR", "PE", "PI", "RJ", "RN", "RS", "RO", "RR", "SC", "SP", "SE", "TO",
+}
+
+// CleanCEP descarta a pontuação do CEP, mantendo apenas os dígitos
+func CleanCEP(cep string) string {
+	var cleaned strings.Builder
+	for _, char := range cep {
+		if char >= '0' && char <= '9' {
+			cleaned.WriteRune(char)
+		}
+	}
+	return cleaned.String()
+}
+
+// IsValidCEP valida o formato do CEP: 8 dígitos, com ou sem hífen. CEPs com todos
+// os dígitos zerados não existem.
+func IsValidCEP(cep string) bool {
+	cep = strings.TrimSpace(cep)
+	if len(cep) == 9 && cep[5] == '-' {
+		cep = cep[:5] + cep[6:]
+	}
+	return len(cep) == 8 && CleanCEP(cep) == cep && cep != "00000000"
+}
+
+// IsValidUF indica se a sigla é de uma unidade da federação
+func IsValidUF(uf string) bool {
+	for _, candidate := range UFs {
+		if candidate == uf {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize apara os campos do endereço, grava o CEP sem hífen e a UF em maiúsculas
+func (a Address) Normalize() Address {
+	a.CEP = strings.TrimSpace(a.CEP)
+	if IsValidCEP(a.CEP) {
+		a.CEP = CleanCEP(a.CEP)
+	}
+	a.Street = strings.TrimSpace(a.Street)
+	a.Number = strings.TrimSpace(a.Number)
+	a.Complement = strings.TrimSpace(a.Complement)
+	a.Neighborhood = strings.TrimSpace(a.Neighborhood)
+	a.City = strings.TrimSpace(a.City)
+	a.UF = strings.ToUpper(strings.TrimSpace(a.UF))
+	return a
+}
+
+// IsZero indica se nenhum campo do endereço foi preenchido
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// IsComplete indica se o endereço tem CEP, logradouro, bairro, cidade e UF. Número
+// e complemento são opcionais.
+func (a Address) IsComplete() bool {
+	return a.CEP != "" && a.Street != "" && a.Neighborhood != "" && a.City != "" && a.UF != ""
+}
+
+// String formata o endereço em uma linha, como em uma etiqueta de correspondência
+func (a Address) String() string {
+	var parts []string
+	street := a.Street
+	if a.Number != "" {
+		street += ", " + a.Number
+	}
+	if a.Complement != "" {
+		street += " - " + a.Complement
+	}
+	for _, part := range []string{street, a.Neighborhood} {
+		if part = strings.Trim(part, " ,-"); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if city := strings.Trim(a.City+"/"+a.UF, "/"); city != "" {
+		parts = append(parts, city)
+	}
+	if len(a.CEP) == 8 {
+		parts = append(parts, "CEP "+a.CEP[:5]+"-"+a.CEP[5:])
+	}
+	return strings.Join(parts, " - ")
+}
@@ -0,0 +1,76 @@
+package model
+
+import "strings"
+
+// Tipos de tutor: pessoa física, identificada pelo CPF, ou organização (ONG,
+// abrigo, fazenda), identificada pelo CNPJ
+const (
+	OwnerTypePerson       = "person"
+	OwnerTypeOrganization = "organization"
+)
+
+// CleanDocument descarta a pontuação de um CPF ou CNPJ, mantendo dígitos e as
+// letras do CNPJ alfanumérico em maiúsculas
+func CleanDocument(document string) string {
+	var cleaned strings.Builder
+	for _, char := range strings.ToUpper(document) {
+		if (char >= '0' && char <= '9') || (char >= 'A' && char <= 'Z') {
+			cleaned.WriteRune(char)
+		}
+	}
+	return cleaned.String()
+}
+
+// IsValidDocument valida o documento de um tutor: CPF (11 dígitos) ou CNPJ (14 caracteres)
+func IsValidDocument(document string) bool {
+	switch document = CleanDocument(document); len(document) {
+	case 11:
+		return IsValidCPF(document)
+	case 14:
+		return IsValidCNPJ(document)
+	default:
+		return false
+	}
+}
+
+// OwnerTypeOf indica o tipo de tutor pelo documento: CNPJ para organizações, CPF para pessoas
+func OwnerTypeOf(document string) string {
+	if len(CleanDocument(document)) == 14 {
+		return OwnerTypeOrganization
+	}
+	return OwnerTypePerson
+}
+
+// IsValidCNPJ valida os dígitos verificadores do CNPJ. Aceita o CNPJ alfanumérico:
+// os 12 primeiros caracteres podem ter letras, que valem o código ASCII menos 48,
+// e os dois dígitos verificadores são sempre numéricos.
+func IsValidCNPJ(cnpj string) bool {
+	cnpj = CleanDocument(cnpj)
+	if len(cnpj) != 14 || strings.Count(cnpj, cnpj[:1]) == 14 {
+		return false
+	}
+	for _, char := range cnpj[12:] {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+
+	first := cnpjDigit(cnpj[:12])
+	second := cnpjDigit(cnpj[:12] + string(rune('0'+first)))
+	return cnpj[12] == byte('0'+first) && cnpj[13] == byte('0'+second)
+}
+
+// cnpjDigit calcula um dígito verificador do CNPJ com pesos de 2 a 9, da direita para a esquerda
+func cnpjDigit(base string) int {
+	sum, weight := 0, 2
+	for i := len(base) - 1; i >= 0; i-- {
+		sum += int(base[i]-'0') * weight
+		if weight++; weight > 9 {
+			weight = 2
+		}
+	}
+	if remainder := sum % 11; remainder >= 2 {
+		return 11 - remainder
+	}
+	return 0
+}
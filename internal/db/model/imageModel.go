@@ -1,10 +1,16 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 
 
 type ImageModel struct {
     ID    uuid.UUID   `gorm:"primaryKey" type:"uuid"`
     Image []byte `gorm:"type:bytea"`
+    CreatedAt time.Time `gorm:"autoCreateTime;default:CURRENT_TIMESTAMP"` // Imagens antigas recebem a data da migração
+    UpdatedAt time.Time `gorm:"autoUpdateTime;default:CURRENT_TIMESTAMP"`
 }
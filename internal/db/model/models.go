@@ -3,6 +3,7 @@ package model
 import (
 	"database/sql/driver"
 	"fmt"
+	"strings"
 
 	"time"
 
@@ -12,19 +13,24 @@ import (
 )
 
 type Animal struct {
-	ID          uuid.UUID      `gorm:"type:uuid;primary_key;" json:"animal_id"`
-	Name        string         `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
-	Species     string         `json:"species" gorm:"not null" validate:"required"`
-	Breed       string         `json:"breed" gorm:"not null" validate:"required"`
-	Age         int            `json:"age" validate:"gte=0"`
-	Weight      float64        `json:"weight" validate:"gte=0"`
-	Image       uuid.UUID         `json:"image" gorm:"type:uuid"` // Armazenando imagem em bytea
-	Description string         `json:"description"`
-	Timestamp   time.Time      `json:"timestamp" gorm:"autoCreateTime"`
-	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
-	CPFTutor    string         `gorm:"type:char(11);not null" json:"cpf_tutor" validate:"required,len=11"`
+	ID                 uuid.UUID      `gorm:"type:uuid;primary_key;" json:"animal_id"`
+	Name               string         `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
+	Species            string         `json:"species" gorm:"not null" validate:"required"`
+	Breed              string         `json:"breed" gorm:"not null" validate:"required"`
+	Age                int            `json:"age" gorm:"-" validate:"gte=0"` // Idade em anos completos, calculada a partir de BirthDate
+	AgeMonths          int            `json:"age_months" gorm:"-"`           // Meses além dos anos completos
+	BirthDate          *CustomDate    `json:"birth_date" gorm:"type:date"`
+	BirthDatePrecision string         `json:"birth_date_precision" gorm:"type:varchar(10)" validate:"omitempty,oneof=exact month year estimated"`
+	Weight             float64        `json:"weight" validate:"gte=0"`
+	Microchip          *string        `json:"microchip" gorm:"type:varchar(15);uniqueIndex:idx_animals_microchip_active,where:deleted_at IS NULL"` // Único entre animais ativos
+	Image              uuid.UUID      `json:"image" gorm:"type:uuid"`                                                                              // Armazenando imagem em bytea
+	Description        string         `json:"description"`
+	DeceasedAt         *CustomDate    `json:"deceased_at" gorm:"type:date"` // Preenchida pelo registro de óbito
+	Timestamp          time.Time      `json:"timestamp" gorm:"autoCreateTime"`
+	CreatedAt          time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt          time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+	CPFTutor           string         `gorm:"type:varchar(14);not null" json:"cpf_tutor" validate:"required,len=11|len=14"`
 }
 
 type ConsultationDosage struct {
@@ -47,6 +53,9 @@ type Dosage struct {
     Dosage             string         `json:"dosage" validate:"required"`
     ConsultationID     *uuid.UUID     `gorm:"type:uuid" json:"consultation_id"` // Relacionamento opcional
     HospitalizationID  *uuid.UUID     `gorm:"type:uuid" json:"hospitalization_id"` // Relacionamento opcional
+    CRVM               string         `gorm:"column:crvm;type:varchar(12)" json:"crvm"` // Veterinário que assina a prescrição; vazio nas prescrições anteriores à exigência
+    OverrideReason     string         `json:"override_reason,omitempty"` // Justificativa para prescrever apesar de alergia ou condição registrada
+    OverriddenBy       string         `json:"overridden_by,omitempty"`
     CreatedAt          time.Time      `json:"created_at" gorm:"autoCreateTime"`
     UpdatedAt          time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
     DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
@@ -99,11 +108,40 @@ func (cd CustomDate) String() string {
     return cd.Time.Format(customDateLayout)
 }
 
+// MarshalJSON grava a data no formato 2006-01-02, o mesmo aceito nas requisições
+func (cd CustomDate) MarshalJSON() ([]byte, error) {
+    return []byte(`"` + cd.Time.Format(customDateLayout) + `"`), nil
+}
+
+// UnmarshalJSON aceita 2006-01-02 e, para clientes antigos, datas RFC 3339
+func (cd *CustomDate) UnmarshalJSON(data []byte) error {
+    value := strings.Trim(string(data), `"`)
+    if value == "null" || value == "" {
+        return nil
+    }
+    t, err := time.Parse(customDateLayout, value)
+    if err != nil {
+        if t, err = time.Parse(time.RFC3339, value); err != nil {
+            return fmt.Errorf("invalid date %q: use 2006-01-02", value)
+        }
+    }
+    cd.Time = t
+    return nil
+}
+
+// Situações de uma consulta. no_show é a consulta agendada em que o animal não compareceu.
+const (
+	ConsultationScheduled = "scheduled"
+	ConsultationCompleted = "completed"
+	ConsultationCanceled  = "canceled"
+	ConsultationNoShow    = "no_show"
+)
+
 type Consultation struct {
 	ID                       uuid.UUID      `gorm:"type:uuid;primary_key" json:"consultation_id"`
 	AnimalID                 uuid.UUID      `gorm:"type:uuid;not null" json:"animal_id" validate:"required,uuid"`
 	CRVM                     string         `gorm:"column:crvm;not null" json:"crvm" validate:"required,min=1"`
-	ConsultationDate         CustomDate     `json:"consultation_date" validate:"required"` // Usa CustomDate
+	ConsultationDate         CustomDate     `json:"consultation_date" validate:"required"`                      // Usa CustomDate
 	ConsultationHour         string         `json:"consultation_hour" validate:"required,len=5,datetime=15:04"` // Ajuste o formato se necessário
 	Observation              string         `json:"observation" validate:"max=255"`
 	Reason                   string         `json:"reason" validate:"required,min=10,max=255"`
@@ -111,7 +149,9 @@ type Consultation struct {
 	ConsultationDescription  string         `json:"consultation_description" validate:"required"`
 	ConsultationPrescription string         `json:"consultation_prescription"`
 	ConsultationPrice        float64        `json:"consultation_price" validate:"required,gte=0"`
-	ConsultationStatus       string         `json:"consultation_status" validate:"required,oneof=scheduled completed canceled"`
+	ConsultationStatus       string         `json:"consultation_status" validate:"required,oneof=scheduled completed canceled no_show"`
+	AuthorizedBy             string         `gorm:"type:varchar(14)" json:"authorized_by,omitempty"` // CPF ou CNPJ do tutor principal que autorizou o procedimento
+	AnimalAge                *AnimalAge     `json:"animal_age,omitempty" gorm:"-"` // Idade do animal na data da consulta
 	CreatedAt                time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt                time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt                gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
@@ -127,6 +167,18 @@ type ConsultationHistory struct {
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
 }
 
+// AnimalHistory registra as alterações feitas em um animal, com autor e momento
+type AnimalHistory struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key;" json:"animal_history_id"`
+	AnimalID  uuid.UUID      `gorm:"type:uuid;not null;index" json:"animal_id"`
+	ChangedBy string         `json:"changed_by"`
+	Changes   []Change       `gorm:"type:jsonb;serializer:json" json:"changes"`
+	Timestamp time.Time      `json:"timestamp"`
+	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
+}
+
 type Change struct {
 	Field    string `json:"field"`
 	OldValue string `json:"old_value"`
@@ -163,6 +215,7 @@ type Medication struct {
 	StorageConditions    string         `json:"storage_conditions"`                                       // Condições de armazenamento do medicamento, opcional.
 	PrescriptionRequired bool           `json:"prescription_required"`                                    // Indica se o medicamento requer prescrição médica, booleano.
 	Expiration           time.Time      `json:"expiration" validate:"required"`                           // Data de validade do medicamento, obrigatório.
+	VaccineProductID     *uuid.UUID     `json:"vaccine_product_id" gorm:"type:uuid;index"`                // Produto vacinal do qual este medicamento é um lote, opcional.
 	CreatedAt            time.Time      `json:"created_at" gorm:"autoCreateTime"`                         // Data de criação do registro, automaticamente preenchido pelo GORM.
 	UpdatedAt            time.Time      `json:"updated_at" gorm:"autoUpdateTime"`                         // Data de atualização do registro, automaticamente preenchido pelo GORM.
 	DeletedAt            gorm.DeletedAt `gorm:"index" json:"-"`                                           // Campo usado para soft delete, permitindo que o registro seja marcado como deletado sem ser removido fisicamente do banco.
@@ -1,7 +1,6 @@
 package repository
 
 import (
-	"vetblock/internal/db"
 	"vetblock/internal/db/model"
 
 	"github.com/google/uuid"
@@ -13,14 +12,14 @@ type ImageRepositoryInterface interface {
 	FindImageByID(id uuid.UUID) (*model.ImageModel, error)
 	DeleteImage(id uuid.UUID) (string, error)
 	UpdateImage(id uuid.UUID, updatedImage model.ImageModel) error
+	FindImageMetadata(id uuid.UUID) (*model.ImageModel, error)
 }
 
 type ImageRepository struct {
 	Db *gorm.DB
 }
 
-func NewImageRepository() *ImageRepository {
-	database := db.NewDb()
+func NewImageRepository(database *gorm.DB) *ImageRepository {
 	return &ImageRepository{Db: database}
 }
 
@@ -40,6 +39,15 @@ func (r *ImageRepository) FindImageByID(id uuid.UUID) (*model.ImageModel, error)
 	return &image, nil
 }
 
+// FindImageMetadata busca a imagem sem carregar o conteúdo, apenas ID e datas
+func (r *ImageRepository) FindImageMetadata(id uuid.UUID) (*model.ImageModel, error) {
+	var image model.ImageModel
+	if err := r.Db.Select("id", "created_at", "updated_at").Where("id = ?", id).First(&image).Error; err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
 func (r *ImageRepository) DeleteImage(id uuid.UUID) (string, error) {
 	var image model.ImageModel
 	if err := r.Db.Where("id = ?", id).First(&image).Error; err != nil {
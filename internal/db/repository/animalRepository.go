@@ -3,7 +3,7 @@ package repository
 import (
 	"errors"
 	"log"
-	"vetblock/internal/db"
+	"time"
 	"vetblock/internal/db/model"
 
 	"github.com/google/uuid"
@@ -14,8 +14,7 @@ type AnimalRepository struct {
 	Db *gorm.DB
 }
 
-func NewAnimalRepository() *AnimalRepository {
-	database := db.NewDb()
+func NewAnimalRepository(database *gorm.DB) *AnimalRepository {
 	return &AnimalRepository{Db: database}
 }
 
@@ -41,6 +40,17 @@ func (r *AnimalRepository) FindAnimalByID(id uuid.UUID) (*model.Animal, error) {
 	return &animal, nil
 }
 
+func (r *AnimalRepository) FindAnimalByMicrochip(number string) (*model.Animal, error) {
+	var animal model.Animal
+	if err := r.Db.Where("microchip = ? AND deleted_at IS NULL", number).First(&animal).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Print("Error finding animal by microchip:", err)
+		}
+		return nil, err
+	}
+	return &animal, nil
+}
+
 func (r *AnimalRepository) DeleteAnimal(id uuid.UUID) (string, error) {
 	var animal model.Animal
 	if err := r.Db.Where("id = ?", id).First(&animal).Error; err != nil {
@@ -63,8 +73,19 @@ func (r *AnimalRepository) DeleteAnimal(id uuid.UUID) (string, error) {
 
 func (r *AnimalRepository) FindByUniqueAttributes(animal model.Animal) (*model.Animal, error) {
 	var existingAnimal model.Animal
-	if err := r.Db.Where("name = ? AND species = ? AND breed = ? AND age = ? AND description = ? AND cpf_tutor = ? AND deleted_at IS NULL",
-		animal.Name, animal.Species, animal.Breed, animal.Age, animal.Description, animal.CPFTutor).First(&existingAnimal).Error; err != nil {
+	query := r.Db.Where("name = ? AND species = ? AND breed = ? AND description = ? AND cpf_tutor = ? AND deleted_at IS NULL",
+		animal.Name, animal.Species, animal.Breed, animal.Description, animal.CPFTutor)
+	// Datas estimadas a partir da idade só valem pelo ano, e as convertidas da
+	// antiga coluna age dependem da data de cadastro
+	switch {
+	case animal.BirthDate != nil && animal.BirthDatePrecision == model.BirthDatePrecisionEstimated:
+		query = query.Where("birth_date_precision = ? AND EXTRACT(YEAR FROM birth_date) = ?", model.BirthDatePrecisionEstimated, animal.BirthDate.Year())
+	case animal.BirthDate != nil:
+		query = query.Where("birth_date = ?", animal.BirthDate)
+	default:
+		query = query.Where("birth_date IS NULL")
+	}
+	if err := query.First(&existingAnimal).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -82,3 +103,91 @@ func (r *AnimalRepository) FindAllAnimals() ([]model.Animal, error) {
 	}
 	return animals, nil
 }
+
+// UpdateAnimal salva o animal e o histórico das alterações. Um novo peso também
+// entra na curva de peso, como uma pesagem de hoje.
+func (r *AnimalRepository) UpdateAnimal(animal *model.Animal, history *model.AnimalHistory) error {
+	return r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(animal).Error; err != nil {
+			log.Print("Error updating animal:", err)
+			return err
+		}
+		if history != nil && len(history.Changes) > 0 {
+			if err := tx.Create(history).Error; err != nil {
+				log.Print("Error saving animal history:", err)
+				return err
+			}
+		}
+		if history != nil && animal.Weight > 0 && hasChange(history, "weight") {
+			now := time.Now()
+			record := &model.WeightRecord{
+				ID:         uuid.New(),
+				AnimalID:   animal.ID,
+				Date:       model.CustomDate{Time: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)},
+				Weight:     animal.Weight,
+				Unit:       model.WeightUnitKg,
+				RecordedBy: history.ChangedBy,
+			}
+			if err := saveWeight(tx, record, true); err != nil {
+				return err
+			}
+		}
+		log.Print("Repository Updating Animal")
+		return nil
+	})
+}
+
+func hasChange(history *model.AnimalHistory, field string) bool {
+	for _, change := range history.Changes {
+		if change.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *AnimalRepository) FindAnimalHistory(animalID uuid.UUID) ([]model.AnimalHistory, error) {
+	var history []model.AnimalHistory
+	if err := r.Db.Where("animal_id = ?", animalID).Order("timestamp desc").Find(&history).Error; err != nil {
+		log.Print("Error finding animal history:", err)
+		return nil, err
+	}
+	return history, nil
+}
+
+// TransferOwnership troca o tutor do animal e grava a transferência e o histórico na mesma transação
+func (r *AnimalRepository) TransferOwnership(animal *model.Animal, transfer *model.OwnershipTransfer, history *model.AnimalHistory) error {
+	return r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Animal{}).Where("id = ?", animal.ID).Update("cpf_tutor", transfer.NewCPF).Error; err != nil {
+			log.Print("Error updating animal tutor:", err)
+			return err
+		}
+		if err := tx.Create(transfer).Error; err != nil {
+			log.Print("Error saving ownership transfer:", err)
+			return err
+		}
+		// O novo tutor principal deixa de ter um papel secundário no animal
+		if err := tx.Where("animal_id = ? AND cpf_tutor = ?", animal.ID, transfer.NewCPF).Delete(&model.AnimalTutor{}).Error; err != nil {
+			log.Print("Error removing animal tutor:", err)
+			return err
+		}
+		if history != nil {
+			if err := tx.Create(history).Error; err != nil {
+				log.Print("Error saving animal history:", err)
+				return err
+			}
+		}
+		animal.CPFTutor = transfer.NewCPF
+		log.Print("Repository Transferring Animal Ownership")
+		return nil
+	})
+}
+
+func (r *AnimalRepository) FindOwnershipTransfers(animalID uuid.UUID) ([]model.OwnershipTransfer, error) {
+	var transfers []model.OwnershipTransfer
+	if err := r.Db.Where("animal_id = ?", animalID).Order("effective_date asc, created_at asc").Find(&transfers).Error; err != nil {
+		log.Print("Error finding ownership transfers:", err)
+		return nil, err
+	}
+	return transfers, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"log"
+	"time"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecordDeath grava o óbito, marca o animal como falecido e cancela as consultas
+// agendadas a partir de "cancelFrom", tudo na mesma transação. Retorna quantas
+// consultas foram canceladas.
+func (r *AnimalRepository) RecordDeath(animal *model.Animal, record *model.DeathRecord, history *model.AnimalHistory, cancelFrom time.Time) (int64, error) {
+	var cancelled int64
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(record).Error; err != nil {
+			log.Print("Error saving death record:", err)
+			return err
+		}
+		if err := tx.Model(&model.Animal{}).Where("id = ?", animal.ID).Update("deceased_at", record.DateOfDeath).Error; err != nil {
+			log.Print("Error marking animal as deceased:", err)
+			return err
+		}
+
+		result := tx.Model(&model.Consultation{}).
+			Where("animal_id = ? AND consultation_status = ? AND consultation_date >= ?", animal.ID, "scheduled", cancelFrom.Format("2006-01-02")).
+			Update("consultation_status", "canceled")
+		if result.Error != nil {
+			log.Print("Error canceling scheduled consultations:", result.Error)
+			return result.Error
+		}
+		cancelled = result.RowsAffected
+
+		if history != nil {
+			if err := tx.Create(history).Error; err != nil {
+				log.Print("Error saving animal history:", err)
+				return err
+			}
+		}
+		log.Print("Repository Recording Animal Death")
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	animal.DeceasedAt = &record.DateOfDeath
+	return cancelled, nil
+}
+
+func (r *AnimalRepository) FindDeathRecord(animalID uuid.UUID) (*model.DeathRecord, error) {
+	var record model.DeathRecord
+	if err := r.Db.Where("animal_id = ?", animalID).First(&record).Error; err != nil {
+		log.Print("Error finding death record:", err)
+		return nil, err
+	}
+	return &record, nil
+}
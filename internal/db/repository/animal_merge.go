@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"log"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FindAnimalsByTutorAndSpecies lista os animais ativos do tutor com a espécie informada
+func (r *AnimalRepository) FindAnimalsByTutorAndSpecies(cpfTutor, species string) ([]model.Animal, error) {
+	var animals []model.Animal
+	if err := r.Db.Where("cpf_tutor = ? AND LOWER(species) = LOWER(?)", cpfTutor, species).Order("created_at asc").Find(&animals).Error; err != nil {
+		log.Print("Error finding animals by tutor and species:", err)
+		return nil, err
+	}
+	return animals, nil
+}
+
+// MergeAnimals move consultas, dosagens, internações, pesagens, alergias, condições crônicas, vacinas e a imagem do animal de origem
+// para o de destino, exclui a origem e grava o destino, o histórico e o registro da fusão,
+// tudo na mesma transação. As contagens de registros movidos são preenchidas em merge.
+func (r *AnimalRepository) MergeAnimals(source, target *model.Animal, merge *model.AnimalMerge, history *model.AnimalHistory) error {
+	return r.Db.Transaction(func(tx *gorm.DB) error {
+		moves := []struct {
+			table  any
+			column string
+			count  *int64
+		}{
+			{&model.Consultation{}, "animal_id", &merge.MovedConsultations},
+			{&model.Dosage{}, "animal_id", &merge.MovedDosages},
+			{&model.Hospitalization{}, "patient_id", &merge.MovedHospitalizations},
+			{&model.WeightRecord{}, "animal_id", &merge.MovedWeightRecords},
+			{&model.Allergy{}, "animal_id", &merge.MovedClinicalRecords},
+			{&model.ChronicCondition{}, "animal_id", &merge.MovedClinicalRecords},
+			{&model.VaccinationRecord{}, "animal_id", &merge.MovedVaccinations},
+		}
+		for _, move := range moves {
+			result := tx.Model(move.table).Where(move.column+" = ?", source.ID).Update(move.column, target.ID)
+			if result.Error != nil {
+				log.Printf("Error moving %T: %v", move.table, result.Error)
+				return result.Error
+			}
+			*move.count += result.RowsAffected
+		}
+
+		// Os tutores adicionais da origem passam para o destino, exceto os que ele já tem
+		if err := tx.Model(&model.AnimalTutor{}).
+			Where("animal_id = ? AND cpf_tutor <> ?", source.ID, target.CPFTutor).
+			Where("cpf_tutor NOT IN (?)", tx.Model(&model.AnimalTutor{}).Select("cpf_tutor").Where("animal_id = ?", target.ID)).
+			Update("animal_id", target.ID).Error; err != nil {
+			log.Print("Error moving animal tutors:", err)
+			return err
+		}
+
+		// A imagem do animal usa o mesmo ID do animal; só é movida se o destino ainda não tiver uma
+		moved, err := moveAnimalImage(tx, source.ID, target.ID)
+		if err != nil {
+			log.Print("Error moving animal image:", err)
+			return err
+		}
+		merge.MovedImage = moved
+
+		if err := tx.Delete(source).Error; err != nil {
+			log.Print("Error deleting merged animal:", err)
+			return err
+		}
+		if err := tx.Save(target).Error; err != nil {
+			log.Print("Error updating merge target:", err)
+			return err
+		}
+		if history != nil && len(history.Changes) > 0 {
+			if err := tx.Create(history).Error; err != nil {
+				log.Print("Error saving animal history:", err)
+				return err
+			}
+		}
+		if err := tx.Create(merge).Error; err != nil {
+			log.Print("Error saving animal merge:", err)
+			return err
+		}
+		log.Print("Repository Merging Animals")
+		return nil
+	})
+}
+
+func moveAnimalImage(tx *gorm.DB, sourceID, targetID uuid.UUID) (bool, error) {
+	var targetImages int64
+	if err := tx.Model(&model.ImageModel{}).Where("id = ?", targetID).Count(&targetImages).Error; err != nil {
+		return false, err
+	}
+	if targetImages > 0 {
+		return false, nil
+	}
+	result := tx.Model(&model.ImageModel{}).Where("id = ?", sourceID).Update("id", targetID)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *AnimalRepository) FindAnimalMerges(animalID uuid.UUID) ([]model.AnimalMerge, error) {
+	var merges []model.AnimalMerge
+	if err := r.Db.Where("source_animal_id = ? OR target_animal_id = ?", animalID, animalID).Order("timestamp desc").Find(&merges).Error; err != nil {
+		log.Print("Error finding animal merges:", err)
+		return nil, err
+	}
+	return merges, nil
+}
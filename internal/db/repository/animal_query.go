@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidSortField = errors.New("invalid sort field")
+)
+
+// AnimalQuery descreve os filtros, a ordenação e a paginação da listagem de animais
+type AnimalQuery struct {
+	Species     string
+	Breed       string
+	CPFTutor    string // Inclui os animais em que o tutor tem qualquer papel
+	TutorRole   string // Restringe CPFTutor a um papel (primary_owner, co_owner...)
+	NamePrefix  string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	SortBy      string // name, species, breed ou created_at (padrão)
+	SortDesc    bool
+	Cursor      string
+	Limit       int
+}
+
+// AnimalPage é uma página da listagem; NextCursor fica vazio na última página
+type AnimalPage struct {
+	Animals    []model.Animal `json:"animals"`
+	NextCursor string         `json:"next_cursor"`
+}
+
+// Colunas pelas quais a listagem pode ser ordenada
+var animalSortColumns = map[string]string{
+	"name":       "name",
+	"species":    "species",
+	"breed":      "breed",
+	"created_at": "created_at",
+}
+
+const defaultAnimalSort = "created_at"
+
+// animalCursor guarda a posição do último animal retornado (valor da coluna de ordenação + ID)
+type animalCursor struct {
+	Sort  string    `json:"s"`
+	Desc  bool      `json:"d"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func (r *AnimalRepository) QueryAnimals(query AnimalQuery) (*AnimalPage, error) {
+	sortBy := query.SortBy
+	if sortBy == "" {
+		sortBy = defaultAnimalSort
+	}
+	column, ok := animalSortColumns[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSortField, query.SortBy)
+	}
+
+	tx := r.Db.Model(&model.Animal{})
+	if query.Species != "" {
+		tx = tx.Where("LOWER(species) = LOWER(?)", query.Species)
+	}
+	if query.Breed != "" {
+		tx = tx.Where("LOWER(breed) = LOWER(?)", query.Breed)
+	}
+	if query.CPFTutor != "" {
+		switch query.TutorRole {
+		case "":
+			tx = tx.Where("(cpf_tutor = ? OR id IN (?))", query.CPFTutor, animalsOfTutor(r.Db, query.CPFTutor, ""))
+		case model.TutorRolePrimaryOwner:
+			tx = tx.Where("cpf_tutor = ?", query.CPFTutor)
+		default:
+			tx = tx.Where("id IN (?)", animalsOfTutor(r.Db, query.CPFTutor, query.TutorRole))
+		}
+	}
+	if query.NamePrefix != "" {
+		tx = tx.Where("name ILIKE ?", escapeLike(query.NamePrefix)+"%")
+	}
+	if query.CreatedFrom != nil {
+		tx = tx.Where("created_at >= ?", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		tx = tx.Where("created_at < ?", *query.CreatedTo)
+	}
+
+	direction, comparison := "ASC", ">"
+	if query.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeAnimalCursor(query.Cursor)
+		if err != nil || cursor.Sort != sortBy || cursor.Desc != query.SortDesc {
+			return nil, ErrInvalidCursor
+		}
+		tx = tx.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, comparison), cursor.Value, cursor.ID)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 1
+	}
+
+	var animals []model.Animal
+	if err := tx.Order(fmt.Sprintf("%s %s, id %s", column, direction, direction)).Limit(limit + 1).Find(&animals).Error; err != nil {
+		log.Print("Error querying animals:", err)
+		return nil, err
+	}
+
+	page := &AnimalPage{Animals: animals}
+	if len(animals) > limit {
+		page.Animals = animals[:limit]
+		last := page.Animals[limit-1]
+		page.NextCursor = encodeAnimalCursor(animalCursor{
+			Sort:  sortBy,
+			Desc:  query.SortDesc,
+			Value: animalSortValue(last, sortBy),
+			ID:    last.ID,
+		})
+	}
+	return page, nil
+}
+
+func animalSortValue(animal model.Animal, sortBy string) string {
+	switch sortBy {
+	case "name":
+		return animal.Name
+	case "species":
+		return animal.Species
+	case "breed":
+		return animal.Breed
+	default:
+		return animal.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+func encodeAnimalCursor(cursor animalCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeAnimalCursor(encoded string) (*animalCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var cursor animalCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// escapeLike escapa os curingas do LIKE para que o prefixo seja comparado literalmente
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
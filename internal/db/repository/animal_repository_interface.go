@@ -1,6 +1,8 @@
 package repository
 
 import (
+    "time"
+
     "github.com/google/uuid"
     "vetblock/internal/db/model"
 )
@@ -10,6 +12,25 @@ type AnimalRepositoryInterface interface {
     FindByUniqueAttributes(animal model.Animal) (*model.Animal, error)
     SaveAnimal(animal *model.Animal) error
     FindAnimalByID(id uuid.UUID) (*model.Animal, error)
+    FindAnimalByMicrochip(number string) (*model.Animal, error)
     DeleteAnimal(id uuid.UUID) (string, error)
     FindAllAnimals() ([]model.Animal, error)
+    QueryAnimals(query AnimalQuery) (*AnimalPage, error)
+    TransferOwnership(animal *model.Animal, transfer *model.OwnershipTransfer, history *model.AnimalHistory) error
+    FindOwnershipTransfers(animalID uuid.UUID) ([]model.OwnershipTransfer, error)
+    UpdateAnimal(animal *model.Animal, history *model.AnimalHistory) error
+    FindAnimalHistory(animalID uuid.UUID) ([]model.AnimalHistory, error)
+    FindDeletedAnimals() ([]model.Animal, error)
+    FindDeletedAnimalByID(id uuid.UUID) (*model.Animal, error)
+    RestoreAnimal(animal *model.Animal, entry *model.AuditLog) error
+    PurgeDeletedAnimals(before time.Time, performedBy string) ([]uuid.UUID, error)
+    FindAnimalsByTutorAndSpecies(cpfTutor, species string) ([]model.Animal, error)
+    MergeAnimals(source, target *model.Animal, merge *model.AnimalMerge, history *model.AnimalHistory) error
+    FindAnimalMerges(animalID uuid.UUID) ([]model.AnimalMerge, error)
+    RecordDeath(animal *model.Animal, record *model.DeathRecord, history *model.AnimalHistory, cancelFrom time.Time) (int64, error)
+    FindDeathRecord(animalID uuid.UUID) (*model.DeathRecord, error)
+    FindAnimalTutors(animalID uuid.UUID) ([]model.AnimalTutor, error)
+    SaveAnimalTutor(link *model.AnimalTutor, history *model.AnimalHistory) error
+    DeleteAnimalTutor(link *model.AnimalTutor, history *model.AnimalHistory) error
+    FindAnimalsOwnedBy(cpf string) ([]model.Animal, error)
 }
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"log"
+	"time"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FindDeletedAnimals lista os animais na lixeira, do excluído mais recentemente ao mais antigo.
+// Os duplicados fundidos em outro animal não aparecem: seus registros já estão no destino.
+func (r *AnimalRepository) FindDeletedAnimals() ([]model.Animal, error) {
+	var animals []model.Animal
+	if err := r.Db.Unscoped().Where("deleted_at IS NOT NULL").
+		Where("id NOT IN (?)", r.Db.Model(&model.AnimalMerge{}).Select("source_animal_id")).
+		Order("deleted_at desc").Find(&animals).Error; err != nil {
+		log.Print("Error finding deleted animals:", err)
+		return nil, err
+	}
+	return animals, nil
+}
+
+func (r *AnimalRepository) FindDeletedAnimalByID(id uuid.UUID) (*model.Animal, error) {
+	var animal model.Animal
+	if err := r.Db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&animal).Error; err != nil {
+		log.Print("Error finding deleted animal:", err)
+		return nil, err
+	}
+	return &animal, nil
+}
+
+// RestoreAnimal tira o animal da lixeira e grava a entrada de auditoria na mesma transação
+func (r *AnimalRepository) RestoreAnimal(animal *model.Animal, entry *model.AuditLog) error {
+	return r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&model.Animal{}).Where("id = ?", animal.ID).Update("deleted_at", nil).Error; err != nil {
+			log.Print("Error restoring animal:", err)
+			return err
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			log.Print("Error saving audit log:", err)
+			return err
+		}
+		animal.DeletedAt = gorm.DeletedAt{}
+		log.Print("Repository Restoring Animal")
+		return nil
+	})
+}
+
+// PurgeDeletedAnimals remove definitivamente os animais excluídos antes de "before",
+// junto com os registros que dependem deles, e grava uma entrada de auditoria por animal.
+// As imagens dos animais usam o mesmo ID do animal (ver rota /animals/:id/image).
+func (r *AnimalRepository) PurgeDeletedAnimals(before time.Time, performedBy string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&model.Animal{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Pluck("id", &ids).Error; err != nil {
+			log.Print("Error finding animals to purge:", err)
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		consultations := tx.Unscoped().Model(&model.Consultation{}).Select("id").Where("animal_id IN ?", ids)
+		hospitalizations := tx.Unscoped().Model(&model.Hospitalization{}).Select("id").Where("patient_id IN ?", ids)
+		dependents := []struct {
+			table any
+			query string
+			args  []any
+		}{
+			{&model.ConsultationHistory{}, "consultation_id IN (?)", []any{consultations}},
+			// Inclui as dosagens ligadas às consultas e internações expurgadas, mesmo que
+			// registradas em outro animal, para não deixar prescrições órfãs
+			{&model.Dosage{}, "animal_id IN ? OR consultation_id IN (?) OR hospitalization_id IN (?)", []any{ids, consultations, hospitalizations}},
+			{&model.Consultation{}, "animal_id IN ?", []any{ids}},
+			{&model.Hospitalization{}, "patient_id IN ?", []any{ids}},
+			{&model.WeightRecord{}, "animal_id IN ?", []any{ids}},
+			{&model.OwnershipTransfer{}, "animal_id IN ?", []any{ids}},
+			{&model.AnimalHistory{}, "animal_id IN ?", []any{ids}},
+			{&model.Allergy{}, "animal_id IN ?", []any{ids}},
+			{&model.ChronicCondition{}, "animal_id IN ?", []any{ids}},
+			{&model.VaccinationRecord{}, "animal_id IN ?", []any{ids}},
+			{&model.DeathRecord{}, "animal_id IN ?", []any{ids}},
+			{&model.ImageModel{}, "id IN ?", []any{ids}},
+		}
+		for _, dependent := range dependents {
+			if err := tx.Unscoped().Where(dependent.query, dependent.args...).Delete(dependent.table).Error; err != nil {
+				log.Printf("Error purging %T: %v", dependent.table, err)
+				return err
+			}
+		}
+
+		if err := tx.Unscoped().Where("id IN ?", ids).Delete(&model.Animal{}).Error; err != nil {
+			log.Print("Error purging animals:", err)
+			return err
+		}
+
+		entries := make([]model.AuditLog, 0, len(ids))
+		for _, id := range ids {
+			entries = append(entries, *model.NewAuditLog(model.AuditActionPurge, "animal", id, performedBy,
+				"deleted before "+before.UTC().Format(time.RFC3339)))
+		}
+		if err := tx.Create(&entries).Error; err != nil {
+			log.Print("Error saving audit log:", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Repository Purged %d Animals", len(ids))
+	return ids, nil
+}
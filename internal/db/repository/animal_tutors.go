@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"log"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FindAnimalTutors lista os tutores adicionais do animal, do vínculo mais antigo ao mais recente
+func (r *AnimalRepository) FindAnimalTutors(animalID uuid.UUID) ([]model.AnimalTutor, error) {
+	var links []model.AnimalTutor
+	if err := r.Db.Where("animal_id = ?", animalID).Order("created_at asc").Find(&links).Error; err != nil {
+		log.Print("Error finding animal tutors:", err)
+		return nil, err
+	}
+	return links, nil
+}
+
+// SaveAnimalTutor cria ou altera o vínculo e registra a alteração no histórico do animal
+func (r *AnimalRepository) SaveAnimalTutor(link *model.AnimalTutor, history *model.AnimalHistory) error {
+	return r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(link).Error; err != nil {
+			log.Print("Error saving animal tutor:", err)
+			return err
+		}
+		if history != nil {
+			if err := tx.Create(history).Error; err != nil {
+				log.Print("Error saving animal history:", err)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteAnimalTutor remove o vínculo e registra a alteração no histórico do animal
+func (r *AnimalRepository) DeleteAnimalTutor(link *model.AnimalTutor, history *model.AnimalHistory) error {
+	return r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(link).Error; err != nil {
+			log.Print("Error deleting animal tutor:", err)
+			return err
+		}
+		if history != nil {
+			if err := tx.Create(history).Error; err != nil {
+				log.Print("Error saving animal history:", err)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindAnimalsOwnedBy lista os animais de que o tutor é tutor principal ou co-tutor
+func (r *AnimalRepository) FindAnimalsOwnedBy(cpf string) ([]model.Animal, error) {
+	var animals []model.Animal
+	if err := r.Db.Where("(cpf_tutor = ? OR id IN (?))", cpf, animalsOfTutor(r.Db, cpf, model.TutorRoleCoOwner)).
+		Order("name asc, id asc").Find(&animals).Error; err != nil {
+		log.Print("Error finding animals owned by tutor:", err)
+		return nil, err
+	}
+	return animals, nil
+}
+
+// animalsOfTutor seleciona os IDs dos animais em que o tutor tem um papel além do
+// de tutor principal; role vazio inclui todos os papéis
+func animalsOfTutor(db *gorm.DB, cpf, role string) *gorm.DB {
+	tx := db.Model(&model.AnimalTutor{}).Select("animal_id").Where("cpf_tutor = ?", cpf)
+	if role != "" {
+		tx = tx.Where("role = ?", role)
+	}
+	return tx
+}
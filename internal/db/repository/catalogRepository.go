@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CatalogRepository interface {
+	ListSpecies(ctx context.Context) ([]model.Species, error)
+	FindSpeciesByID(ctx context.Context, id uuid.UUID) (*model.Species, error)
+	SaveSpecies(ctx context.Context, species *model.Species) error
+	DeleteSpecies(ctx context.Context, id uuid.UUID) error
+	FindBreedByID(ctx context.Context, id uuid.UUID) (*model.Breed, error)
+	SaveBreed(ctx context.Context, breed *model.Breed) error
+	DeleteBreed(ctx context.Context, id uuid.UUID) error
+}
+
+type catalogRepository struct {
+	db *gorm.DB
+}
+
+func NewCatalogRepository(db *gorm.DB) CatalogRepository {
+	return &catalogRepository{db: db}
+}
+
+// ListSpecies lista as espécies com suas raças, em ordem alfabética do nome em português
+func (r *catalogRepository) ListSpecies(ctx context.Context) ([]model.Species, error) {
+	var species []model.Species
+	err := r.db.WithContext(ctx).
+		Preload("Breeds", func(tx *gorm.DB) *gorm.DB { return tx.Order("name_pt asc") }).
+		Order("name_pt asc").
+		Find(&species).Error
+	if err != nil {
+		log.Print("Error listing species:", err)
+		return nil, err
+	}
+	return species, nil
+}
+
+func (r *catalogRepository) FindSpeciesByID(ctx context.Context, id uuid.UUID) (*model.Species, error) {
+	var species model.Species
+	if err := r.db.WithContext(ctx).Preload("Breeds").Where("id = ?", id).First(&species).Error; err != nil {
+		log.Print("Error finding species:", err)
+		return nil, err
+	}
+	return &species, nil
+}
+
+// SaveSpecies cria ou atualiza a espécie, sem mexer nas raças. Quando o nome em
+// inglês muda, os animais da espécie, inclusive os da lixeira, passam a usar o novo
+// nome na mesma transação.
+func (r *catalogRepository) SaveSpecies(ctx context.Context, species *model.Species) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous model.Species
+		err := tx.Where("id = ?", species.ID).First(&previous).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Print("Error finding species:", err)
+			return err
+		}
+		if err := tx.Omit("Breeds").Save(species).Error; err != nil {
+			log.Print("Error saving species:", err)
+			return err
+		}
+		if previous.NameEN == "" || previous.NameEN == species.NameEN {
+			return nil
+		}
+		if err := tx.Unscoped().Model(&model.Animal{}).Where("species = ?", previous.NameEN).Update("species", species.NameEN).Error; err != nil {
+			log.Print("Error renaming animal species:", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// DeleteSpecies exclui a espécie e suas raças na mesma transação
+func (r *catalogRepository) DeleteSpecies(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("species_id = ?", id).Delete(&model.Breed{}).Error; err != nil {
+			log.Print("Error deleting breeds:", err)
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&model.Species{})
+		if result.Error != nil {
+			log.Print("Error deleting species:", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *catalogRepository) FindBreedByID(ctx context.Context, id uuid.UUID) (*model.Breed, error) {
+	var breed model.Breed
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&breed).Error; err != nil {
+		log.Print("Error finding breed:", err)
+		return nil, err
+	}
+	return &breed, nil
+}
+
+// SaveBreed cria ou atualiza a raça. Quando o nome em inglês muda, os animais da
+// espécie com essa raça, inclusive os da lixeira, passam a usar o novo nome na
+// mesma transação.
+func (r *catalogRepository) SaveBreed(ctx context.Context, breed *model.Breed) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous model.Breed
+		err := tx.Where("id = ?", breed.ID).First(&previous).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Print("Error finding breed:", err)
+			return err
+		}
+		if err := tx.Save(breed).Error; err != nil {
+			log.Print("Error saving breed:", err)
+			return err
+		}
+		if previous.NameEN == "" || previous.NameEN == breed.NameEN {
+			return nil
+		}
+		var species model.Species
+		if err := tx.Where("id = ?", breed.SpeciesID).First(&species).Error; err != nil {
+			log.Print("Error finding species:", err)
+			return err
+		}
+		if err := tx.Unscoped().Model(&model.Animal{}).Where("species = ? AND breed = ?", species.NameEN, previous.NameEN).Update("breed", breed.NameEN).Error; err != nil {
+			log.Print("Error renaming animal breed:", err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *catalogRepository) DeleteBreed(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Breed{})
+	if result.Error != nil {
+		log.Print("Error deleting breed:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ClinicalRecordRepository interface {
+	CreateAllergy(ctx context.Context, allergy *model.Allergy) error
+	FindAllergiesByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.Allergy, error)
+	DeleteAllergy(ctx context.Context, animalID, allergyID uuid.UUID) error
+	CreateCondition(ctx context.Context, condition *model.ChronicCondition) error
+	FindConditionsByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.ChronicCondition, error)
+	DeleteCondition(ctx context.Context, animalID, conditionID uuid.UUID) error
+}
+
+type clinicalRecordRepository struct {
+	db *gorm.DB
+}
+
+func NewClinicalRecordRepository(db *gorm.DB) ClinicalRecordRepository {
+	return &clinicalRecordRepository{db: db}
+}
+
+func (r *clinicalRecordRepository) CreateAllergy(ctx context.Context, allergy *model.Allergy) error {
+	if err := r.db.WithContext(ctx).Create(allergy).Error; err != nil {
+		log.Print("Error saving allergy:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *clinicalRecordRepository) FindAllergiesByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.Allergy, error) {
+	var allergies []model.Allergy
+	if err := r.db.WithContext(ctx).Where("animal_id = ?", animalID).Order("created_at asc").Find(&allergies).Error; err != nil {
+		log.Print("Error finding allergies:", err)
+		return nil, err
+	}
+	return allergies, nil
+}
+
+func (r *clinicalRecordRepository) DeleteAllergy(ctx context.Context, animalID, allergyID uuid.UUID) error {
+	return deleteAnimalRecord(r.db.WithContext(ctx), &model.Allergy{}, animalID, allergyID)
+}
+
+func (r *clinicalRecordRepository) CreateCondition(ctx context.Context, condition *model.ChronicCondition) error {
+	if err := r.db.WithContext(ctx).Create(condition).Error; err != nil {
+		log.Print("Error saving chronic condition:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *clinicalRecordRepository) FindConditionsByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.ChronicCondition, error) {
+	var conditions []model.ChronicCondition
+	if err := r.db.WithContext(ctx).Where("animal_id = ?", animalID).Order("created_at asc").Find(&conditions).Error; err != nil {
+		log.Print("Error finding chronic conditions:", err)
+		return nil, err
+	}
+	return conditions, nil
+}
+
+func (r *clinicalRecordRepository) DeleteCondition(ctx context.Context, animalID, conditionID uuid.UUID) error {
+	return deleteAnimalRecord(r.db.WithContext(ctx), &model.ChronicCondition{}, animalID, conditionID)
+}
+
+// deleteAnimalRecord exclui o registro somente se ele pertencer ao animal informado
+func deleteAnimalRecord(db *gorm.DB, record interface{}, animalID, id uuid.UUID) error {
+	result := db.Where("id = ? AND animal_id = ?", id, animalID).Delete(record)
+	if result.Error != nil {
+		log.Printf("Error deleting %T: %v", record, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"vetblock/internal/db/model"
+
+	"gorm.io/gorm"
+)
+
+type ConsentRepository interface {
+	FindConsentsByTutor(ctx context.Context, cpf string) ([]model.TutorConsent, error)
+	SaveConsent(ctx context.Context, consent *model.TutorConsent) error
+}
+
+type consentRepository struct {
+	db *gorm.DB
+}
+
+func NewConsentRepository(db *gorm.DB) ConsentRepository {
+	return &consentRepository{db: db}
+}
+
+// FindConsentsByTutor retorna todos os consentimentos do tutor, inclusive os
+// revogados, do mais antigo ao mais recente
+func (r *consentRepository) FindConsentsByTutor(ctx context.Context, cpf string) ([]model.TutorConsent, error) {
+	var consents []model.TutorConsent
+	if err := r.db.WithContext(ctx).Where("cpf_tutor = ?", cpf).Order("granted_at ASC, created_at ASC").Find(&consents).Error; err != nil {
+		log.Print("Error querying consents:", err)
+		return nil, err
+	}
+	return consents, nil
+}
+
+func (r *consentRepository) SaveConsent(ctx context.Context, consent *model.TutorConsent) error {
+	if err := r.db.WithContext(ctx).Save(consent).Error; err != nil {
+		log.Print("Error saving consent:", err)
+		return err
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ type ConsultationRepository interface {
 	DeleteConsultation(ctx context.Context, id uuid.UUID) error
 	FindConsultationByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.Consultation, error)
 	FindConsultationByVeterinaryCRVM(ctx context.Context, crvm string) ([]model.Consultation, error)
+	FindConsultationByVeterinaryCRVMAndDateRange(ctx context.Context, crvm, startDate, endDate string) ([]model.Consultation, error)
 	FindConsultationByDate(ctx context.Context, date string) ([]model.Consultation, error)
 	FindConsultationByDateRange(ctx context.Context, startDate, endDate string) ([]model.Consultation, error)
 	FindConsultationByAnimalIDAndDateRange(ctx context.Context, animalID uuid.UUID, startDate, endDate string) ([]model.Consultation, error)
@@ -30,7 +31,7 @@ type ConsultationRepositoryImpl struct {
 
 // Função para obter a instância do banco de dados
 func GetDB() *gorm.DB {
-	return db.NewDb()
+	return db.GetDB()
 }
 
 // Função para criar uma nova instância do ConsultationRepositoryImpl
@@ -65,6 +66,14 @@ func (repo *ConsultationRepositoryImpl) FindConsultationByVeterinaryCRVM(ctx con
 	return consultations, result.Error
 }
 
+// Método para encontrar as consultas do veterinário em um intervalo de datas
+func (repo *ConsultationRepositoryImpl) FindConsultationByVeterinaryCRVMAndDateRange(ctx context.Context, crvm, startDate, endDate string) ([]model.Consultation, error) {
+	var consultations []model.Consultation
+	result := repo.db.WithContext(ctx).Where("crvm = ? AND consultation_date BETWEEN ? AND ?", crvm, startDate, endDate).
+		Order("consultation_date ASC, consultation_hour ASC").Find(&consultations)
+	return consultations, result.Error
+}
+
 // Método para encontrar consulta por data
 func (repo *ConsultationRepositoryImpl) FindConsultationByDate(ctx context.Context, date string) ([]model.Consultation, error) {
 	var consultations []model.Consultation
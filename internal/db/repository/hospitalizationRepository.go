@@ -9,13 +9,21 @@ import (
 	"gorm.io/gorm"
 )
 
+type HospitalizationRepositoryInterface interface {
+	SaveHospitalization(hospitalization *model.Hospitalization) error
+	FindHospitalizationByID(id string) (*model.Hospitalization, error)
+	DeleteHospitalization(id string) (string, error)
+	GetHospitalizationByID(id uuid.UUID) (*model.Hospitalization, error)
+	FindHospitalizationsByPatientID(patientID uuid.UUID) ([]model.Hospitalization, error)
+}
+
 type HospitalizationRepository struct {
 	Db *gorm.DB
 }
 
 func NewHospitalizationRepository() *HospitalizationRepository {
 	return &HospitalizationRepository{
-		Db: db.NewDb(),
+		Db: db.GetDB(),
 	}
 }
 
@@ -70,4 +78,14 @@ func (r *HospitalizationRepository) GetHospitalizationByID(id uuid.UUID) (*model
 		return nil, err
 	}
 	return &hospitalization, nil
-}
\ No newline at end of file
+}
+
+// Lista as hospitalizações do animal, da mais recente para a mais antiga
+func (r *HospitalizationRepository) FindHospitalizationsByPatientID(patientID uuid.UUID) ([]model.Hospitalization, error) {
+	var hospitalizations []model.Hospitalization
+	if err := r.Db.Where("patient_id = ?", patientID).Order("start_date desc").Find(&hospitalizations).Error; err != nil {
+		log.Print("Error finding hospitalizations:", err)
+		return nil, err
+	}
+	return hospitalizations, nil
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"vetblock/internal/db/model"
+
+	"gorm.io/gorm"
+)
+
+// AnonymizedText substitui os dados pessoais do tutor nos textos livres dos prontuários
+const AnonymizedText = "[anonimizado]"
+
+// FreeTextColumns são colunas de texto livre de uma tabela de prontuário e a
+// coluna que liga cada linha ao animal (ou à consulta, em consultation_id). Em
+// JSON, a troca é feita no texto do jsonb, alcançando os valores antigos e novos
+// guardados no histórico de alterações.
+type FreeTextColumns struct {
+	Table   string
+	Key     string
+	Columns []string
+	JSON    bool
+}
+
+// AnonymizedFreeText lista os textos livres dos prontuários de onde os dados
+// pessoais do tutor são removidos na anonimização
+var AnonymizedFreeText = []FreeTextColumns{
+	{Table: "animals", Key: "id", Columns: []string{"description"}},
+	{Table: "consultations", Key: "animal_id", Columns: []string{"observation", "reason", "consultation_description", "consultation_prescription"}},
+	{Table: "hospitalizations", Key: "patient_id", Columns: []string{"reason"}},
+	{Table: "chronic_conditions", Key: "animal_id", Columns: []string{"notes"}},
+	{Table: "vaccination_records", Key: "animal_id", Columns: []string{"notes"}},
+	{Table: "death_records", Key: "animal_id", Columns: []string{"notes"}},
+	{Table: "animal_histories", Key: "animal_id", Columns: []string{"changes"}, JSON: true},
+	{Table: "consultation_histories", Key: "consultation_id", Columns: []string{"changes"}, JSON: true},
+}
+
+// TutorData reúne os dados pessoais de um tutor e os registros dos seus animais
+// (aqueles de que é tutor principal ou co-tutor), usados na exportação da LGPD
+type TutorData struct {
+	Tutor                model.Tutor                 `json:"tutor"`
+	LegalRepresentatives []model.LegalRepresentative `json:"legal_representatives"`
+	Animals              []model.Animal              `json:"animals"`
+	AnimalTutors         []model.AnimalTutor         `json:"animal_tutors"`
+	OwnershipTransfers   []model.OwnershipTransfer   `json:"ownership_transfers"`
+	Consultations        []model.Consultation        `json:"consultations"`
+	Prescriptions        []model.Dosage              `json:"prescriptions"`
+	Hospitalizations     []model.Hospitalization     `json:"hospitalizations"`
+	Vaccinations         []model.VaccinationRecord   `json:"vaccinations"`
+	Weights              []model.WeightRecord        `json:"weights"`
+	Allergies            []model.Allergy             `json:"allergies"`
+	ChronicConditions    []model.ChronicCondition    `json:"chronic_conditions"`
+	DeathRecords         []model.DeathRecord         `json:"death_records"`
+	Consents             []model.TutorConsent        `json:"consents"`
+	// AnimalTransfers traz todas as transferências dos animais, usadas para limitar
+	// consultas e prescrições ao período do tutor; não é exportado
+	AnimalTransfers []model.OwnershipTransfer `json:"-"`
+}
+
+// TutorAnonymization descreve a anonimização de um tutor: o CPF é trocado pelo
+// pseudônimo em todas as tabelas e Terms são removidos dos textos livres
+type TutorAnonymization struct {
+	CPF       string
+	Pseudonym string
+	Name      string
+	Terms     []string
+	At        time.Time
+}
+
+type LGPDRepository interface {
+	FindTutorData(ctx context.Context, cpf string) (*TutorData, error)
+	AnonymizeTutor(ctx context.Context, anonymization TutorAnonymization, entry *model.AuditLog) error
+	SaveAuditLog(ctx context.Context, entry *model.AuditLog) error
+}
+
+type lgpdRepository struct {
+	db *gorm.DB
+}
+
+func NewLGPDRepository(db *gorm.DB) LGPDRepository {
+	return &lgpdRepository{db: db}
+}
+
+func (r *lgpdRepository) FindTutorData(ctx context.Context, cpf string) (*TutorData, error) {
+	tx := r.db.WithContext(ctx)
+	data := &TutorData{}
+	if err := tx.Where("cpf_tutor = ?", cpf).First(&data.Tutor).Error; err != nil {
+		return nil, err
+	}
+
+	owned := tx.Model(&model.Animal{}).Select("id").
+		Where("cpf_tutor = ? OR id IN (?)", cpf, animalsOfTutor(tx, cpf, model.TutorRoleCoOwner))
+	queries := []struct {
+		dest  interface{}
+		query string
+		args  []interface{}
+	}{
+		{&data.Animals, "id IN (?)", []interface{}{owned}},
+		{&data.AnimalTutors, "cpf_tutor = ?", []interface{}{cpf}},
+		{&data.OwnershipTransfers, "previous_cpf = ? OR new_cpf = ?", []interface{}{cpf, cpf}},
+		{&data.Consents, "cpf_tutor = ?", []interface{}{cpf}},
+		{&data.LegalRepresentatives, "cnpj = ?", []interface{}{cpf}},
+		{&data.Consultations, "animal_id IN (?)", []interface{}{owned}},
+		{&data.Prescriptions, "animal_id IN (?)", []interface{}{owned}},
+		{&data.Hospitalizations, "patient_id IN (?)", []interface{}{owned}},
+		{&data.Vaccinations, "animal_id IN (?)", []interface{}{owned}},
+		{&data.Weights, "animal_id IN (?)", []interface{}{owned}},
+		{&data.Allergies, "animal_id IN (?)", []interface{}{owned}},
+		{&data.ChronicConditions, "animal_id IN (?)", []interface{}{owned}},
+		{&data.DeathRecords, "animal_id IN (?)", []interface{}{owned}},
+		{&data.AnimalTransfers, "animal_id IN (?)", []interface{}{owned}},
+	}
+	for _, q := range queries {
+		if err := tx.Where(q.query, q.args...).Order("created_at ASC").Find(q.dest).Error; err != nil {
+			log.Print("Error exporting tutor data:", err)
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// AnonymizeTutor troca o CPF do tutor pelo pseudônimo em todas as tabelas, apaga
+// seus contatos, revoga os consentimentos em vigor e remove nome, CPF, e-mail e
+// telefone dos textos livres dos seus animais (AnonymizedFreeText), inclusive dos
+// valores guardados no histórico de alterações. Os registros clínicos são mantidos.
+func (r *lgpdRepository) AnonymizeTutor(ctx context.Context, a TutorAnonymization, entry *model.AuditLog) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var tutor model.Tutor
+		if err := tx.Where("cpf_tutor = ?", a.CPF).First(&tutor).Error; err != nil {
+			return err
+		}
+
+		// animals, animal_tutors e tutor_consents seguem a troca do CPF por ON UPDATE
+		// CASCADE. Cidade e UF do endereço são mantidas para as estatísticas por região.
+		if err := tx.Model(&model.Tutor{}).Where("cpf_tutor = ?", a.CPF).Updates(map[string]interface{}{
+			"cpf_tutor":            a.Pseudonym,
+			"name":                 a.Name,
+			"email":                "",
+			"phone":                "",
+			"address":              "",
+			"address_cep":          "",
+			"address_street":       "",
+			"address_number":       "",
+			"address_complement":   "",
+			"address_neighborhood": "",
+			"active":               false,
+			"firebase_uid":         nil,
+			"anonymized_at":        a.At,
+		}).Error; err != nil {
+			return err
+		}
+		// Sem contato, não há mais como enviar lembretes nem campanhas ao titular
+		if err := tx.Model(&model.TutorConsent{}).Where("cpf_tutor IN (?, ?) AND revoked_at IS NULL", a.CPF, a.Pseudonym).Updates(map[string]interface{}{
+			"revoked_at": a.At,
+			"revoked_by": entry.PerformedBy,
+		}).Error; err != nil {
+			return err
+		}
+		if tutor.Email != "" {
+			if err := tx.Where("LOWER(email) = LOWER(?) AND user_type = ?", tutor.Email, model.TutorType).Delete(&model.User{}).Error; err != nil {
+				return err
+			}
+		}
+
+		renames := []struct {
+			table  interface{}
+			column string
+		}{
+			{&model.OwnershipTransfer{}, "previous_cpf"},
+			{&model.OwnershipTransfer{}, "new_cpf"},
+			{&model.Consultation{}, "authorized_by"},
+			{&model.DeathRecord{}, "authorized_by"},
+		}
+		for _, rename := range renames {
+			if err := tx.Unscoped().Model(rename.table).Where(rename.column+" = ?", a.CPF).
+				Update(rename.column, a.Pseudonym).Error; err != nil {
+				return err
+			}
+		}
+		for _, table := range []string{"animal_histories", "consultation_histories"} {
+			if err := tx.Exec("UPDATE "+table+" SET changes = replace(changes::text, ?, ?)::jsonb WHERE changes::text LIKE ?",
+				a.CPF, a.Pseudonym, "%"+a.CPF+"%").Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&model.AuditLog{}).Where("entity_type = ? AND entity_key = ?", "tutor", a.CPF).
+			Update("entity_key", a.Pseudonym).Error; err != nil {
+			return err
+		}
+
+		// Textos livres dos animais em que o tutor tem qualquer papel
+		if pattern := AnonymizationPattern(a.Terms); pattern != "" {
+			animals := tx.Unscoped().Model(&model.Animal{}).Select("id").
+				Where("cpf_tutor = ? OR id IN (?)", a.Pseudonym, animalsOfTutor(tx, a.Pseudonym, ""))
+			consultations := tx.Unscoped().Model(&model.Consultation{}).Select("id").Where("animal_id IN (?)", animals)
+			for _, n := range AnonymizedFreeText {
+				scope := animals
+				if n.Key == "consultation_id" {
+					scope = consultations
+				}
+				for _, column := range n.Columns {
+					value := "regexp_replace(" + column + ", ?, ?, 'gi')"
+					if n.JSON {
+						value = "regexp_replace(" + column + "::text, ?, ?, 'gi')::jsonb"
+					}
+					if err := tx.Exec("UPDATE "+n.Table+" SET "+column+" = "+value+" WHERE "+n.Key+" IN (?)",
+						pattern, AnonymizedText, scope).Error; err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		return tx.Create(entry).Error
+	})
+}
+
+func (r *lgpdRepository) SaveAuditLog(ctx context.Context, entry *model.AuditLog) error {
+	if err := r.db.WithContext(ctx).Create(entry).Error; err != nil {
+		log.Print("Error saving audit log:", err)
+		return err
+	}
+	return nil
+}
+
+// AnonymizationPattern monta a expressão que encontra qualquer um dos termos como
+// palavra inteira. A fronteira de palavra do PostgreSQL (\y) só é exigida nas
+// pontas do termo que são letras ou dígitos: um telefone como "(11) 98765-4321"
+// começa com um parêntese e não teria fronteira de palavra antes dele.
+func AnonymizationPattern(terms []string) string {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		runes := []rune(term)
+		quotedTerm := regexp.QuoteMeta(term)
+		if isWordRune(runes[0]) {
+			quotedTerm = `\y` + quotedTerm
+		}
+		if isWordRune(runes[len(runes)-1]) {
+			quotedTerm += `\y`
+		}
+		quoted = append(quoted, quotedTerm)
+	}
+	if len(quoted) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(quoted, "|") + ")"
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -16,7 +16,7 @@ type MedicationRepository struct {
 
 func NewMedicationRepository() *MedicationRepository {
 	return &MedicationRepository{
-		Db: db.NewDb(),
+		Db: db.GetDB(),
 	}
 }
 
@@ -1,6 +1,8 @@
 package repository
 
 import (
+    "time"
+
     "github.com/google/uuid"
     "github.com/stretchr/testify/mock"
     "vetblock/internal/db/model"
@@ -48,3 +50,134 @@ func (m *MockAnimalRepository) FindAllAnimals() ([]model.Animal, error) {
     }
     return nil, args.Error(1)
 }
+
+func (m *MockAnimalRepository) UpdateAnimal(animal *model.Animal, history *model.AnimalHistory) error {
+    args := m.Called(animal, history)
+    return args.Error(0)
+}
+
+func (m *MockAnimalRepository) FindAnimalHistory(animalID uuid.UUID) ([]model.AnimalHistory, error) {
+    args := m.Called(animalID)
+    if obj, ok := args.Get(0).([]model.AnimalHistory); ok {
+        return obj, args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockAnimalRepository) QueryAnimals(query AnimalQuery) (*AnimalPage, error) {
+    args := m.Called(query)
+    if obj, ok := args.Get(0).(*AnimalPage); ok {
+        return obj, args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockAnimalRepository) TransferOwnership(animal *model.Animal, transfer *model.OwnershipTransfer, history *model.AnimalHistory) error {
+    args := m.Called(animal, transfer, history)
+    return args.Error(0)
+}
+
+func (m *MockAnimalRepository) FindOwnershipTransfers(animalID uuid.UUID) ([]model.OwnershipTransfer, error) {
+    args := m.Called(animalID)
+    if obj, ok := args.Get(0).([]model.OwnershipTransfer); ok {
+        return obj, args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockAnimalRepository) FindAnimalByMicrochip(number string) (*model.Animal, error) {
+    args := m.Called(number)
+    if obj, ok := args.Get(0).(*model.Animal); ok {
+        return obj, args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockAnimalRepository) FindDeletedAnimals() ([]model.Animal, error) {
+    args := m.Called()
+    if obj, ok := args.Get(0).([]model.Animal); ok {
+        return obj, args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockAnimalRepository) FindDeletedAnimalByID(id uuid.UUID) (*model.Animal, error) {
+    args := m.Called(id)
+    if obj, ok := args.Get(0).(*model.Animal); ok {
+        return obj, args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockAnimalRepository) RestoreAnimal(animal *model.Animal, entry *model.AuditLog) error {
+    args := m.Called(animal, entry)
+    return args.Error(0)
+}
+
+func (m *MockAnimalRepository) PurgeDeletedAnimals(before time.Time, performedBy string) ([]uuid.UUID, error) {
+    args := m.Called(before, performedBy)
+    if obj, ok := args.Get(0).([]uuid.UUID); ok {
+        return obj, args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockAnimalRepository) FindAnimalsByTutorAndSpecies(cpfTutor, species string) ([]model.Animal, error) {
+    args := m.Called(cpfTutor, species)
+    if obj, ok := args.Get(0).([]model.Animal); ok {
+        return obj, args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockAnimalRepository) MergeAnimals(source, target *model.Animal, merge *model.AnimalMerge, history *model.AnimalHistory) error {
+    args := m.Called(source, target, merge, history)
+    return args.Error(0)
+}
+
+func (m *MockAnimalRepository) FindAnimalMerges(animalID uuid.UUID) ([]model.AnimalMerge, error) {
+    args := m.Called(animalID)
+    if obj, ok := args.Get(0).([]model.AnimalMerge); ok {
+        return obj, args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockAnimalRepository) RecordDeath(animal *model.Animal, record *model.DeathRecord, history *model.AnimalHistory, cancelFrom time.Time) (int64, error) {
+    args := m.Called(animal, record, history, cancelFrom)
+    return int64(args.Int(0)), args.Error(1)
+}
+
+func (m *MockAnimalRepository) FindDeathRecord(animalID uuid.UUID) (*model.DeathRecord, error) {
+    args := m.Called(animalID)
+    if obj, ok := args.Get(0).(*model.DeathRecord); ok {
+        return obj, args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockAnimalRepository) FindAnimalTutors(animalID uuid.UUID) ([]model.AnimalTutor, error) {
+    args := m.Called(animalID)
+    if obj, ok := args.Get(0).([]model.AnimalTutor); ok {
+        return obj, args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockAnimalRepository) SaveAnimalTutor(link *model.AnimalTutor, history *model.AnimalHistory) error {
+    args := m.Called(link, history)
+    return args.Error(0)
+}
+
+func (m *MockAnimalRepository) DeleteAnimalTutor(link *model.AnimalTutor, history *model.AnimalHistory) error {
+    args := m.Called(link, history)
+    return args.Error(0)
+}
+
+func (m *MockAnimalRepository) FindAnimalsOwnedBy(cpf string) ([]model.Animal, error) {
+    args := m.Called(cpf)
+    if obj, ok := args.Get(0).([]model.Animal); ok {
+        return obj, args.Error(1)
+    }
+    return nil, args.Error(1)
+}
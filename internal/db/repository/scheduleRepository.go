@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ScheduleRepository interface {
+	FindWorkingHours(ctx context.Context, crvm string) ([]model.WorkingHours, error)
+	ReplaceWorkingHours(ctx context.Context, crvm string, hours []model.WorkingHours) error
+	FindExceptions(ctx context.Context, crvm, startDate, endDate string) ([]model.AvailabilityException, error)
+	FindExceptionByID(ctx context.Context, id uuid.UUID) (*model.AvailabilityException, error)
+	SaveException(ctx context.Context, exception *model.AvailabilityException) error
+	DeleteException(ctx context.Context, id uuid.UUID) error
+}
+
+type scheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleRepository(db *gorm.DB) ScheduleRepository {
+	return &scheduleRepository{db: db}
+}
+
+// FindWorkingHours retorna os intervalos de atendimento semanais do veterinário,
+// ordenados por dia da semana e horário de início
+func (r *scheduleRepository) FindWorkingHours(ctx context.Context, crvm string) ([]model.WorkingHours, error) {
+	var hours []model.WorkingHours
+	if err := r.db.WithContext(ctx).Where("crvm = ?", crvm).Order("weekday ASC, start_time ASC").Find(&hours).Error; err != nil {
+		log.Print("Error querying working hours:", err)
+		return nil, err
+	}
+	return hours, nil
+}
+
+// ReplaceWorkingHours substitui, em uma transação, todos os intervalos semanais do
+// veterinário e registra em veterinaries.hours_set_at que o horário foi cadastrado,
+// para distinguir um horário esvaziado de um que nunca existiu
+func (r *scheduleRepository) ReplaceWorkingHours(ctx context.Context, crvm string, hours []model.WorkingHours) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("crvm = ?", crvm).Delete(&model.WorkingHours{}).Error; err != nil {
+			log.Print("Error deleting working hours:", err)
+			return err
+		}
+		if err := tx.Model(&model.Veterinary{}).Where("crvm = ?", crvm).Update("hours_set_at", time.Now()).Error; err != nil {
+			log.Print("Error updating veterinary working hours date:", err)
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		if err := tx.Create(&hours).Error; err != nil {
+			log.Print("Error saving working hours:", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// FindExceptions retorna as ausências do veterinário que se sobrepõem ao período,
+// com as datas no formato 2006-01-02
+func (r *scheduleRepository) FindExceptions(ctx context.Context, crvm, startDate, endDate string) ([]model.AvailabilityException, error) {
+	var exceptions []model.AvailabilityException
+	if err := r.db.WithContext(ctx).
+		Where("crvm = ? AND start_date <= ? AND end_date >= ?", crvm, endDate, startDate).
+		Order("start_date ASC, start_time ASC").Find(&exceptions).Error; err != nil {
+		log.Print("Error querying availability exceptions:", err)
+		return nil, err
+	}
+	return exceptions, nil
+}
+
+func (r *scheduleRepository) FindExceptionByID(ctx context.Context, id uuid.UUID) (*model.AvailabilityException, error) {
+	var exception model.AvailabilityException
+	if err := r.db.WithContext(ctx).First(&exception, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &exception, nil
+}
+
+func (r *scheduleRepository) SaveException(ctx context.Context, exception *model.AvailabilityException) error {
+	if err := r.db.WithContext(ctx).Save(exception).Error; err != nil {
+		log.Print("Error saving availability exception:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *scheduleRepository) DeleteException(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Delete(&model.AvailabilityException{}, "id = ?", id).Error; err != nil {
+		log.Print("Error deleting availability exception:", err)
+		return err
+	}
+	return nil
+}
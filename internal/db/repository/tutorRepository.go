@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"strings"
+	"vetblock/internal/db/model"
+
+	"gorm.io/gorm"
+)
+
+// TutorQuery filtra a busca de tutores. Name e Email buscam por trecho, sem
+// diferenciar maiúsculas; Phone compara apenas os dígitos do telefone. City e
+// Neighborhood comparam o nome inteiro, sem diferenciar maiúsculas.
+type TutorQuery struct {
+	Name            string
+	Phone           string
+	Email           string
+	OwnerType       string
+	UF              string
+	City            string
+	Neighborhood    string
+	IncludeInactive bool
+	Limit           int
+}
+
+// TutorRegionCount é o número de tutores ativos de um bairro
+type TutorRegionCount struct {
+	UF           string `json:"uf"`
+	City         string `json:"city"`
+	Neighborhood string `json:"neighborhood"`
+	Tutors       int64  `json:"tutors"`
+}
+
+type TutorRepository interface {
+	FindTutorByCPF(ctx context.Context, cpf string) (*model.Tutor, error)
+	FindTutorByUID(ctx context.Context, uid string) (*model.Tutor, error)
+	FindUnboundTutorByEmail(ctx context.Context, email string) (*model.Tutor, error)
+	CreateTutor(ctx context.Context, tutor *model.Tutor) error
+	CreateTutorWithAnimal(ctx context.Context, tutor *model.Tutor, animal *model.Animal) error
+	ListTutors(ctx context.Context, query TutorQuery) ([]model.Tutor, error)
+	UpdateTutor(ctx context.Context, tutor *model.Tutor) error
+	FindLegalRepresentatives(ctx context.Context, cnpj string) ([]model.LegalRepresentative, error)
+	SaveLegalRepresentative(ctx context.Context, representative *model.LegalRepresentative) error
+	DeleteLegalRepresentative(ctx context.Context, representative *model.LegalRepresentative) error
+	CountTutorsByRegion(ctx context.Context, uf, city string) ([]TutorRegionCount, error)
+	FindTutorsWithLegacyAddress(ctx context.Context) ([]model.Tutor, error)
+}
+
+type tutorRepository struct {
+	db *gorm.DB
+}
+
+func NewTutorRepository(db *gorm.DB) TutorRepository {
+	return &tutorRepository{db: db}
+}
+
+func (r *tutorRepository) FindTutorByCPF(ctx context.Context, cpf string) (*model.Tutor, error) {
+	var tutor model.Tutor
+	if err := r.db.WithContext(ctx).Where("cpf_tutor = ?", cpf).First(&tutor).Error; err != nil {
+		return nil, err
+	}
+	return &tutor, nil
+}
+
+func (r *tutorRepository) FindTutorByUID(ctx context.Context, uid string) (*model.Tutor, error) {
+	var tutor model.Tutor
+	if err := r.db.WithContext(ctx).Where("firebase_uid = ?", uid).First(&tutor).Error; err != nil {
+		return nil, err
+	}
+	return &tutor, nil
+}
+
+// FindUnboundTutorByEmail busca, sem diferenciar maiúsculas, um tutor que ainda não
+// tem usuário do Firebase vinculado
+func (r *tutorRepository) FindUnboundTutorByEmail(ctx context.Context, email string) (*model.Tutor, error) {
+	var tutor model.Tutor
+	if err := r.db.WithContext(ctx).Where("LOWER(email) = LOWER(?) AND firebase_uid IS NULL", email).First(&tutor).Error; err != nil {
+		return nil, err
+	}
+	return &tutor, nil
+}
+
+func (r *tutorRepository) CreateTutor(ctx context.Context, tutor *model.Tutor) error {
+	if err := r.db.WithContext(ctx).Create(tutor).Error; err != nil {
+		log.Print("Error saving tutor:", err)
+		return err
+	}
+	log.Print("Repository Saving Tutor")
+	return nil
+}
+
+// CreateTutorWithAnimal grava o tutor e, quando informado, o seu animal na mesma
+// transação, para que uma falha no animal não deixe o tutor gravado sozinho
+func (r *tutorRepository) CreateTutorWithAnimal(ctx context.Context, tutor *model.Tutor, animal *model.Animal) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(tutor).Error; err != nil {
+			log.Print("Error saving tutor:", err)
+			return err
+		}
+		if animal != nil {
+			if err := tx.Create(animal).Error; err != nil {
+				log.Print("Error saving animal:", err)
+				return err
+			}
+		}
+		log.Print("Repository Saving Tutor")
+		return nil
+	})
+}
+
+func (r *tutorRepository) ListTutors(ctx context.Context, query TutorQuery) ([]model.Tutor, error) {
+	tx := r.db.WithContext(ctx).Model(&model.Tutor{})
+	if !query.IncludeInactive {
+		tx = tx.Where("active = ?", true)
+	}
+	if query.Name != "" {
+		tx = tx.Where("name ILIKE ?", "%"+escapeLike(query.Name)+"%")
+	}
+	if query.Email != "" {
+		tx = tx.Where("email ILIKE ?", "%"+escapeLike(strings.TrimSpace(query.Email))+"%")
+	}
+	if query.OwnerType != "" {
+		tx = tx.Where("owner_type = ?", query.OwnerType)
+	}
+	if query.Phone != "" {
+		tx = tx.Where("regexp_replace(phone, '[^0-9]', '', 'g') LIKE ?", "%"+query.Phone+"%")
+	}
+	if query.UF != "" {
+		tx = tx.Where("address_uf = ?", query.UF)
+	}
+	if query.City != "" {
+		tx = tx.Where("LOWER(address_city) = LOWER(?)", query.City)
+	}
+	if query.Neighborhood != "" {
+		tx = tx.Where("LOWER(address_neighborhood) = LOWER(?)", query.Neighborhood)
+	}
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
+
+	var tutors []model.Tutor
+	if err := tx.Order("name ASC, cpf_tutor ASC").Find(&tutors).Error; err != nil {
+		log.Print("Error querying tutors:", err)
+		return nil, err
+	}
+	return tutors, nil
+}
+
+func (r *tutorRepository) UpdateTutor(ctx context.Context, tutor *model.Tutor) error {
+	if err := r.db.WithContext(ctx).Save(tutor).Error; err != nil {
+		log.Print("Error updating tutor:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *tutorRepository) FindLegalRepresentatives(ctx context.Context, cnpj string) ([]model.LegalRepresentative, error) {
+	var representatives []model.LegalRepresentative
+	if err := r.db.WithContext(ctx).Where("cnpj = ?", cnpj).Order("name ASC").Find(&representatives).Error; err != nil {
+		log.Print("Error querying legal representatives:", err)
+		return nil, err
+	}
+	return representatives, nil
+}
+
+func (r *tutorRepository) SaveLegalRepresentative(ctx context.Context, representative *model.LegalRepresentative) error {
+	if err := r.db.WithContext(ctx).Save(representative).Error; err != nil {
+		log.Print("Error saving legal representative:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *tutorRepository) DeleteLegalRepresentative(ctx context.Context, representative *model.LegalRepresentative) error {
+	if err := r.db.WithContext(ctx).Delete(representative).Error; err != nil {
+		log.Print("Error deleting legal representative:", err)
+		return err
+	}
+	return nil
+}
+
+// CountTutorsByRegion conta os tutores ativos com endereço estruturado por UF,
+// cidade e bairro, opcionalmente restritos a uma UF e a uma cidade
+func (r *tutorRepository) CountTutorsByRegion(ctx context.Context, uf, city string) ([]TutorRegionCount, error) {
+	tx := r.db.WithContext(ctx).Model(&model.Tutor{}).
+		Select("address_uf AS uf, address_city AS city, address_neighborhood AS neighborhood, COUNT(*) AS tutors").
+		Where("active = ? AND address_cep <> ''", true)
+	if uf != "" {
+		tx = tx.Where("address_uf = ?", uf)
+	}
+	if city != "" {
+		tx = tx.Where("LOWER(address_city) = LOWER(?)", city)
+	}
+
+	var counts []TutorRegionCount
+	if err := tx.Group("address_uf, address_city, address_neighborhood").
+		Order("address_uf ASC, address_city ASC, address_neighborhood ASC").Scan(&counts).Error; err != nil {
+		log.Print("Error counting tutors by region:", err)
+		return nil, err
+	}
+	return counts, nil
+}
+
+// FindTutorsWithLegacyAddress busca os tutores que só têm o endereço em texto livre
+func (r *tutorRepository) FindTutorsWithLegacyAddress(ctx context.Context) ([]model.Tutor, error) {
+	var tutors []model.Tutor
+	if err := r.db.WithContext(ctx).
+		Where("address <> '' AND COALESCE(address_cep, '') = '' AND anonymized_at IS NULL").
+		Order("cpf_tutor ASC").Find(&tutors).Error; err != nil {
+		log.Print("Error querying tutors with legacy addresses:", err)
+		return nil, err
+	}
+	return tutors, nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrBatchOutOfStock indica que o lote acabou entre a verificação do estoque e a baixa
+var ErrBatchOutOfStock = errors.New("o lote da vacina está sem estoque")
+
+// VaccinationDue é a dose mais recente de um protocolo aplicada em um animal, com o
+// vencimento da próxima dose
+type VaccinationDue struct {
+	AnimalID      uuid.UUID `json:"animal_id"`
+	AnimalName    string    `json:"animal_name"`
+	CPFTutor      string    `json:"cpf_tutor"`
+	ProtocolID    uuid.UUID `json:"protocol_id"`
+	ProtocolName  string    `json:"protocol_name"`
+	LastDose      int       `json:"last_dose"`
+	LastAppliedAt time.Time `json:"last_applied_at"`
+	NextDueDate   time.Time `json:"next_due_date"`
+}
+
+type VaccinationRepository interface {
+	ListProtocols(ctx context.Context, species string) ([]model.VaccinationProtocol, error)
+	FindProtocolByID(ctx context.Context, id uuid.UUID) (*model.VaccinationProtocol, error)
+	SaveProtocol(ctx context.Context, protocol *model.VaccinationProtocol) error
+	ListProducts(ctx context.Context) ([]model.VaccineProduct, error)
+	FindProductByID(ctx context.Context, id uuid.UUID) (*model.VaccineProduct, error)
+	SaveProduct(ctx context.Context, product *model.VaccineProduct) error
+	FindBatch(ctx context.Context, medicationID uuid.UUID) (*model.Medication, error)
+	CreateRecord(ctx context.Context, record *model.VaccinationRecord) error
+	FindRecordsByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.VaccinationRecord, error)
+	FindDue(ctx context.Context, until time.Time, cpfTutor string) ([]VaccinationDue, error)
+}
+
+type vaccinationRepository struct {
+	db *gorm.DB
+}
+
+func NewVaccinationRepository(db *gorm.DB) VaccinationRepository {
+	return &vaccinationRepository{db: db}
+}
+
+// ListProtocols lista os protocolos, opcionalmente só os da espécie informada
+func (r *vaccinationRepository) ListProtocols(ctx context.Context, species string) ([]model.VaccinationProtocol, error) {
+	var protocols []model.VaccinationProtocol
+	tx := r.db.WithContext(ctx).Order("species asc, name asc")
+	if species != "" {
+		tx = tx.Where("LOWER(species) = LOWER(?)", species)
+	}
+	if err := tx.Find(&protocols).Error; err != nil {
+		log.Print("Error listing vaccination protocols:", err)
+		return nil, err
+	}
+	return protocols, nil
+}
+
+func (r *vaccinationRepository) FindProtocolByID(ctx context.Context, id uuid.UUID) (*model.VaccinationProtocol, error) {
+	var protocol model.VaccinationProtocol
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&protocol).Error; err != nil {
+		log.Print("Error finding vaccination protocol:", err)
+		return nil, err
+	}
+	return &protocol, nil
+}
+
+func (r *vaccinationRepository) SaveProtocol(ctx context.Context, protocol *model.VaccinationProtocol) error {
+	if err := r.db.WithContext(ctx).Save(protocol).Error; err != nil {
+		log.Print("Error saving vaccination protocol:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *vaccinationRepository) ListProducts(ctx context.Context) ([]model.VaccineProduct, error) {
+	var products []model.VaccineProduct
+	if err := r.db.WithContext(ctx).Order("name asc").Find(&products).Error; err != nil {
+		log.Print("Error listing vaccine products:", err)
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *vaccinationRepository) FindProductByID(ctx context.Context, id uuid.UUID) (*model.VaccineProduct, error) {
+	var product model.VaccineProduct
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&product).Error; err != nil {
+		log.Print("Error finding vaccine product:", err)
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *vaccinationRepository) SaveProduct(ctx context.Context, product *model.VaccineProduct) error {
+	if err := r.db.WithContext(ctx).Save(product).Error; err != nil {
+		log.Print("Error saving vaccine product:", err)
+		return err
+	}
+	return nil
+}
+
+// FindBatch busca o medicamento do estoque que representa um lote de vacina
+func (r *vaccinationRepository) FindBatch(ctx context.Context, medicationID uuid.UUID) (*model.Medication, error) {
+	var medication model.Medication
+	if err := r.db.WithContext(ctx).Where("id = ?", medicationID).First(&medication).Error; err != nil {
+		log.Print("Error finding vaccine batch:", err)
+		return nil, err
+	}
+	return &medication, nil
+}
+
+// CreateRecord grava a aplicação e, quando ela usa um lote do estoque, baixa uma
+// unidade desse lote na mesma transação. A baixa só acontece com estoque
+// positivo; sem estoque, a aplicação é desfeita e retorna ErrBatchOutOfStock.
+func (r *vaccinationRepository) CreateRecord(ctx context.Context, record *model.VaccinationRecord) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(record).Error; err != nil {
+			log.Print("Error saving vaccination record:", err)
+			return err
+		}
+		if record.MedicationID != nil {
+			result := tx.Model(&model.Medication{}).Where("id = ? AND quantity > 0", *record.MedicationID).Update("quantity", gorm.Expr("quantity - 1"))
+			if result.Error != nil {
+				log.Print("Error updating vaccine batch quantity:", result.Error)
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrBatchOutOfStock
+			}
+		}
+		log.Print("Repository Saving Vaccination Record")
+		return nil
+	})
+}
+
+func (r *vaccinationRepository) FindRecordsByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.VaccinationRecord, error) {
+	var records []model.VaccinationRecord
+	if err := r.db.WithContext(ctx).Where("animal_id = ?", animalID).Order("applied_at asc, dose_number asc").Find(&records).Error; err != nil {
+		log.Print("Error finding vaccination records:", err)
+		return nil, err
+	}
+	return records, nil
+}
+
+// FindDue considera apenas a dose mais recente de cada protocolo em cada animal ativo e vivo e
+// devolve as que têm a próxima dose vencendo até "until", da mais atrasada à mais distante
+func (r *vaccinationRepository) FindDue(ctx context.Context, until time.Time, cpfTutor string) ([]VaccinationDue, error) {
+	latest := r.db.Model(&model.VaccinationRecord{}).
+		Select("DISTINCT ON (animal_id, protocol_id) *").
+		Order("animal_id, protocol_id, applied_at DESC, dose_number DESC")
+
+	tx := r.db.WithContext(ctx).Table("(?) AS r", latest).
+		Select(`a.id AS animal_id, a.name AS animal_name, a.cpf_tutor, p.id AS protocol_id, p.name AS protocol_name,
+			r.dose_number AS last_dose, r.applied_at AS last_applied_at, r.next_due_date`).
+		Joins("JOIN animals a ON a.id = r.animal_id AND a.deleted_at IS NULL AND a.deceased_at IS NULL").
+		Joins("JOIN vaccination_protocols p ON p.id = r.protocol_id").
+		Where("r.next_due_date IS NOT NULL AND r.next_due_date <= ?", until)
+	if cpfTutor != "" {
+		tx = tx.Where("(a.cpf_tutor = ? OR a.id IN (SELECT animal_id FROM animal_tutors WHERE cpf_tutor = ?))", cpfTutor, cpfTutor)
+	}
+
+	var due []VaccinationDue
+	if err := tx.Order("r.next_due_date ASC, a.name ASC").Scan(&due).Error; err != nil {
+		log.Print("Error finding due vaccinations:", err)
+		return nil, err
+	}
+	return due, nil
+}
@@ -1,40 +1,171 @@
 package repository
 
 import (
+	"context"
 	"log"
-
-	"vetblock/internal/db"
 	"vetblock/internal/db/model"
 
 	"gorm.io/gorm"
 )
 
-type VeterinaryRepository struct {
-	Db *gorm.DB
+// VeterinaryQuery filtra a listagem de veterinários. Name busca por trecho do nome
+// ou do sobrenome e Specialty por trecho da especialidade, sem diferenciar maiúsculas.
+type VeterinaryQuery struct {
+	Name      string
+	Specialty string
+}
+
+type VeterinaryRepository interface {
+	FindVeterinaryByCRVM(ctx context.Context, crvm string) (*model.Veterinary, error)
+	FindVeterinaryIncludingDeleted(ctx context.Context, crvm string) (*model.Veterinary, error)
+	FindVeterinaryByUID(ctx context.Context, uid string) (*model.Veterinary, error)
+	FindUnboundVeterinaryByEmail(ctx context.Context, email string) (*model.Veterinary, error)
+	ListVeterinaries(ctx context.Context, query VeterinaryQuery) ([]model.Veterinary, error)
+	CreateVeterinary(ctx context.Context, veterinary *model.Veterinary) error
+	UpdateVeterinary(ctx context.Context, veterinary *model.Veterinary) error
+	DeleteVeterinary(ctx context.Context, veterinary *model.Veterinary) error
+	RestoreVeterinary(ctx context.Context, veterinary *model.Veterinary) error
+	FindLicenses(ctx context.Context, crvm string) ([]model.VetLicense, error)
+	SaveLicense(ctx context.Context, license *model.VetLicense) error
+	DeleteLicense(ctx context.Context, license *model.VetLicense) error
+	FindLicensesExpiringBy(ctx context.Context, date string) ([]model.VetLicense, error)
+}
+
+type veterinaryRepository struct {
+	db *gorm.DB
+}
+
+func NewVeterinaryRepository(db *gorm.DB) VeterinaryRepository {
+	return &veterinaryRepository{db: db}
+}
+
+// FindVeterinaryByCRVM busca um veterinário que não foi removido
+func (r *veterinaryRepository) FindVeterinaryByCRVM(ctx context.Context, crvm string) (*model.Veterinary, error) {
+	var veterinary model.Veterinary
+	if err := r.db.WithContext(ctx).Where("crvm = ?", crvm).First(&veterinary).Error; err != nil {
+		return nil, err
+	}
+	return &veterinary, nil
+}
+
+// FindVeterinaryIncludingDeleted busca o veterinário, inclusive removido
+func (r *veterinaryRepository) FindVeterinaryIncludingDeleted(ctx context.Context, crvm string) (*model.Veterinary, error) {
+	var veterinary model.Veterinary
+	if err := r.db.WithContext(ctx).Unscoped().Where("crvm = ?", crvm).First(&veterinary).Error; err != nil {
+		return nil, err
+	}
+	return &veterinary, nil
+}
+
+// FindVeterinaryByUID busca o veterinário, não removido, vinculado ao usuário do Firebase
+func (r *veterinaryRepository) FindVeterinaryByUID(ctx context.Context, uid string) (*model.Veterinary, error) {
+	var veterinary model.Veterinary
+	if err := r.db.WithContext(ctx).Where("firebase_uid = ?", uid).First(&veterinary).Error; err != nil {
+		return nil, err
+	}
+	return &veterinary, nil
 }
 
-func NewVeterinaryRepository() *VeterinaryRepository {
-	return &VeterinaryRepository{
-		Db: db.NewDb(),
+// FindUnboundVeterinaryByEmail busca, sem diferenciar maiúsculas, um veterinário não
+// removido que ainda não tem usuário do Firebase vinculado
+func (r *veterinaryRepository) FindUnboundVeterinaryByEmail(ctx context.Context, email string) (*model.Veterinary, error) {
+	var veterinary model.Veterinary
+	if err := r.db.WithContext(ctx).Where("LOWER(email) = LOWER(?) AND firebase_uid IS NULL", email).First(&veterinary).Error; err != nil {
+		return nil, err
 	}
+	return &veterinary, nil
 }
 
-func (r *VeterinaryRepository) SaveVeterinary(veterinary model.Veterinary) error {
-	log.Println("saving veterinary transaction")
-	if err := r.Db.Create(veterinary).Error; err != nil {
+func (r *veterinaryRepository) ListVeterinaries(ctx context.Context, query VeterinaryQuery) ([]model.Veterinary, error) {
+	tx := r.db.WithContext(ctx).Model(&model.Veterinary{})
+	if query.Name != "" {
+		pattern := "%" + escapeLike(query.Name) + "%"
+		tx = tx.Where("name ILIKE ? OR last_name ILIKE ?", pattern, pattern)
+	}
+	if query.Specialty != "" {
+		tx = tx.Where("specialty ILIKE ?", "%"+escapeLike(query.Specialty)+"%")
+	}
+
+	var veterinaries []model.Veterinary
+	if err := tx.Order("name ASC, last_name ASC, crvm ASC").Find(&veterinaries).Error; err != nil {
+		log.Print("Error querying veterinaries:", err)
+		return nil, err
+	}
+	return veterinaries, nil
+}
+
+func (r *veterinaryRepository) CreateVeterinary(ctx context.Context, veterinary *model.Veterinary) error {
+	if err := r.db.WithContext(ctx).Create(veterinary).Error; err != nil {
 		log.Print("Error saving veterinary:", err)
 		return err
 	}
-	log.Print(veterinary)
-	log.Print("Repository Saving Veterinary")
 	return nil
 }
 
-func (r *VeterinaryRepository) FindVeterinaryByCRVM(crvm string) (*model.Veterinary, error) {
-	var veterinary model.Veterinary
-	if err := r.Db.Where("crvm = ? AND deleted_at IS NULL", crvm).First(&veterinary).Error; err != nil {
-		log.Print("Error finding veterinary:", err)
+func (r *veterinaryRepository) UpdateVeterinary(ctx context.Context, veterinary *model.Veterinary) error {
+	if err := r.db.WithContext(ctx).Save(veterinary).Error; err != nil {
+		log.Print("Error updating veterinary:", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteVeterinary remove o veterinário por soft delete; as consultas e os óbitos
+// continuam apontando para o CRMV
+func (r *veterinaryRepository) DeleteVeterinary(ctx context.Context, veterinary *model.Veterinary) error {
+	if err := r.db.WithContext(ctx).Where("crvm = ?", veterinary.CRVM).Delete(&model.Veterinary{}).Error; err != nil {
+		log.Print("Error deleting veterinary:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *veterinaryRepository) RestoreVeterinary(ctx context.Context, veterinary *model.Veterinary) error {
+	if err := r.db.WithContext(ctx).Unscoped().Model(&model.Veterinary{}).Where("crvm = ?", veterinary.CRVM).
+		Update("deleted_at", nil).Error; err != nil {
+		log.Print("Error restoring veterinary:", err)
+		return err
+	}
+	veterinary.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+// FindLicenses retorna as inscrições do veterinário, ordenadas por UF
+func (r *veterinaryRepository) FindLicenses(ctx context.Context, crvm string) ([]model.VetLicense, error) {
+	var licenses []model.VetLicense
+	if err := r.db.WithContext(ctx).Where("crvm = ?", crvm).Order("uf ASC").Find(&licenses).Error; err != nil {
+		log.Print("Error querying veterinary licenses:", err)
 		return nil, err
 	}
-	return &veterinary, nil
-}
\ No newline at end of file
+	return licenses, nil
+}
+
+func (r *veterinaryRepository) SaveLicense(ctx context.Context, license *model.VetLicense) error {
+	if err := r.db.WithContext(ctx).Save(license).Error; err != nil {
+		log.Print("Error saving veterinary license:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *veterinaryRepository) DeleteLicense(ctx context.Context, license *model.VetLicense) error {
+	if err := r.db.WithContext(ctx).Delete(&model.VetLicense{}, "id = ?", license.ID).Error; err != nil {
+		log.Print("Error deleting veterinary license:", err)
+		return err
+	}
+	return nil
+}
+
+// FindLicensesExpiringBy retorna as inscrições dos veterinários ativos que vencem
+// até a data (2006-01-02), inclusive as já vencidas, e as sem validade informada
+func (r *veterinaryRepository) FindLicensesExpiringBy(ctx context.Context, date string) ([]model.VetLicense, error) {
+	var licenses []model.VetLicense
+	if err := r.db.WithContext(ctx).
+		Where("expires_at IS NULL OR expires_at <= ?", date).
+		Where("crvm IN (?)", r.db.Model(&model.Veterinary{}).Select("crvm")).
+		Order("expires_at ASC NULLS FIRST, crvm ASC, uf ASC").Find(&licenses).Error; err != nil {
+		log.Print("Error querying expiring veterinary licenses:", err)
+		return nil, err
+	}
+	return licenses, nil
+}
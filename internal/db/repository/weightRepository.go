@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WeightRepository interface {
+	Create(ctx context.Context, record *model.WeightRecord, updateAnimalWeight bool) error
+	FindByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.WeightRecord, error)
+}
+
+type weightRepository struct {
+	db *gorm.DB
+}
+
+func NewWeightRepository(db *gorm.DB) WeightRepository {
+	return &weightRepository{db: db}
+}
+
+// Create salva a pesagem e, quando solicitado, atualiza o peso atual do animal na mesma transação
+func (r *weightRepository) Create(ctx context.Context, record *model.WeightRecord, updateAnimalWeight bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return saveWeight(tx, record, updateAnimalWeight)
+	})
+}
+
+// saveWeight é o caminho comum das pesagens e das mudanças de peso no cadastro do
+// animal, para que a curva de peso, o peso atual e o histórico do animal não
+// divirjam. Grava a pesagem na transação tx e, com updateAnimalWeight, atualiza o
+// peso atual do animal e registra a alteração no histórico. Quando o animal já tem
+// esse peso (atualizações do cadastro, que gravam o próprio histórico), só a
+// pesagem é gravada.
+func saveWeight(tx *gorm.DB, record *model.WeightRecord, updateAnimalWeight bool) error {
+	if err := tx.Create(record).Error; err != nil {
+		log.Print("Error saving weight record:", err)
+		return err
+	}
+	log.Print("Repository Saving Weight Record")
+	if !updateAnimalWeight {
+		return nil
+	}
+
+	var animal model.Animal
+	if err := tx.Select("id", "weight").Where("id = ?", record.AnimalID).First(&animal).Error; err != nil {
+		log.Print("Error finding animal to update weight:", err)
+		return err
+	}
+	weight := record.WeightInKg()
+	if animal.Weight == weight {
+		return nil
+	}
+	if err := tx.Model(&model.Animal{}).Where("id = ?", record.AnimalID).Update("weight", weight).Error; err != nil {
+		log.Print("Error updating animal weight:", err)
+		return err
+	}
+	history := &model.AnimalHistory{
+		ID:        uuid.New(),
+		AnimalID:  record.AnimalID,
+		ChangedBy: record.RecordedBy,
+		Changes:   []model.Change{{Field: "weight", OldValue: fmt.Sprint(animal.Weight), NewValue: fmt.Sprint(weight)}},
+		Timestamp: time.Now(),
+	}
+	if err := tx.Create(history).Error; err != nil {
+		log.Print("Error saving animal history:", err)
+		return err
+	}
+	log.Print("Repository Updating Animal Weight")
+	return nil
+}
+
+// FindByAnimalID retorna as pesagens do animal em ordem cronológica
+func (r *weightRepository) FindByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.WeightRecord, error) {
+	var records []model.WeightRecord
+	err := r.db.WithContext(ctx).Where("animal_id = ?", animalID).Order("date asc, created_at asc").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"log"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Protocolos vacinais iniciais, seguindo as recomendações usuais para cães e gatos no Brasil
+var vaccinationProtocolSeed = []model.VaccinationProtocol{
+	{Code: "dog_v10", Name: "V10 (polivalente)", Species: "Dog", Doses: 3, DoseIntervalDays: 21, BoosterIntervalDays: 365,
+		Description: "Cinomose, parvovirose, coronavirose, hepatite, adenovirose, parainfluenza e leptospirose"},
+	{Code: "dog_v8", Name: "V8 (polivalente)", Species: "Dog", Doses: 3, DoseIntervalDays: 21, BoosterIntervalDays: 365},
+	{Code: "dog_rabies", Name: "Antirrábica", Species: "Dog", Doses: 1, BoosterIntervalDays: 365},
+	{Code: "dog_kennel_cough", Name: "Gripe canina (tosse dos canis)", Species: "Dog", Doses: 2, DoseIntervalDays: 21, BoosterIntervalDays: 365},
+	{Code: "dog_giardia", Name: "Giárdia", Species: "Dog", Doses: 2, DoseIntervalDays: 21, BoosterIntervalDays: 365},
+	{Code: "dog_leishmaniasis", Name: "Leishmaniose", Species: "Dog", Doses: 3, DoseIntervalDays: 21, BoosterIntervalDays: 365},
+	{Code: "cat_v4", Name: "V4 (quádrupla felina)", Species: "Cat", Doses: 2, DoseIntervalDays: 21, BoosterIntervalDays: 365,
+		Description: "Rinotraqueíte, calicivirose, panleucopenia e clamidiose"},
+	{Code: "cat_v5", Name: "V5 (quíntupla felina)", Species: "Cat", Doses: 2, DoseIntervalDays: 21, BoosterIntervalDays: 365,
+		Description: "V4 mais leucemia felina (FeLV)"},
+	{Code: "cat_rabies", Name: "Antirrábica", Species: "Cat", Doses: 1, BoosterIntervalDays: 365},
+}
+
+// seedVaccinationProtocols carrega os protocolos iniciais que ainda não existem,
+// procurando pelo código inclusive entre os excluídos
+func seedVaccinationProtocols(db *gorm.DB) error {
+	for _, seed := range vaccinationProtocolSeed {
+		var count int64
+		if err := db.Unscoped().Model(&model.VaccinationProtocol{}).Where("code = ?", seed.Code).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		protocol := seed
+		protocol.ID = uuid.New()
+		if err := db.Create(&protocol).Error; err != nil {
+			return err
+		}
+		log.Printf("Seeded vaccination protocol %s", protocol.Code)
+	}
+	return nil
+}
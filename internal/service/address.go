@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+)
+
+var (
+	ErrInvalidCEP        = errors.New("CEP inválido: informe os 8 dígitos")
+	ErrInvalidUF         = errors.New("UF inválida")
+	ErrIncompleteAddress = errors.New("endereço incompleto: informe CEP, logradouro, bairro, cidade e UF")
+	ErrCEPMismatch       = errors.New("a UF informada não corresponde ao CEP")
+	ErrCEPNotFound       = errors.New("CEP não encontrado na tabela local")
+)
+
+// Situação de cada tutor na migração dos endereços em texto livre
+const (
+	AddressMigrated    = "migrated"
+	AddressNeedsReview = "needs_review"
+)
+
+// AddressMigrationResult é o resultado da migração do endereço de um tutor.
+// Address traz os campos extraídos do texto, completos ou não.
+type AddressMigrationResult struct {
+	CPFTutor    string        `json:"cpf_tutor"`
+	Status      string        `json:"status"`
+	AddressText string        `json:"address_text"`
+	Address     model.Address `json:"address"`
+	Message     string        `json:"message,omitempty"`
+}
+
+// AddressMigrationReport resume a migração dos endereços em texto livre
+type AddressMigrationReport struct {
+	DryRun      bool                     `json:"dry_run"`
+	Total       int                      `json:"total"`
+	Migrated    int                      `json:"migrated"`
+	NeedsReview int                      `json:"needs_review"`
+	Tutors      []AddressMigrationResult `json:"tutors"`
+}
+
+// Colunas reconhecidas no arquivo da tabela de CEPs e os nomes aceitos para cada uma
+var cepColumnAliases = map[string][]string{
+	"cep":          {"cep"},
+	"street":       {"street", "logradouro", "endereco"},
+	"neighborhood": {"neighborhood", "bairro"},
+	"city":         {"city", "cidade", "localidade", "municipio"},
+	"uf":           {"uf", "estado"},
+}
+
+// CEPDirectory é uma tabela local de CEPs, carregada de um arquivo, usada para
+// completar e conferir endereços sem depender de um serviço externo. Um
+// CEPDirectory nulo é uma tabela vazia.
+type CEPDirectory struct {
+	entries map[string]model.Address
+}
+
+// LoadCEPDirectory lê a tabela de CEPs de um CSV com cabeçalho, separado por
+// vírgula ou ponto e vírgula, com as colunas cep, logradouro, bairro, cidade e
+// uf. Logradouro e bairro podem ficar vazios nos CEPs gerais de município.
+func LoadCEPDirectory(r io.Reader) (*CEPDirectory, error) {
+	reader, err := newImportReader(r)
+	if err != nil {
+		return nil, err
+	}
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrEmptyImport
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler cabeçalho: %w", err)
+	}
+
+	aliases := make(map[string]string)
+	for field, names := range cepColumnAliases {
+		for _, name := range names {
+			aliases[model.NormalizeName(name)] = field
+		}
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		if field, ok := aliases[model.NormalizeName(name)]; ok {
+			if _, taken := columns[field]; !taken {
+				columns[field] = i
+			}
+		}
+	}
+	for _, field := range []string{"cep", "city", "uf"} {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingImportColumn, field)
+		}
+	}
+
+	directory := &CEPDirectory{entries: make(map[string]model.Address)}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line, err)
+		}
+		fields := importRecord(columns, record)
+		address := model.Address{
+			CEP:          fields["cep"],
+			Street:       fields["street"],
+			Neighborhood: fields["neighborhood"],
+			City:         fields["city"],
+			UF:           fields["uf"],
+		}.Normalize()
+		if !model.IsValidCEP(address.CEP) {
+			return nil, fmt.Errorf("linha %d: %w", line, ErrInvalidCEP)
+		}
+		if !model.IsValidUF(address.UF) {
+			return nil, fmt.Errorf("linha %d: %w", line, ErrInvalidUF)
+		}
+		directory.entries[address.CEP] = address
+	}
+	return directory, nil
+}
+
+// LoadCEPDirectoryFile carrega a tabela de CEPs de um arquivo
+func LoadCEPDirectoryFile(path string) (*CEPDirectory, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadCEPDirectory(file)
+}
+
+// CEPDirectoryFromEnv carrega a tabela de CEPs do arquivo indicado em
+// CEP_TABLE_PATH. Sem a variável, ou se o arquivo não puder ser lido, retorna
+// uma tabela vazia e os endereços são validados apenas pelo formato.
+func CEPDirectoryFromEnv() *CEPDirectory {
+	path := os.Getenv("CEP_TABLE_PATH")
+	if path == "" {
+		return nil
+	}
+	directory, err := LoadCEPDirectoryFile(path)
+	if err != nil {
+		log.Printf("Failed to load CEP table %q: %v", path, err)
+		return nil
+	}
+	log.Printf("Loaded %d CEPs from %s", directory.Len(), path)
+	return directory
+}
+
+// Len retorna o número de CEPs da tabela
+func (d *CEPDirectory) Len() int {
+	if d == nil {
+		return 0
+	}
+	return len(d.entries)
+}
+
+// Lookup busca o CEP, com ou sem hífen, na tabela
+func (d *CEPDirectory) Lookup(cep string) (model.Address, bool) {
+	if d == nil {
+		return model.Address{}, false
+	}
+	address, ok := d.entries[model.CleanCEP(cep)]
+	return address, ok
+}
+
+// prepareAddress normaliza e valida um endereço estruturado. Quando o CEP está na
+// tabela, os campos em branco são preenchidos com os dados dela e a UF é conferida.
+func prepareAddress(ceps *CEPDirectory, address model.Address) (model.Address, error) {
+	address = address.Normalize()
+	if address.CEP != "" && !model.IsValidCEP(address.CEP) {
+		return address, ErrInvalidCEP
+	}
+	if address.UF != "" && !model.IsValidUF(address.UF) {
+		return address, ErrInvalidUF
+	}
+
+	if known, ok := ceps.Lookup(address.CEP); ok {
+		if address.UF != "" && address.UF != known.UF {
+			return address, ErrCEPMismatch
+		}
+		fill := func(field *string, value string) {
+			if *field == "" {
+				*field = value
+			}
+		}
+		fill(&address.Street, known.Street)
+		fill(&address.Neighborhood, known.Neighborhood)
+		fill(&address.City, known.City)
+		fill(&address.UF, known.UF)
+	}
+
+	if !address.IsComplete() {
+		return address, ErrIncompleteAddress
+	}
+	return address, nil
+}
+
+var (
+	legacyCEPPattern    = regexp.MustCompile(`(?i)(?:CEP:?\s*)?\b(\d{2})\.?(\d{3})-?(\d{3})\b`)
+	legacyNumberPattern = regexp.MustCompile(`(?i),\s*(?:n[º°o]?\.?\s*)?(\d+[A-Z]?|S/?N)\b`)
+	legacyUFPattern     = regexp.MustCompile(`(?i)[\s,/-]([A-Z]{2})[\s.,-]*$`)
+)
+
+// parseLegacyAddress extrai o que for possível de um endereço em texto livre: o
+// CEP, o número (após a primeira vírgula), a UF (ao final) e o logradouro (antes
+// da primeira vírgula). Com o CEP na tabela, os demais campos vêm dela.
+func parseLegacyAddress(ceps *CEPDirectory, text string) model.Address {
+	var address model.Address
+	if match := legacyCEPPattern.FindStringSubmatchIndex(text); match != nil {
+		address.CEP = text[match[2]:match[3]] + text[match[4]:match[5]] + text[match[6]:match[7]]
+		text = text[:match[0]] + text[match[1]:]
+	}
+	text = strings.Trim(strings.TrimSpace(text), ",.-")
+	if match := legacyNumberPattern.FindStringSubmatch(text); match != nil {
+		address.Number = strings.ToUpper(match[1])
+	}
+	if match := legacyUFPattern.FindStringSubmatch(text); match != nil && model.IsValidUF(strings.ToUpper(match[1])) {
+		address.UF = strings.ToUpper(match[1])
+	}
+	if street, _, found := strings.Cut(text, ","); found {
+		address.Street = street
+	}
+
+	if known, ok := ceps.Lookup(address.CEP); ok {
+		address.UF = known.UF
+		address.City = known.City
+		if known.Neighborhood != "" {
+			address.Neighborhood = known.Neighborhood
+		}
+		if known.Street != "" {
+			address.Street = known.Street
+		}
+	}
+	return address.Normalize()
+}
+
+// LookupCEP busca o endereço do CEP na tabela local
+func (s *TutorService) LookupCEP(cep string) (model.Address, error) {
+	if !model.IsValidCEP(cep) {
+		return model.Address{}, ErrInvalidCEP
+	}
+	address, ok := s.ceps.Lookup(cep)
+	if !ok {
+		return model.Address{}, ErrCEPNotFound
+	}
+	return address, nil
+}
+
+// TutorRegions conta os tutores ativos por UF, cidade e bairro, para planejar
+// visitas domiciliares e acompanhar a distribuição dos casos
+func (s *TutorService) TutorRegions(ctx context.Context, uf, city string) ([]repository.TutorRegionCount, error) {
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+	if uf != "" && !model.IsValidUF(uf) {
+		return nil, ErrInvalidUF
+	}
+	counts, err := s.repo.CountTutorsByRegion(ctx, uf, strings.TrimSpace(city))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar tutores por região: %w", err)
+	}
+	if counts == nil {
+		counts = []repository.TutorRegionCount{}
+	}
+	return counts, nil
+}
+
+// MigrateLegacyAddresses converte os endereços em texto livre dos cadastros
+// antigos em endereços estruturados. Só são gravados os endereços que ficam
+// completos; os demais são listados para revisão manual. O texto original é
+// mantido até que o endereço seja alterado. Em dryRun nada é gravado.
+func (s *TutorService) MigrateLegacyAddresses(ctx context.Context, dryRun bool) (*AddressMigrationReport, error) {
+	tutors, err := s.repo.FindTutorsWithLegacyAddress(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar tutores: %w", err)
+	}
+
+	report := &AddressMigrationReport{DryRun: dryRun, Tutors: []AddressMigrationResult{}}
+	for i := range tutors {
+		tutor := &tutors[i]
+		result := AddressMigrationResult{CPFTutor: tutor.CPFTutor, AddressText: tutor.AddressText}
+		address, err := prepareAddress(s.ceps, parseLegacyAddress(s.ceps, tutor.AddressText))
+		result.Address = address
+		if err == nil && !dryRun {
+			tutor.Address = address
+			err = s.repo.UpdateTutor(ctx, tutor)
+		}
+
+		report.Total++
+		if err != nil {
+			result.Status, result.Message = AddressNeedsReview, err.Error()
+			report.NeedsReview++
+		} else {
+			result.Status = AddressMigrated
+			report.Migrated++
+		}
+		report.Tutors = append(report.Tutors, result)
+	}
+
+	log.Printf("Legacy address migration (dry run: %t): %d tutors, %d migrated, %d need review",
+		dryRun, report.Total, report.Migrated, report.NeedsReview)
+	return report, nil
+}
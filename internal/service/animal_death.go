@@ -0,0 +1,83 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAnimalDeceased        = errors.New("animal is deceased")
+	ErrAnimalAlreadyDeceased = errors.New("animal death already recorded")
+	ErrFutureDeathDate       = errors.New("date of death cannot be in the future")
+	ErrDeathBeforeBirth      = errors.New("date of death is before the birth date")
+	ErrUnknownVeterinarian   = errors.New("veterinarian not found")
+)
+
+// RecordDeath marks an animal as deceased. The death record keeps the date,
+// cause, euthanasia flag, the CRVM of the veterinarian who certified or
+// authorized it and the body disposition. When the record names the tutor who
+// authorized a euthanasia, that tutor must be the primary owner. Consultations
+// still scheduled from today on are cancelled; the number of cancelled
+// consultations is returned.
+func (s *AnimalService) RecordDeath(id uuid.UUID, record model.DeathRecord, recordedBy string, getVetFunc func(string) (*model.Veterinary, error), now time.Time) (*model.DeathRecord, int64, error) {
+	animal, err := s.repo.FindAnimalByID(id)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error finding animal: %w", err)
+	}
+	if animal.IsDeceased() {
+		return nil, 0, ErrAnimalAlreadyDeceased
+	}
+
+	record.ID = uuid.New()
+	record.AnimalID = id
+	record.RecordedBy = recordedBy
+	if err := animalValidator.Struct(record); err != nil {
+		return nil, 0, fmt.Errorf("invalid death record: %w", err)
+	}
+	if record.DateOfDeath.After(now) {
+		return nil, 0, ErrFutureDeathDate
+	}
+	if animal.BirthDate != nil && record.DateOfDeath.Before(animal.BirthDate.Time) {
+		return nil, 0, ErrDeathBeforeBirth
+	}
+	if vet, err := getVetFunc(record.CRVM); err != nil || vet == nil {
+		return nil, 0, ErrUnknownVeterinarian
+	}
+	// A euthanasia is always authorized by the primary owner, who is recorded
+	// when authorized_by is not given
+	if record.Euthanasia && record.AuthorizedBy == "" {
+		record.AuthorizedBy = animal.CPFTutor
+	}
+	if record.AuthorizedBy != "" {
+		if err := AuthorizeProcedure(animal, record.AuthorizedBy); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	history := &model.AnimalHistory{
+		ID:        uuid.New(),
+		AnimalID:  id,
+		ChangedBy: recordedBy,
+		Changes:   []model.Change{{Field: "deceased_at", OldValue: "", NewValue: record.DateOfDeath.String()}},
+		Timestamp: now,
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	cancelled, err := s.repo.RecordDeath(animal, &record, history, today)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error recording death: %w", err)
+	}
+
+	log.Printf("Animal %s recorded as deceased on %s; %d consultations cancelled", id, record.DateOfDeath, cancelled)
+	return &record, cancelled, nil
+}
+
+// GetDeathRecord returns the death record of a deceased animal.
+func (s *AnimalService) GetDeathRecord(id uuid.UUID) (*model.DeathRecord, error) {
+	return s.repo.FindDeathRecord(id)
+}
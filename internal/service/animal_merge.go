@@ -0,0 +1,206 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrMergeSameAnimal = errors.New("cannot merge an animal into itself")
+	ErrMergeMismatch   = errors.New("animals must have the same tutor and species to be merged")
+	ErrAnimalMerged    = errors.New("animal was merged into another one and cannot be restored")
+)
+
+// DuplicateNameSimilarity is the minimum name similarity (0 to 1) for two animals
+// of the same tutor and species to be reported as probable duplicates.
+const DuplicateNameSimilarity = 0.75
+
+// DuplicateCandidate is an animal that is probably the same patient as another one.
+type DuplicateCandidate struct {
+	Animal     model.Animal `json:"animal"`
+	Similarity float64      `json:"similarity"`
+}
+
+// DuplicatePair groups two active animals that are probably the same patient.
+type DuplicatePair struct {
+	First      model.Animal `json:"first"`
+	Second     model.Animal `json:"second"`
+	Similarity float64      `json:"similarity"`
+}
+
+// FindDuplicateCandidates lists the animals of the same tutor and species whose
+// names are similar to the given animal's, most similar first.
+func (s *AnimalService) FindDuplicateCandidates(id uuid.UUID) ([]DuplicateCandidate, error) {
+	animal, err := s.repo.FindAnimalByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("error finding animal: %w", err)
+	}
+	siblings, err := s.repo.FindAnimalsByTutorAndSpecies(animal.CPFTutor, animal.Species)
+	if err != nil {
+		return nil, fmt.Errorf("error finding duplicate candidates: %w", err)
+	}
+
+	candidates := []DuplicateCandidate{}
+	for _, sibling := range siblings {
+		if sibling.ID == animal.ID {
+			continue
+		}
+		if similarity := NameSimilarity(animal.Name, sibling.Name); similarity >= DuplicateNameSimilarity {
+			candidates = append(candidates, DuplicateCandidate{Animal: sibling, Similarity: similarity})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Similarity > candidates[j].Similarity
+	})
+	return candidates, nil
+}
+
+// ListDuplicateCandidates scans every active animal for probable duplicates.
+func (s *AnimalService) ListDuplicateCandidates() ([]DuplicatePair, error) {
+	animals, err := s.repo.FindAllAnimals()
+	if err != nil {
+		return nil, fmt.Errorf("error finding animals: %w", err)
+	}
+	return FindDuplicatePairs(animals), nil
+}
+
+// FindDuplicatePairs compares the names of the animals sharing tutor and species
+// and returns the pairs above DuplicateNameSimilarity, most similar first.
+func FindDuplicatePairs(animals []model.Animal) []DuplicatePair {
+	groups := make(map[string][]model.Animal)
+	var keys []string
+	for _, animal := range animals {
+		key := animal.CPFTutor + "|" + model.NormalizeName(animal.Species)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], animal)
+	}
+
+	pairs := []DuplicatePair{}
+	for _, key := range keys {
+		group := groups[key]
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				if similarity := NameSimilarity(group[i].Name, group[j].Name); similarity >= DuplicateNameSimilarity {
+					pairs = append(pairs, DuplicatePair{First: group[i], Second: group[j], Similarity: similarity})
+				}
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Similarity > pairs[j].Similarity
+	})
+	return pairs
+}
+
+// NameSimilarity compares two names ignoring case, accents and extra spaces.
+// It returns 1 for identical names and 0 for completely different ones, based
+// on the Levenshtein distance relative to the longer name.
+func NameSimilarity(a, b string) float64 {
+	ra, rb := []rune(model.NormalizeName(a)), []rune(model.NormalizeName(b))
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return roundTo(1-float64(levenshtein(ra, rb))/float64(longest), 2)
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// MergeAnimals merges the duplicate source animal into the target one. The
+// source's consultations, dosages, hospitalizations, weight records, allergies,
+// chronic conditions, vaccinations and image move to the target, fields the target is missing are copied from the source,
+// and the source is deleted. Both animals must share tutor and species.
+func (s *AnimalService) MergeAnimals(targetID, sourceID uuid.UUID, mergedBy string) (*model.AnimalMerge, error) {
+	if targetID == sourceID {
+		return nil, ErrMergeSameAnimal
+	}
+	target, err := s.repo.FindAnimalByID(targetID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding target animal: %w", err)
+	}
+	source, err := s.repo.FindAnimalByID(sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding source animal: %w", err)
+	}
+	if target.CPFTutor != source.CPFTutor || model.NormalizeName(target.Species) != model.NormalizeName(source.Species) {
+		return nil, ErrMergeMismatch
+	}
+
+	before := *target
+	fillMissingFields(target, source)
+
+	now := time.Now()
+	changes := append(diffAnimal(before, *target), model.Change{Field: "merged_from", NewValue: source.ID.String()})
+	history := &model.AnimalHistory{
+		ID:        uuid.New(),
+		AnimalID:  target.ID,
+		ChangedBy: mergedBy,
+		Changes:   changes,
+		Timestamp: now,
+	}
+	merge := &model.AnimalMerge{
+		ID:             uuid.New(),
+		SourceAnimalID: source.ID,
+		TargetAnimalID: target.ID,
+		MergedBy:       mergedBy,
+		Timestamp:      now,
+	}
+
+	if err := s.repo.MergeAnimals(source, target, merge, history); err != nil {
+		return nil, fmt.Errorf("error merging animals: %w", err)
+	}
+	return merge, nil
+}
+
+// fillMissingFields copies into target the data only the source has.
+func fillMissingFields(target, source *model.Animal) {
+	if target.Breed == "" {
+		target.Breed = source.Breed
+	}
+	if target.Description == "" {
+		target.Description = source.Description
+	}
+	if target.BirthDate == nil && source.BirthDate != nil {
+		target.BirthDate = source.BirthDate
+		target.BirthDatePrecision = source.BirthDatePrecision
+	}
+	if target.Microchip == nil && source.Microchip != nil {
+		target.Microchip = source.Microchip
+	}
+	if target.Weight == 0 {
+		target.Weight = source.Weight
+	}
+}
+
+// GetAnimalMerges lists the merges the animal took part in, as source or target.
+func (s *AnimalService) GetAnimalMerges(id uuid.UUID) ([]model.AnimalMerge, error) {
+	return s.repo.FindAnimalMerges(id)
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+)
+
+// DefaultAnimalTrashRetention is how long a deleted animal stays in the trash
+// when ANIMAL_TRASH_RETENTION_DAYS is not set.
+const DefaultAnimalTrashRetention = 30 * 24 * time.Hour
+
+// AnimalTrashRetention reads the retention period, in days, from the
+// ANIMAL_TRASH_RETENTION_DAYS environment variable.
+func AnimalTrashRetention() time.Duration {
+	value := os.Getenv("ANIMAL_TRASH_RETENTION_DAYS")
+	if value == "" {
+		return DefaultAnimalTrashRetention
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 1 {
+		log.Printf("Invalid ANIMAL_TRASH_RETENTION_DAYS %q, using default", value)
+		return DefaultAnimalTrashRetention
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// ListDeletedAnimals returns the animals currently in the trash.
+func (s *AnimalService) ListDeletedAnimals() ([]model.Animal, error) {
+	return s.repo.FindDeletedAnimals()
+}
+
+// RestoreAnimal moves an animal out of the trash. It fails if the animal was
+// merged into another one, or if another active animal took the same microchip
+// or identical registration in the meantime.
+func (s *AnimalService) RestoreAnimal(id uuid.UUID, restoredBy string) (*model.Animal, error) {
+	animal, err := s.repo.FindDeletedAnimalByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("error finding deleted animal: %w", err)
+	}
+
+	merges, err := s.repo.FindAnimalMerges(id)
+	if err != nil {
+		return nil, fmt.Errorf("error finding animal merges: %w", err)
+	}
+	for _, merge := range merges {
+		if merge.SourceAnimalID == id {
+			return nil, ErrAnimalMerged
+		}
+	}
+
+	if err := s.checkMicrochip(animal); err != nil {
+		return nil, err
+	}
+	if err := s.ValidateAnimalExists(*animal); err != nil {
+		return nil, err
+	}
+
+	entry := model.NewAuditLog(model.AuditActionRestore, "animal", animal.ID, restoredBy,
+		fmt.Sprintf("deleted at %s", animal.DeletedAt.Time.UTC().Format(time.RFC3339)))
+	if err := s.repo.RestoreAnimal(animal, entry); err != nil {
+		return nil, fmt.Errorf("error restoring animal: %w", err)
+	}
+	return animal, nil
+}
+
+// PurgeAnimalTrash permanently deletes the animals that have been in the trash
+// for longer than the retention period, along with their dependent records.
+func (s *AnimalService) PurgeAnimalTrash(retention time.Duration, now time.Time, purgedBy string) ([]uuid.UUID, error) {
+	if retention <= 0 {
+		return nil, fmt.Errorf("invalid retention period: %s", retention)
+	}
+	ids, err := s.repo.PurgeDeletedAnimals(now.Add(-retention), purgedBy)
+	if err != nil {
+		return nil, fmt.Errorf("error purging animal trash: %w", err)
+	}
+	return ids, nil
+}
+
+// RunAnimalTrashPurge purges the trash once per interval until ctx is done.
+func (s *AnimalService) RunAnimalTrashPurge(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ids, err := s.PurgeAnimalTrash(retention, time.Now(), "system")
+		if err != nil {
+			log.Print(err)
+		} else if len(ids) > 0 {
+			log.Printf("Purged %d animals from the trash", len(ids))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
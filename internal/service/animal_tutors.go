@@ -0,0 +1,168 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidTutorRole    = errors.New("invalid tutor role")
+	ErrTutorIsPrimaryOwner = errors.New("tutor is the primary owner of this animal")
+	ErrAnimalTutorNotFound = errors.New("tutor is not linked to this animal")
+	ErrNotPrimaryOwner     = errors.New("only the primary owner can authorize procedures")
+)
+
+// TutorRoles lists every role a tutor can have towards an animal.
+var TutorRoles = []string{model.TutorRolePrimaryOwner, model.TutorRoleCoOwner, model.TutorRoleEmergencyContact, model.TutorRoleAuthorizedPickup}
+
+// AnimalTutorLink is one of the animal's tutors and their role. The primary
+// owner comes from Animal.CPFTutor and has no link record.
+type AnimalTutorLink struct {
+	CPFTutor string     `json:"cpf_tutor"`
+	Role     string     `json:"role"`
+	AddedBy  string     `json:"added_by,omitempty"`
+	AddedAt  *time.Time `json:"added_at,omitempty"`
+}
+
+// ListAnimalTutors returns the primary owner followed by the other tutors of the animal.
+func (s *AnimalService) ListAnimalTutors(id uuid.UUID) ([]AnimalTutorLink, error) {
+	animal, err := s.repo.FindAnimalByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("error finding animal: %w", err)
+	}
+	links, err := s.repo.FindAnimalTutors(id)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving animal tutors: %w", err)
+	}
+
+	tutors := []AnimalTutorLink{{CPFTutor: animal.CPFTutor, Role: model.TutorRolePrimaryOwner}}
+	for _, link := range links {
+		addedAt := link.CreatedAt
+		tutors = append(tutors, AnimalTutorLink{CPFTutor: link.CPFTutor, Role: link.Role, AddedBy: link.AddedBy, AddedAt: &addedAt})
+	}
+	return tutors, nil
+}
+
+// SetAnimalTutor links a tutor to the animal with a role other than primary
+// owner, or changes the role of a tutor already linked. The primary owner is
+// changed only through an ownership transfer.
+func (s *AnimalService) SetAnimalTutor(id uuid.UUID, cpf, role, changedBy string) (*model.AnimalTutor, error) {
+	if role == model.TutorRolePrimaryOwner {
+		return nil, fmt.Errorf("%w: the primary owner is changed by transferring the animal", ErrInvalidTutorRole)
+	}
+	if !isTutorRole(role) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTutorRole, role)
+	}
+	if !model.IsValidDocument(cpf) {
+		return nil, ErrInvalidCPF
+	}
+
+	animal, err := s.repo.FindAnimalByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("error finding animal: %w", err)
+	}
+	if animal.CPFTutor == cpf {
+		return nil, ErrTutorIsPrimaryOwner
+	}
+	if err := s.checkTutor(cpf); err != nil {
+		return nil, err
+	}
+
+	links, err := s.repo.FindAnimalTutors(id)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving animal tutors: %w", err)
+	}
+	link := findAnimalTutor(links, cpf)
+	oldValue := ""
+	if link == nil {
+		link = &model.AnimalTutor{ID: uuid.New(), AnimalID: id, CPFTutor: cpf, AddedBy: changedBy}
+	} else if link.Role == role {
+		return link, nil
+	} else {
+		oldValue = link.CPFTutor + ":" + link.Role
+	}
+	link.Role = role
+	if err := animalValidator.Struct(link); err != nil {
+		return nil, fmt.Errorf("invalid animal tutor: %w", err)
+	}
+
+	history := &model.AnimalHistory{
+		ID:        uuid.New(),
+		AnimalID:  id,
+		ChangedBy: changedBy,
+		Changes:   []model.Change{{Field: "tutors", OldValue: oldValue, NewValue: cpf + ":" + role}},
+		Timestamp: time.Now(),
+	}
+	if err := s.repo.SaveAnimalTutor(link, history); err != nil {
+		return nil, fmt.Errorf("error saving animal tutor: %w", err)
+	}
+
+	log.Printf("Tutor %s linked to animal %s as %s", cpf, id, role)
+	return link, nil
+}
+
+// RemoveAnimalTutor unlinks a tutor that is not the primary owner.
+func (s *AnimalService) RemoveAnimalTutor(id uuid.UUID, cpf, changedBy string) error {
+	animal, err := s.repo.FindAnimalByID(id)
+	if err != nil {
+		return fmt.Errorf("error finding animal: %w", err)
+	}
+	if animal.CPFTutor == cpf {
+		return ErrTutorIsPrimaryOwner
+	}
+
+	links, err := s.repo.FindAnimalTutors(id)
+	if err != nil {
+		return fmt.Errorf("error retrieving animal tutors: %w", err)
+	}
+	link := findAnimalTutor(links, cpf)
+	if link == nil {
+		return ErrAnimalTutorNotFound
+	}
+
+	history := &model.AnimalHistory{
+		ID:        uuid.New(),
+		AnimalID:  id,
+		ChangedBy: changedBy,
+		Changes:   []model.Change{{Field: "tutors", OldValue: link.CPFTutor + ":" + link.Role, NewValue: ""}},
+		Timestamp: time.Now(),
+	}
+	if err := s.repo.DeleteAnimalTutor(link, history); err != nil {
+		return fmt.Errorf("error removing animal tutor: %w", err)
+	}
+
+	log.Printf("Tutor %s unlinked from animal %s", cpf, id)
+	return nil
+}
+
+// AuthorizeProcedure checks that the tutor authorizing a procedure on the
+// animal is its primary owner; co-owners and other roles cannot authorize.
+func AuthorizeProcedure(animal *model.Animal, cpf string) error {
+	if animal.CPFTutor != cpf {
+		return ErrNotPrimaryOwner
+	}
+	return nil
+}
+
+func isTutorRole(role string) bool {
+	for _, candidate := range TutorRoles {
+		if role == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+func findAnimalTutor(links []model.AnimalTutor, cpf string) *model.AnimalTutor {
+	for i := range links {
+		if links[i].CPFTutor == cpf {
+			return &links[i]
+		}
+	}
+	return nil
+}
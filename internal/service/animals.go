@@ -1,25 +1,71 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"time"
 	"vetblock/internal/db/model"
 	"vetblock/internal/db/repository"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type AnimalService struct {
-	repo repository.AnimalRepositoryInterface
+	repo    repository.AnimalRepositoryInterface
+	catalog *CatalogService
+	tutors  *TutorService
+}
+
+// AnimalServiceOption configures an optional dependency of AnimalService.
+type AnimalServiceOption func(*AnimalService)
+
+// WithCatalog makes the service check species and breeds against the reference
+// catalog and store the catalog's names. Without it they are free text.
+func WithCatalog(catalog *CatalogService) AnimalServiceOption {
+	return func(s *AnimalService) {
+		s.catalog = catalog
+	}
+}
+
+// WithTutors makes the service require that an animal's tutor is registered and
+// active when the animal is created or moved to another tutor.
+func WithTutors(tutors *TutorService) AnimalServiceOption {
+	return func(s *AnimalService) {
+		s.tutors = tutors
+	}
 }
 
 // NewAnimalService creates a new instance of AnimalService with a repository
-func NewAnimalService(repo repository.AnimalRepositoryInterface) *AnimalService {
+func NewAnimalService(repo repository.AnimalRepositoryInterface, opts ...AnimalServiceOption) *AnimalService {
 	if repo == nil {
 		log.Fatal("AnimalService requires a non-nil repository")
 	}
-	return &AnimalService{repo: repo}
+	s := &AnimalService{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// applyCatalog maps the animal's species and breed to catalog entries, when a
+// catalog is configured.
+func (s *AnimalService) applyCatalog(animal *model.Animal) error {
+	if s.catalog == nil {
+		return nil
+	}
+	return s.catalog.CanonicalizeAnimal(context.Background(), animal)
+}
+
+// checkTutor ensures the tutor exists and is active, when a tutor service is configured.
+func (s *AnimalService) checkTutor(cpf string) error {
+	if s.tutors == nil {
+		return nil
+	}
+	return s.tutors.CheckTutor(context.Background(), cpf)
 }
 
 // ValidateAnimal checks if the animal has valid data (e.g., a name and species).
@@ -33,6 +79,8 @@ func ValidateAnimal(animal model.Animal) error {
 	return nil
 }
 
+var ErrAnimalExists = errors.New("animal already exists")
+
 // ValidateAnimalExists checks if the animal already exists in the repository.
 func (s *AnimalService) ValidateAnimalExists(animal model.Animal) error {
 	existingAnimal, err := s.repo.FindByUniqueAttributes(animal)
@@ -40,7 +88,7 @@ func (s *AnimalService) ValidateAnimalExists(animal model.Animal) error {
 		return fmt.Errorf("error checking for existing animal: %v", err)
 	}
 	if existingAnimal != nil {
-		return errors.New("animal already exists")
+		return ErrAnimalExists
 	}
 	return nil
 }
@@ -48,14 +96,13 @@ func (s *AnimalService) ValidateAnimalExists(animal model.Animal) error {
 // AddAnimal adds a new animal to the repository after validation.
 func (s *AnimalService) AddAnimal(animal model.Animal) error {
 	log.Println("Starting add animal transaction")
-	
-	// Validate the animal's fields
-	if err := ValidateAnimal(animal); err != nil {
+
+	if err := s.PrepareAnimal(&animal); err != nil {
 		return err
 	}
 
-	// Validate if the animal already exists
-	if err := s.ValidateAnimalExists(animal); err != nil {
+	// Validate that the tutor is registered and active
+	if err := s.checkTutor(animal.CPFTutor); err != nil {
 		return err
 	}
 
@@ -68,6 +115,28 @@ func (s *AnimalService) AddAnimal(animal model.Animal) error {
 	return nil
 }
 
+// PrepareAnimal runs every check AddAnimal makes before saving, normalizing
+// species, breed and microchip in place. It returns ErrAnimalExists for duplicates.
+func (s *AnimalService) PrepareAnimal(animal *model.Animal) error {
+	// Validate the animal's fields
+	if err := ValidateAnimal(*animal); err != nil {
+		return err
+	}
+
+	// Map the species and breed to the reference catalog
+	if err := s.applyCatalog(animal); err != nil {
+		return err
+	}
+
+	// Validate the microchip format and that no other animal uses it
+	if err := s.checkMicrochip(animal); err != nil {
+		return err
+	}
+
+	// Validate if the animal already exists
+	return s.ValidateAnimalExists(*animal)
+}
+
 // GetAnimalByID retrieves an animal by its ID from the repository.
 func (s *AnimalService) GetAnimalByID(id uuid.UUID) (*model.Animal, error) {
 	animal, err := s.repo.FindAnimalByID(id)
@@ -80,32 +149,270 @@ func (s *AnimalService) GetAnimalByID(id uuid.UUID) (*model.Animal, error) {
 	return animal, nil
 }
 
-// UpdateAnimal updates an existing animal's details in the repository.
-func (s *AnimalService) UpdateAnimal(id uuid.UUID, updatedAnimal model.Animal) error {
+// AnimalPatch holds the fields of a partial update; nil fields are left untouched.
+type AnimalPatch struct {
+	Name               *string  `json:"name"`
+	Species            *string  `json:"species"`
+	Breed              *string  `json:"breed"`
+	Age                *int     `json:"age"` // Accepted from older clients; becomes an estimated birth date
+	BirthDate          *string  `json:"birth_date"`
+	BirthDatePrecision *string  `json:"birth_date_precision"`
+	Weight             *float64 `json:"weight"`
+	Description        *string  `json:"description"`
+	CPFTutor           *string  `json:"cpf_tutor"` // Must match the current tutor; changes go through TransferOwnership
+	Microchip          *string  `json:"microchip"` // An empty string removes the microchip
+}
+
+var animalValidator = validator.New()
+
+// UpdateAnimal replaces all editable fields of an existing animal. The microchip
+// is only replaced when one is given; PATCH with an empty string removes it.
+func (s *AnimalService) UpdateAnimal(id uuid.UUID, updatedAnimal model.Animal, changedBy string) (*model.Animal, error) {
+	patch := AnimalPatch{
+		Name:        &updatedAnimal.Name,
+		Species:     &updatedAnimal.Species,
+		Breed:       &updatedAnimal.Breed,
+		Weight:      &updatedAnimal.Weight,
+		Description: &updatedAnimal.Description,
+		CPFTutor:    &updatedAnimal.CPFTutor,
+		Microchip:   updatedAnimal.Microchip,
+	}
+	if updatedAnimal.BirthDate != nil {
+		birthDate := updatedAnimal.BirthDate.Format("2006-01-02")
+		patch.BirthDate = &birthDate
+		patch.BirthDatePrecision = &updatedAnimal.BirthDatePrecision
+	} else {
+		patch.Age = &updatedAnimal.Age
+	}
+	return s.PatchAnimal(id, patch, changedBy)
+}
+
+// PatchAnimal applies the non-nil fields of patch to an existing animal,
+// validates the result and records every changed field in the animal history.
+func (s *AnimalService) PatchAnimal(id uuid.UUID, patch AnimalPatch, changedBy string) (*model.Animal, error) {
 	log.Println("Updating animal")
 
 	animal, err := s.repo.FindAnimalByID(id)
 	if err != nil {
-		return fmt.Errorf("error finding animal to update: %v", err)
+		return nil, fmt.Errorf("error finding animal to update: %w", err)
+	}
+	before := *animal
+
+	if patch.Name != nil {
+		animal.Name = *patch.Name
+	}
+	if patch.Species != nil {
+		animal.Species = *patch.Species
+	}
+	if patch.Breed != nil {
+		animal.Breed = *patch.Breed
+	}
+	if patch.Species != nil || patch.Breed != nil {
+		if err := s.applyCatalog(animal); err != nil {
+			return nil, err
+		}
+	}
+	// The legacy age only estimates a birth date the animal does not have yet; a
+	// client echoing the computed age must not replace a known birth date
+	if patch.BirthDate == nil && animal.BirthDate != nil {
+		patch.Age = nil
+	}
+	if patch.BirthDate != nil || patch.Age != nil {
+		precision := ""
+		if patch.BirthDatePrecision != nil {
+			precision = *patch.BirthDatePrecision
+		}
+		birthDateValue := ""
+		if patch.BirthDate != nil {
+			birthDateValue = *patch.BirthDate
+		}
+		birthDate, resolvedPrecision, err := ResolveBirthDate(birthDateValue, precision, patch.Age, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if birthDate != nil || patch.BirthDate != nil {
+			animal.BirthDate, animal.BirthDatePrecision = birthDate, resolvedPrecision
+		}
+		animal.RefreshAge(time.Now())
+	}
+	if patch.Weight != nil {
+		animal.Weight = *patch.Weight
+	}
+	if patch.Description != nil {
+		animal.Description = *patch.Description
+	}
+	// The primary tutor only changes through TransferOwnership, which records the
+	// transfer used to limit what each tutor sees of the animal's history
+	if patch.CPFTutor != nil && *patch.CPFTutor != animal.CPFTutor {
+		return nil, ErrTutorChangeNotAllowed
+	}
+	if patch.Microchip != nil {
+		microchip := *patch.Microchip
+		animal.Microchip = &microchip
+		if err := s.checkMicrochip(animal); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := animalValidator.Struct(animal); err != nil {
+		return nil, fmt.Errorf("invalid animal: %w", err)
 	}
 
-	// Update the animal's fields
-	animal.Name = updatedAnimal.Name
-	animal.Species = updatedAnimal.Species
-	animal.Breed = updatedAnimal.Breed
-	animal.Age = updatedAnimal.Age
-	animal.Description = updatedAnimal.Description
-	animal.CPFTutor = updatedAnimal.CPFTutor
+	changes := diffAnimal(before, *animal)
+	if len(changes) == 0 {
+		log.Println("No changes to update")
+		return animal, nil
+	}
+
+	history := &model.AnimalHistory{
+		ID:        uuid.New(),
+		AnimalID:  animal.ID,
+		ChangedBy: changedBy,
+		Changes:   changes,
+		Timestamp: time.Now(),
+	}
 
 	// Save the updated animal
-	if err := s.repo.SaveAnimal(animal); err != nil {
-		return fmt.Errorf("error updating animal: %v", err)
+	if err := s.repo.UpdateAnimal(animal, history); err != nil {
+		return nil, fmt.Errorf("error updating animal: %w", err)
 	}
 
 	log.Println("Animal updated successfully")
+	return animal, nil
+}
+
+// diffAnimal lists the editable fields whose values differ between before and after.
+func diffAnimal(before, after model.Animal) []model.Change {
+	var changes []model.Change
+	compare := func(field string, oldValue, newValue interface{}) {
+		oldStr, newStr := fmt.Sprint(oldValue), fmt.Sprint(newValue)
+		if oldStr != newStr {
+			changes = append(changes, model.Change{Field: field, OldValue: oldStr, NewValue: newStr})
+		}
+	}
+
+	compare("name", before.Name, after.Name)
+	compare("species", before.Species, after.Species)
+	compare("breed", before.Breed, after.Breed)
+	compare("birth_date", formatBirthDate(before.BirthDate), formatBirthDate(after.BirthDate))
+	compare("birth_date_precision", before.BirthDatePrecision, after.BirthDatePrecision)
+	compare("weight", before.Weight, after.Weight)
+	compare("description", before.Description, after.Description)
+	compare("cpf_tutor", before.CPFTutor, after.CPFTutor)
+	compare("microchip", formatOptional(before.Microchip), formatOptional(after.Microchip))
+	return changes
+}
+
+func formatOptional(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func formatBirthDate(date *model.CustomDate) string {
+	if date == nil {
+		return ""
+	}
+	return date.String()
+}
+
+// ResolveBirthDate converts the birth data sent by clients into a stored birth
+// date and its precision. birthDate accepts "2006-01-02", "2006-01" or "2006";
+// when it is empty, a legacy age in years produces an estimated birth date on
+// January 1 of the birth year, so the same age sent on different days of a year
+// gives the same date. An explicit precision overrides the one implied by the
+// format.
+func ResolveBirthDate(birthDate, precision string, age *int, now time.Time) (*model.CustomDate, string, error) {
+	if birthDate == "" {
+		if age == nil || *age <= 0 {
+			return nil, "", nil
+		}
+		estimated := model.CustomDate{Time: time.Date(now.Year()-*age, time.January, 1, 0, 0, 0, 0, time.UTC)}
+		return &estimated, model.BirthDatePrecisionEstimated, nil
+	}
+
+	layouts := []struct {
+		layout    string
+		precision string
+	}{
+		{"2006-01-02", model.BirthDatePrecisionExact},
+		{"2006-01", model.BirthDatePrecisionMonth},
+		{"2006", model.BirthDatePrecisionYear},
+	}
+	for _, l := range layouts {
+		parsed, err := time.Parse(l.layout, birthDate)
+		if err != nil {
+			continue
+		}
+		if parsed.After(now) {
+			return nil, "", errors.New("birth date cannot be in the future")
+		}
+		if precision == "" {
+			precision = l.precision
+		}
+		switch precision {
+		case model.BirthDatePrecisionExact, model.BirthDatePrecisionMonth, model.BirthDatePrecisionYear, model.BirthDatePrecisionEstimated:
+		default:
+			return nil, "", fmt.Errorf("invalid birth date precision: %s", precision)
+		}
+		return &model.CustomDate{Time: parsed}, precision, nil
+	}
+	return nil, "", fmt.Errorf("invalid birth date: %s", birthDate)
+}
+
+var ErrMicrochipInUse = errors.New("microchip already registered to another animal")
+
+// checkMicrochip normalizes the animal's microchip number, validates it as an
+// ISO 11784/11785 chip and makes sure no other active animal uses it.
+func (s *AnimalService) checkMicrochip(animal *model.Animal) error {
+	if animal.Microchip == nil {
+		return nil
+	}
+	if *animal.Microchip == "" {
+		animal.Microchip = nil
+		return nil
+	}
+
+	info, err := model.ParseMicrochip(*animal.Microchip)
+	if err != nil {
+		return err
+	}
+	animal.Microchip = &info.Number
+
+	existing, err := s.repo.FindAnimalByMicrochip(info.Number)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("error checking microchip: %w", err)
+	}
+	if existing != nil && existing.ID != animal.ID {
+		return ErrMicrochipInUse
+	}
 	return nil
 }
 
+// GetAnimalByMicrochip finds the active animal carrying the given microchip.
+func (s *AnimalService) GetAnimalByMicrochip(number string) (*model.Animal, error) {
+	info, err := model.ParseMicrochip(number)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindAnimalByMicrochip(info.Number)
+}
+
+// GetAnimalHistory returns the change history of an animal, newest first.
+func (s *AnimalService) GetAnimalHistory(id uuid.UUID) ([]model.AnimalHistory, error) {
+	if _, err := s.repo.FindAnimalByID(id); err != nil {
+		return nil, fmt.Errorf("error finding animal: %w", err)
+	}
+
+	history, err := s.repo.FindAnimalHistory(id)
+	if err != nil {
+		log.Printf("Error retrieving animal history: %v\n", err)
+		return nil, fmt.Errorf("error retrieving animal history: %w", err)
+	}
+	return history, nil
+}
+
 // DeleteAnimal removes an animal by its ID from the repository.
 func (s *AnimalService) DeleteAnimal(id uuid.UUID) (string, error) {
 	log.Printf("Deleting animal with ID: %s\n", id)
@@ -128,3 +435,40 @@ func (s *AnimalService) GetAllAnimals() ([]model.Animal, error) {
 	}
 	return animals, nil
 }
+
+const (
+	DefaultAnimalPageSize = 20
+	MaxAnimalPageSize     = 100
+)
+
+var ErrInvalidDateRange = errors.New("created_to must not be before created_from")
+
+// ListAnimals retrieves a filtered, sorted page of animals.
+func (s *AnimalService) ListAnimals(query repository.AnimalQuery) (*repository.AnimalPage, error) {
+	if query.Limit <= 0 {
+		query.Limit = DefaultAnimalPageSize
+	}
+	if query.Limit > MaxAnimalPageSize {
+		query.Limit = MaxAnimalPageSize
+	}
+	if query.TutorRole != "" && !isTutorRole(query.TutorRole) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTutorRole, query.TutorRole)
+	}
+	if query.CreatedFrom != nil && query.CreatedTo != nil && query.CreatedTo.Before(*query.CreatedFrom) {
+		return nil, ErrInvalidDateRange
+	}
+
+	// Let the species filter use any catalog name, e.g. "cão" for "Dog"
+	if s.catalog != nil && query.Species != "" {
+		if species, err := s.catalog.ResolveSpecies(context.Background(), query.Species); err == nil {
+			query.Species = species.NameEN
+		}
+	}
+
+	page, err := s.repo.QueryAnimals(query)
+	if err != nil {
+		log.Printf("Error listing animals: %v\n", err)
+		return nil, fmt.Errorf("error listing animals: %w", err)
+	}
+	return page, nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownSpecies  = errors.New("espécie não encontrada no catálogo")
+	ErrUnknownBreed    = errors.New("raça não encontrada no catálogo para a espécie")
+	ErrCatalogConflict = errors.New("já existe uma entrada no catálogo com esse código ou nome")
+)
+
+type CatalogService struct {
+	repo repository.CatalogRepository
+}
+
+// Cria uma nova instância do CatalogService com um repositório do catálogo de espécies
+func NewCatalogService(repo repository.CatalogRepository) *CatalogService {
+	return &CatalogService{repo: repo}
+}
+
+var catalogValidator = validator.New()
+
+// ListSpecies devolve o catálogo completo, com as raças de cada espécie
+func (s *CatalogService) ListSpecies(ctx context.Context) ([]model.Species, error) {
+	return s.repo.ListSpecies(ctx)
+}
+
+// ResolveSpecies encontra a espécie do catálogo que corresponde ao nome informado,
+// aceitando o código, os nomes em português e inglês e os apelidos cadastrados
+func (s *CatalogService) ResolveSpecies(ctx context.Context, name string) (*model.Species, error) {
+	catalog, err := s.repo.ListSpecies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar o catálogo: %w", err)
+	}
+	for i := range catalog {
+		if catalog[i].Matches(name) {
+			return &catalog[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownSpecies, name)
+}
+
+// ResolveBreed encontra, entre as raças da espécie, a que corresponde ao nome informado
+func ResolveBreed(species *model.Species, name string) (*model.Breed, error) {
+	for i := range species.Breeds {
+		if species.Breeds[i].Matches(name) {
+			return &species.Breeds[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q (%s)", ErrUnknownBreed, name, species.NameEN)
+}
+
+// CanonicalizeAnimal troca a espécie e a raça do animal pelos nomes em inglês do
+// catálogo, rejeitando valores que não correspondem a nenhuma entrada
+func (s *CatalogService) CanonicalizeAnimal(ctx context.Context, animal *model.Animal) error {
+	species, err := s.ResolveSpecies(ctx, animal.Species)
+	if err != nil {
+		return err
+	}
+	animal.Species = species.NameEN
+
+	if animal.Breed == "" {
+		return nil
+	}
+	breed, err := ResolveBreed(species, animal.Breed)
+	if err != nil {
+		return err
+	}
+	animal.Breed = breed.NameEN
+	return nil
+}
+
+// CreateSpecies adiciona uma espécie ao catálogo. Sem código informado, ele é
+// gerado a partir do nome em inglês.
+func (s *CatalogService) CreateSpecies(ctx context.Context, species *model.Species) error {
+	species.ID = uuid.New()
+	species.Breeds = nil
+	return s.saveSpecies(ctx, species)
+}
+
+// UpdateSpecies altera os nomes, o código e os apelidos de uma espécie
+func (s *CatalogService) UpdateSpecies(ctx context.Context, id uuid.UUID, input model.Species) (*model.Species, error) {
+	species, err := s.repo.FindSpeciesByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	species.Code = input.Code
+	species.NamePT = input.NamePT
+	species.NameEN = input.NameEN
+	species.Aliases = input.Aliases
+	if err := s.saveSpecies(ctx, species); err != nil {
+		return nil, err
+	}
+	return species, nil
+}
+
+func (s *CatalogService) saveSpecies(ctx context.Context, species *model.Species) error {
+	if species.Code == "" {
+		species.Code = model.CatalogCode(species.NameEN)
+	}
+	if err := catalogValidator.Struct(species); err != nil {
+		return fmt.Errorf("espécie inválida: %w", err)
+	}
+
+	catalog, err := s.repo.ListSpecies(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao carregar o catálogo: %w", err)
+	}
+	for _, existing := range catalog {
+		if existing.ID != species.ID && namesCollide(existing.Matches, species.Code, species.NamePT, species.NameEN, species.Aliases) {
+			return fmt.Errorf("%w: %s", ErrCatalogConflict, existing.NameEN)
+		}
+	}
+	return s.repo.SaveSpecies(ctx, species)
+}
+
+func (s *CatalogService) DeleteSpecies(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteSpecies(ctx, id)
+}
+
+// AddBreed adiciona uma raça à espécie
+func (s *CatalogService) AddBreed(ctx context.Context, speciesID uuid.UUID, breed *model.Breed) error {
+	breed.ID = uuid.New()
+	breed.SpeciesID = speciesID
+	return s.saveBreed(ctx, breed)
+}
+
+// UpdateBreed altera os nomes, o código e os apelidos de uma raça
+func (s *CatalogService) UpdateBreed(ctx context.Context, id uuid.UUID, input model.Breed) (*model.Breed, error) {
+	breed, err := s.repo.FindBreedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	breed.Code = input.Code
+	breed.NamePT = input.NamePT
+	breed.NameEN = input.NameEN
+	breed.Aliases = input.Aliases
+	if err := s.saveBreed(ctx, breed); err != nil {
+		return nil, err
+	}
+	return breed, nil
+}
+
+func (s *CatalogService) saveBreed(ctx context.Context, breed *model.Breed) error {
+	if breed.Code == "" {
+		breed.Code = model.CatalogCode(breed.NameEN)
+	}
+	if err := catalogValidator.Struct(breed); err != nil {
+		return fmt.Errorf("raça inválida: %w", err)
+	}
+
+	species, err := s.repo.FindSpeciesByID(ctx, breed.SpeciesID)
+	if err != nil {
+		return err
+	}
+	for _, existing := range species.Breeds {
+		if existing.ID != breed.ID && namesCollide(existing.Matches, breed.Code, breed.NamePT, breed.NameEN, breed.Aliases) {
+			return fmt.Errorf("%w: %s", ErrCatalogConflict, existing.NameEN)
+		}
+	}
+	return s.repo.SaveBreed(ctx, breed)
+}
+
+func (s *CatalogService) DeleteBreed(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteBreed(ctx, id)
+}
+
+// namesCollide indica se algum dos nomes de uma nova entrada já identifica outra entrada
+func namesCollide(matches func(string) bool, code, namePT, nameEN string, aliases []string) bool {
+	for _, name := range append([]string{code, namePT, nameEN}, aliases...) {
+		if matches(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var ErrContraindicated = errors.New("medicamento contraindicado para o animal")
+
+// Tipos de registro que podem contraindicar um medicamento
+const (
+	ContraindicationAllergy   = "allergy"
+	ContraindicationCondition = "condition"
+)
+
+// MinOverrideReasonLength é o tamanho mínimo da justificativa para prescrever um medicamento contraindicado
+const MinOverrideReasonLength = 10
+
+// Contraindication liga um princípio ativo do medicamento a uma alergia ou condição do animal
+type Contraindication struct {
+	Kind            string    `json:"kind"` // allergy ou condition
+	RecordID        uuid.UUID `json:"record_id"`
+	Name            string    `json:"name"`
+	Severity        string    `json:"severity"`
+	ActivePrinciple string    `json:"active_principle"`
+}
+
+// ContraindicationError é devolvido quando a dosagem bate com alergias ou condições
+// registradas e não veio com uma justificativa válida
+type ContraindicationError struct {
+	Contraindications []Contraindication
+}
+
+func (e *ContraindicationError) Error() string {
+	names := make([]string, 0, len(e.Contraindications))
+	for _, c := range e.Contraindications {
+		names = append(names, fmt.Sprintf("%s (%s)", c.Name, c.ActivePrinciple))
+	}
+	return fmt.Sprintf("%s: %s", ErrContraindicated, strings.Join(names, ", "))
+}
+
+func (e *ContraindicationError) Is(target error) bool {
+	return target == ErrContraindicated
+}
+
+type ClinicalRecordService struct {
+	repo repository.ClinicalRecordRepository
+}
+
+// Cria uma nova instância do ClinicalRecordService com um repositório de alergias e condições
+func NewClinicalRecordService(repo repository.ClinicalRecordRepository) *ClinicalRecordService {
+	return &ClinicalRecordService{repo: repo}
+}
+
+var clinicalValidator = validator.New()
+
+// AddAllergy registra uma alergia do animal
+func (s *ClinicalRecordService) AddAllergy(ctx context.Context, allergy *model.Allergy) error {
+	allergy.ID = uuid.New()
+	allergy.ActivePrinciples = trimPrinciples(allergy.ActivePrinciples)
+	if err := clinicalValidator.Struct(allergy); err != nil {
+		return fmt.Errorf("alergia inválida: %w", err)
+	}
+	return s.repo.CreateAllergy(ctx, allergy)
+}
+
+func (s *ClinicalRecordService) ListAllergies(ctx context.Context, animalID uuid.UUID) ([]model.Allergy, error) {
+	return s.repo.FindAllergiesByAnimalID(ctx, animalID)
+}
+
+func (s *ClinicalRecordService) RemoveAllergy(ctx context.Context, animalID, allergyID uuid.UUID) error {
+	return s.repo.DeleteAllergy(ctx, animalID, allergyID)
+}
+
+// AddCondition registra uma condição crônica do animal
+func (s *ClinicalRecordService) AddCondition(ctx context.Context, condition *model.ChronicCondition) error {
+	condition.ID = uuid.New()
+	condition.ActivePrinciples = trimPrinciples(condition.ActivePrinciples)
+	if err := clinicalValidator.Struct(condition); err != nil {
+		return fmt.Errorf("condição crônica inválida: %w", err)
+	}
+	return s.repo.CreateCondition(ctx, condition)
+}
+
+func (s *ClinicalRecordService) ListConditions(ctx context.Context, animalID uuid.UUID) ([]model.ChronicCondition, error) {
+	return s.repo.FindConditionsByAnimalID(ctx, animalID)
+}
+
+func (s *ClinicalRecordService) RemoveCondition(ctx context.Context, animalID, conditionID uuid.UUID) error {
+	return s.repo.DeleteCondition(ctx, animalID, conditionID)
+}
+
+func trimPrinciples(principles []string) []string {
+	trimmed := make([]string, 0, len(principles))
+	for _, principle := range principles {
+		if principle = strings.TrimSpace(principle); principle != "" {
+			trimmed = append(trimmed, principle)
+		}
+	}
+	return trimmed
+}
+
+// FindContraindications cruza os princípios ativos do medicamento com os das alergias
+// e condições do animal. A comparação ignora maiúsculas, acentos e espaços extras.
+func FindContraindications(activePrinciples []string, allergies []model.Allergy, conditions []model.ChronicCondition) []Contraindication {
+	prescribed := make(map[string]string, len(activePrinciples))
+	for _, principle := range activePrinciples {
+		prescribed[model.NormalizeName(principle)] = principle
+	}
+
+	var found []Contraindication
+	for _, allergy := range allergies {
+		for _, principle := range allergy.ActivePrinciples {
+			if name, ok := prescribed[model.NormalizeName(principle)]; ok {
+				found = append(found, Contraindication{Kind: ContraindicationAllergy, RecordID: allergy.ID, Name: allergy.Allergen, Severity: allergy.Severity, ActivePrinciple: name})
+			}
+		}
+	}
+	for _, condition := range conditions {
+		for _, principle := range condition.ActivePrinciples {
+			if name, ok := prescribed[model.NormalizeName(principle)]; ok {
+				found = append(found, Contraindication{Kind: ContraindicationCondition, RecordID: condition.ID, Name: condition.Name, Severity: condition.Severity, ActivePrinciple: name})
+			}
+		}
+	}
+	return found
+}
+
+// CheckContraindications devolve as contraindicações do medicamento para o animal
+func (s *ClinicalRecordService) CheckContraindications(ctx context.Context, animalID uuid.UUID, medication *model.Medication) ([]Contraindication, error) {
+	allergies, err := s.repo.FindAllergiesByAnimalID(ctx, animalID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar alergias: %w", err)
+	}
+	conditions, err := s.repo.FindConditionsByAnimalID(ctx, animalID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar condições crônicas: %w", err)
+	}
+	return FindContraindications(medication.ActivePrinciples, allergies, conditions), nil
+}
+
+// EnforceContraindications recusa a dosagem quando o medicamento é contraindicado
+// para o animal, a menos que ela traga uma justificativa com pelo menos
+// MinOverrideReasonLength caracteres
+func (s *ClinicalRecordService) EnforceContraindications(ctx context.Context, dosage *model.Dosage, medication *model.Medication) error {
+	contraindications, err := s.CheckContraindications(ctx, dosage.AnimalID, medication)
+	if err != nil {
+		return err
+	}
+	if len(contraindications) == 0 {
+		dosage.OverrideReason = ""
+		dosage.OverriddenBy = ""
+		return nil
+	}
+
+	dosage.OverrideReason = strings.TrimSpace(dosage.OverrideReason)
+	if len([]rune(dosage.OverrideReason)) < MinOverrideReasonLength {
+		return &ContraindicationError{Contraindications: contraindications}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidConsent = errors.New("canal, finalidade ou origem do consentimento inválidos")
+	ErrNoConsent      = errors.New("tutor não autorizou contato por este canal com esta finalidade")
+)
+
+// ConsentChannels e ConsentPurposes listam as combinações que têm consentimento próprio
+var (
+	ConsentChannels = []string{model.ConsentChannelWhatsApp, model.ConsentChannelSMS, model.ConsentChannelEmail}
+	ConsentPurposes = []string{model.ConsentPurposeClinicalReminders, model.ConsentPurposeMarketing}
+)
+
+// ConsentStatus é a situação atual de um canal e finalidade. GrantedAt e Source
+// vêm do consentimento em vigor.
+type ConsentStatus struct {
+	Channel   string     `json:"channel"`
+	Purpose   string     `json:"purpose"`
+	Granted   bool       `json:"granted"`
+	GrantedAt *time.Time `json:"granted_at,omitempty"`
+	Source    string     `json:"source,omitempty"`
+}
+
+// ConsentState reúne a situação atual de cada canal e finalidade e o histórico
+// de consentimentos e revogações do tutor
+type ConsentState struct {
+	CPFTutor string               `json:"cpf_tutor"`
+	Current  []ConsentStatus      `json:"current"`
+	History  []model.TutorConsent `json:"history"`
+}
+
+type ConsentService struct {
+	repo   repository.ConsentRepository
+	tutors repository.TutorRepository
+}
+
+// Cria uma nova instância do ConsentService com os repositórios de consentimentos e de tutores
+func NewConsentService(repo repository.ConsentRepository, tutors repository.TutorRepository) *ConsentService {
+	return &ConsentService{repo: repo, tutors: tutors}
+}
+
+// GetConsents retorna a situação atual de todos os canais e finalidades e o histórico do tutor
+func (s *ConsentService) GetConsents(ctx context.Context, cpf string) (*ConsentState, error) {
+	tutor, err := s.tutors.FindTutorByCPF(ctx, model.CleanDocument(cpf))
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.repo.FindConsentsByTutor(ctx, tutor.CPFTutor)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar consentimentos: %w", err)
+	}
+	if history == nil {
+		history = []model.TutorConsent{}
+	}
+
+	state := &ConsentState{CPFTutor: tutor.CPFTutor, History: history}
+	for _, channel := range ConsentChannels {
+		for _, purpose := range ConsentPurposes {
+			status := ConsentStatus{Channel: channel, Purpose: purpose}
+			if active := activeConsent(history, channel, purpose); active != nil {
+				grantedAt := active.GrantedAt
+				status.Granted = true
+				status.GrantedAt = &grantedAt
+				status.Source = active.Source
+			}
+			state.Current = append(state.Current, status)
+		}
+	}
+	return state, nil
+}
+
+// GrantConsent registra o consentimento do tutor para o canal e a finalidade. Se
+// já houver um consentimento em vigor, ele é mantido e retornado.
+func (s *ConsentService) GrantConsent(ctx context.Context, cpf, channel, purpose, source, recordedBy string, now time.Time) (*model.TutorConsent, error) {
+	if err := checkConsentTarget(channel, purpose); err != nil {
+		return nil, err
+	}
+	tutor, err := s.tutors.FindTutorByCPF(ctx, model.CleanDocument(cpf))
+	if err != nil {
+		return nil, err
+	}
+	if tutor.AnonymizedAt != nil {
+		return nil, ErrTutorAnonymized
+	}
+	history, err := s.repo.FindConsentsByTutor(ctx, tutor.CPFTutor)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar consentimentos: %w", err)
+	}
+	if active := activeConsent(history, channel, purpose); active != nil {
+		return active, nil
+	}
+
+	consent := &model.TutorConsent{
+		ID:         uuid.New(),
+		CPFTutor:   tutor.CPFTutor,
+		Channel:    channel,
+		Purpose:    purpose,
+		GrantedAt:  now,
+		Source:     source,
+		RecordedBy: recordedBy,
+	}
+	if err := tutorValidator.Struct(consent); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConsent, err)
+	}
+	if err := s.repo.SaveConsent(ctx, consent); err != nil {
+		return nil, fmt.Errorf("erro ao salvar consentimento: %w", err)
+	}
+	log.Printf("Tutor %s autorizou %s por %s", tutor.CPFTutor, purpose, channel)
+	return consent, nil
+}
+
+// RevokeConsent registra a revogação do consentimento em vigor para o canal e a
+// finalidade. Sem consentimento em vigor, não há o que revogar e retorna ErrNoConsent.
+func (s *ConsentService) RevokeConsent(ctx context.Context, cpf, channel, purpose, source, revokedBy string, now time.Time) (*model.TutorConsent, error) {
+	if err := checkConsentTarget(channel, purpose); err != nil {
+		return nil, err
+	}
+	tutor, err := s.tutors.FindTutorByCPF(ctx, model.CleanDocument(cpf))
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.repo.FindConsentsByTutor(ctx, tutor.CPFTutor)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar consentimentos: %w", err)
+	}
+	consent := activeConsent(history, channel, purpose)
+	if consent == nil {
+		return nil, ErrNoConsent
+	}
+
+	consent.RevokedAt = &now
+	consent.RevocationSource = source
+	consent.RevokedBy = revokedBy
+	if err := tutorValidator.Struct(consent); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConsent, err)
+	}
+	if err := s.repo.SaveConsent(ctx, consent); err != nil {
+		return nil, fmt.Errorf("erro ao salvar revogação: %w", err)
+	}
+	log.Printf("Tutor %s revogou %s por %s", tutor.CPFTutor, purpose, channel)
+	return consent, nil
+}
+
+// HasConsent indica se o tutor autorizou, e não revogou, o contato pelo canal com a finalidade
+func (s *ConsentService) HasConsent(ctx context.Context, cpf, channel, purpose string) (bool, error) {
+	if err := checkConsentTarget(channel, purpose); err != nil {
+		return false, err
+	}
+	history, err := s.repo.FindConsentsByTutor(ctx, cpf)
+	if err != nil {
+		return false, fmt.Errorf("erro ao buscar consentimentos: %w", err)
+	}
+	return activeConsent(history, channel, purpose) != nil, nil
+}
+
+func checkConsentTarget(channel, purpose string) error {
+	validChannel, validPurpose := false, false
+	for _, candidate := range ConsentChannels {
+		validChannel = validChannel || candidate == channel
+	}
+	for _, candidate := range ConsentPurposes {
+		validPurpose = validPurpose || candidate == purpose
+	}
+	if !validChannel || !validPurpose {
+		return fmt.Errorf("%w: %s/%s", ErrInvalidConsent, channel, purpose)
+	}
+	return nil
+}
+
+func activeConsent(history []model.TutorConsent, channel, purpose string) *model.TutorConsent {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Channel == channel && history[i].Purpose == purpose && history[i].Active() {
+			return &history[i]
+		}
+	}
+	return nil
+}
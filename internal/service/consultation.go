@@ -11,6 +11,13 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidConsultationStatus = errors.New("situação da consulta inválida: use scheduled, completed, canceled ou no_show")
+	ErrConsultationNotStarted    = errors.New("a consulta só pode ser concluída ou marcada como falta a partir do horário agendado")
+	ErrConsultationNotReopenable = errors.New("consultas concluídas, canceladas ou com falta não mudam de situação; agende uma nova consulta")
+	ErrConsultationNotScheduled  = errors.New("novas consultas são sempre criadas como scheduled")
+)
+
 // Função para verificar se a consulta já existe e retornar erro se necessário
 func checkConsultationExistence(repo repository.ConsultationRepository, id uuid.UUID) (*model.Consultation, error) {
 	existingConsultation, err := repo.FindConsultationByID(context.Background(), id)
@@ -68,7 +75,18 @@ func findConflictingConsultations(repo repository.ConsultationRepository, consul
 	return conflictingConsultations, nil
 }
 
-func AddConsultation(repo repository.ConsultationRepository, consultation *model.Consultation, getVetFunc func(string) (*model.Veterinary, error), getAnimalFunc func(uuid.UUID) (*model.Animal, error)) error {
+// AddConsultation agenda a consulta. checkAvailabilityFunc confirma que o veterinário
+// atende no horário, conforme os horários de atendimento e as ausências dele.
+func AddConsultation(repo repository.ConsultationRepository, consultation *model.Consultation, getVetFunc func(string) (*model.Veterinary, error), getAnimalFunc func(uuid.UUID) (*model.Animal, error), checkAvailabilityFunc func(string, time.Time) error) error {
+	// Toda consulta nasce agendada; a situação só muda por UpdateConsultationStatus
+	switch consultation.ConsultationStatus {
+	case "":
+		consultation.ConsultationStatus = model.ConsultationScheduled
+	case model.ConsultationScheduled:
+	default:
+		return ErrConsultationNotScheduled
+	}
+
 	// Verifique se a consulta já existe
 	existingConsultation, _ := repo.FindConsultationByID(context.Background(), consultation.ID)
 	if existingConsultation != nil {
@@ -92,6 +110,27 @@ func AddConsultation(repo repository.ConsultationRepository, consultation *model
 	if animal == nil {
 		return errors.New("animal não encontrado")
 	}
+	if animal.IsDeceased() {
+		return ErrAnimalDeceased
+	}
+
+	// Apenas o tutor principal pode autorizar procedimentos; sem authorized_by, a
+	// autorização fica registrada em nome dele
+	if consultation.AuthorizedBy == "" {
+		consultation.AuthorizedBy = animal.CPFTutor
+	}
+	if err := AuthorizeProcedure(animal, consultation.AuthorizedBy); err != nil {
+		return err
+	}
+
+	// Verifique se o veterinário atende no horário
+	start, err := time.Parse("2006-01-02 15:04", consultation.ConsultationDate.Format("2006-01-02")+" "+consultation.ConsultationHour)
+	if err != nil {
+		return err
+	}
+	if err := checkAvailabilityFunc(consultation.CRVM, start); err != nil {
+		return err
+	}
 
 	// Verifique conflitos de horário
 	conflictingConsultations, err := findConflictingConsultations(repo, consultation)
@@ -133,6 +172,44 @@ func UpdateConsultation(repo repository.ConsultationRepository, id uuid.UUID, up
 	return nil
 }
 
+// UpdateConsultationStatus altera a situação de uma consulta agendada. Ela só pode
+// ser concluída ou marcada como falta (no_show) a partir do horário agendado, lido
+// no fuso de now (o da clínica). Consultas concluídas, canceladas ou com falta não
+// mudam mais de situação: a mudança pularia as verificações de AddConsultation e
+// do registro de óbito e alteraria as estatísticas já apuradas.
+func UpdateConsultationStatus(repo repository.ConsultationRepository, id uuid.UUID, status string, now time.Time) (*model.Consultation, error) {
+	switch status {
+	case model.ConsultationScheduled, model.ConsultationCompleted, model.ConsultationCanceled, model.ConsultationNoShow:
+	default:
+		return nil, ErrInvalidConsultationStatus
+	}
+
+	consultation, err := checkConsultationExistence(repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if consultation.ConsultationStatus != model.ConsultationScheduled {
+		return nil, ErrConsultationNotReopenable
+	}
+	if status == model.ConsultationCompleted || status == model.ConsultationNoShow {
+		start, err := time.ParseInLocation("2006-01-02 15:04", consultation.ConsultationDate.Format("2006-01-02")+" "+consultation.ConsultationHour, now.Location())
+		if err != nil {
+			start = time.Date(consultation.ConsultationDate.Year(), consultation.ConsultationDate.Month(), consultation.ConsultationDate.Day(), 0, 0, 0, 0, now.Location())
+		}
+		if now.Before(start) {
+			return nil, ErrConsultationNotStarted
+		}
+	}
+
+	consultation.ConsultationStatus = status
+	if err := repo.SaveConsultation(context.Background(), consultation); err != nil {
+		return nil, err
+	}
+	log.Printf("Consulta %s marcada como %s", consultation.ID, status)
+	return consultation, nil
+}
+
 func DeleteConsultation(repo repository.ConsultationRepository, id uuid.UUID) error {
 	consultation, err := checkConsultationExistence(repo, id)
 	if err != nil {
@@ -189,7 +266,7 @@ func GetNextConsultationByVeterinaryCRVM(repo repository.ConsultationRepository,
 				log.Printf("Atualizando próxima consulta para: %v", consultationDateTime)
 				// Atualiza a próxima consulta
 				nextConsultation = &consultation
-				nextConsultation.ConsultationDate = model.CustomDate{consultationDateTime}
+				nextConsultation.ConsultationDate = model.CustomDate{Time: consultationDateTime}
 			}
 		} else {
 			log.Printf("Consulta anterior ou no mesmo horário que o atual: %v", consultationDateTime)
@@ -224,4 +301,24 @@ func GetConsultationsByAnimalID(repo repository.ConsultationRepository, animalID
 	}
 	log.Printf("Consultas encontradas para o animal ID %s: %v", animalID, consultations)
 	return consultations, nil
-}
\ No newline at end of file
+}
+// AnnotateAnimalAge preenche a idade do animal na data de cada consulta.
+// Animais que não puderem ser carregados ficam sem idade.
+func AnnotateAnimalAge(consultations []model.Consultation, getAnimalFunc func(uuid.UUID) (*model.Animal, error)) {
+	animals := make(map[uuid.UUID]*model.Animal)
+	for i := range consultations {
+		animalID := consultations[i].AnimalID
+		animal, cached := animals[animalID]
+		if !cached {
+			found, err := getAnimalFunc(animalID)
+			if err != nil {
+				log.Printf("Erro ao buscar animal %s para calcular a idade: %v", animalID, err)
+			}
+			animals[animalID] = found
+			animal = found
+		}
+		if animal != nil {
+			consultations[i].AnimalAge = animal.AgeAt(consultations[i].ConsultationDate.Time)
+		}
+	}
+}
@@ -10,13 +10,22 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrPrescriberRequired indica uma prescrição sem o CRMV do veterinário que a assina
+var ErrPrescriberRequired = errors.New("informe o CRMV do veterinário que assina a prescrição")
+
 type DosageService struct {
-	repo repository.DosageRepository
+	repo       repository.DosageRepository
+	clinical   *ClinicalRecordService
+	animals    repository.AnimalRepositoryInterface
+	getVetFunc func(string) (*model.Veterinary, error)
 }
 
-// Cria uma nova instância do DosageService com um repositório de dosagem
-func NewDosageService(repo repository.DosageRepository) *DosageService {
-	return &DosageService{repo: repo}
+// Cria uma nova instância do DosageService com um repositório de dosagem, o
+// serviço de alergias e condições usado para barrar medicamentos contraindicados,
+// o repositório de animais usado para barrar prescrições a animais falecidos e a
+// busca do veterinário que assina a prescrição, que recusa quem não pode prescrever
+func NewDosageService(repo repository.DosageRepository, clinical *ClinicalRecordService, animals repository.AnimalRepositoryInterface, getVetFunc func(string) (*model.Veterinary, error)) *DosageService {
+	return &DosageService{repo: repo, clinical: clinical, animals: animals, getVetFunc: getVetFunc}
 }
 
 func (s *DosageService) AddDosage(ctx context.Context, dosage *model.Dosage) error {
@@ -24,7 +33,22 @@ func (s *DosageService) AddDosage(ctx context.Context, dosage *model.Dosage) err
 		return errors.New("dosagem não pode ser nula")
 	}
 
-	
+	// Verifica se o animal está vivo
+	animal, err := s.animals.FindAnimalByID(dosage.AnimalID)
+	if err != nil {
+		return err
+	}
+	if animal.IsDeceased() {
+		return ErrAnimalDeceased
+	}
+
+	// Verifica se o veterinário pode assinar a prescrição
+	if dosage.CRVM == "" {
+		return ErrPrescriberRequired
+	}
+	if _, err := s.getVetFunc(dosage.CRVM); err != nil {
+		return err
+	}
 
 	// Verifica se o medicamento existe
 	medication, err := GetMedicationByID(dosage.MedicationID)
@@ -36,6 +60,11 @@ func (s *DosageService) AddDosage(ctx context.Context, dosage *model.Dosage) err
 		return errors.New("medicamento não encontrado")
 	}
 
+	// Verifica alergias e condições crônicas do animal
+	if err := s.clinical.EnforceContraindications(ctx, dosage, medication); err != nil {
+		return err
+	}
+
 	// Verifica se a consulta existe, se ConsultationsID não for nil
 	if dosage.ConsultationID != nil {
 		consultation, err := GetConsultationByID(*dosage.ConsultationID) // Desreferencia o ponteiro
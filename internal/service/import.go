@@ -0,0 +1,430 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Situação de cada linha no relatório de importação
+const (
+	ImportRowCreated = "created"
+	ImportRowSkipped = "skipped"
+	ImportRowFailed  = "failed"
+)
+
+// MaxImportRows limita o tamanho de um arquivo de importação
+const MaxImportRows = 20000
+
+var (
+	ErrEmptyImport         = errors.New("arquivo de importação vazio")
+	ErrImportTooLarge      = fmt.Errorf("o arquivo de importação excede %d linhas", MaxImportRows)
+	ErrMissingImportColumn = errors.New("coluna obrigatória ausente no arquivo de importação")
+	ErrUnknownImportField  = errors.New("campo de importação desconhecido")
+)
+
+// Campos reconhecidos na importação e os nomes de coluna aceitos para cada um.
+// Os nomes são comparados sem diferenciar maiúsculas, acentos e espaços extras.
+var importColumnAliases = map[string][]string{
+	"cpf_tutor":    {"cpf_tutor", "cpf", "cpf do tutor", "cpf tutor"},
+	"tutor_name":   {"tutor_name", "tutor", "nome do tutor", "nome tutor", "nome_tutor", "proprietario"},
+	"email":        {"email", "e-mail"},
+	"phone":        {"phone", "telefone", "celular"},
+	"address":      {"address", "endereco"},
+	"cep":          {"cep"},
+	"street":       {"street", "logradouro", "rua"},
+	"number":       {"number", "numero"},
+	"complement":   {"complement", "complemento"},
+	"neighborhood": {"neighborhood", "bairro"},
+	"city":         {"city", "cidade", "municipio"},
+	"uf":           {"uf", "estado"},
+	"name":         {"name", "animal", "nome", "nome do animal", "nome animal", "nome_animal", "paciente"},
+	"species":      {"species", "especie"},
+	"breed":        {"breed", "raca"},
+	"birth_date":   {"birth_date", "nascimento", "data de nascimento", "data_nascimento"},
+	"age":          {"age", "idade"},
+	"weight":       {"weight", "peso"},
+	"microchip":    {"microchip", "chip"},
+	"description":  {"description", "descricao", "observacoes", "obs"},
+}
+
+// ImportOptions controla a importação. Mapping associa colunas do arquivo a
+// campos reconhecidos (ex.: {"Dono": "tutor_name"}) e tem precedência sobre os
+// nomes de coluna padrão. Em DryRun nada é gravado.
+type ImportOptions struct {
+	DryRun  bool
+	Mapping map[string]string
+}
+
+// ImportRowResult é o resultado de uma linha do arquivo; Row é o número da
+// linha no arquivo, contando o cabeçalho como linha 1
+type ImportRowResult struct {
+	Row          int        `json:"row"`
+	Status       string     `json:"status"`
+	CPFTutor     string     `json:"cpf_tutor,omitempty"`
+	AnimalName   string     `json:"animal_name,omitempty"`
+	AnimalID     *uuid.UUID `json:"animal_id,omitempty"`
+	TutorCreated bool       `json:"tutor_created"`
+	Message      string     `json:"message,omitempty"`
+}
+
+// ImportReport resume a importação linha a linha
+type ImportReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+type ImportService struct {
+	tutors  repository.TutorRepository
+	animals *AnimalService
+}
+
+// Cria uma nova instância do ImportService com o repositório de tutores e o
+// serviço de animais, que aplica as mesmas validações do cadastro manual
+func NewImportService(tutors repository.TutorRepository, animals *AnimalService) *ImportService {
+	return &ImportService{tutors: tutors, animals: animals}
+}
+
+// importBatch guarda o que já foi visto no arquivo, para que linhas repetidas
+// sejam detectadas mesmo em dry-run, quando nada chega ao banco
+type importBatch struct {
+	tutors  map[string]bool
+	animals map[string]bool
+}
+
+// ImportCSV importa tutores e animais de um CSV com cabeçalho, separado por
+// vírgula ou ponto e vírgula. Cada linha é tratada de forma independente: uma
+// linha com erro não impede as demais. Linhas sem nome de animal cadastram
+// apenas o tutor. Tutores só são criados quando o arquivo tem a coluna de nome
+// do tutor; sem ela, linhas de tutores não cadastrados falham. Animais
+// repetidos (no banco ou no próprio arquivo) são ignorados.
+func (s *ImportService) ImportCSV(ctx context.Context, r io.Reader, options ImportOptions, now time.Time) (*ImportReport, error) {
+	reader, err := newImportReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrEmptyImport
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler cabeçalho: %w", err)
+	}
+	columns, err := mapImportColumns(header, options.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{DryRun: options.DryRun, Rows: []ImportRowResult{}}
+	batch := &importBatch{tutors: map[string]bool{}, animals: map[string]bool{}}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if report.Total >= MaxImportRows {
+			return nil, ErrImportTooLarge
+		}
+
+		var result ImportRowResult
+		if err != nil {
+			result = ImportRowResult{Row: line, Status: ImportRowFailed, Message: err.Error()}
+		} else {
+			result = s.importRow(ctx, line, importRecord(columns, record), options.DryRun, batch, now)
+		}
+
+		report.Total++
+		switch result.Status {
+		case ImportRowCreated:
+			report.Created++
+		case ImportRowSkipped:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	log.Printf("CSV import (dry run: %t): %d rows, %d created, %d skipped, %d failed",
+		options.DryRun, report.Total, report.Created, report.Skipped, report.Failed)
+	return report, nil
+}
+
+func (s *ImportService) importRow(ctx context.Context, line int, fields map[string]string, dryRun bool, batch *importBatch, now time.Time) ImportRowResult {
+	result := ImportRowResult{Row: line, AnimalName: fields["name"]}
+	fail := func(message string) ImportRowResult {
+		result.Status, result.Message = ImportRowFailed, message
+		return result
+	}
+
+	cpf := model.CleanDocument(fields["cpf_tutor"])
+	result.CPFTutor = cpf
+	if cpf == "" {
+		return fail("CPF ou CNPJ do tutor não informado")
+	}
+	if !model.IsValidDocument(cpf) {
+		return fail(ErrInvalidCPF.Error())
+	}
+
+	// Monta e valida o tutor antes de qualquer gravação. Sem a coluna de nome
+	// do tutor, o animal só é importado se o tutor já estiver cadastrado.
+	var tutor *model.Tutor
+	_, hasTutorColumn := fields["tutor_name"]
+	if !batch.tutors[cpf] {
+		existing, err := s.tutors.FindTutorByCPF(ctx, cpf)
+		switch {
+		case err == nil && !existing.Active:
+			return fail(ErrTutorInactive.Error())
+		case err == nil:
+			batch.tutors[cpf] = true
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return fail(fmt.Sprintf("erro ao buscar tutor: %v", err))
+		case !hasTutorColumn:
+			return fail(ErrUnknownTutor.Error())
+		default:
+			tutor, err = model.NewTutor(cpf, fields["tutor_name"], fields["email"], fields["phone"], importAddress(fields), "")
+			if err == nil {
+				err = prepareImportAddress(tutor, fields["address"])
+			}
+			if err == nil {
+				err = tutorValidator.Struct(tutor)
+			}
+			if err != nil {
+				return fail(fmt.Sprintf("tutor inválido: %v", err))
+			}
+		}
+	}
+
+	// Monta e valida o animal
+	var animal *model.Animal
+	if result.AnimalName != "" {
+		built, err := buildImportAnimal(cpf, fields, now)
+		if err != nil {
+			return fail(err.Error())
+		}
+		if err := s.animals.PrepareAnimal(built); err != nil {
+			if errors.Is(err, ErrAnimalExists) {
+				result.Status, result.Message = ImportRowSkipped, "animal já cadastrado"
+				return result
+			}
+			return fail(err.Error())
+		}
+		key := importAnimalKey(*built)
+		if batch.animals[key] {
+			result.Status, result.Message = ImportRowSkipped, "animal repetido no arquivo"
+			return result
+		}
+		batch.animals[key] = true
+		animal = built
+	}
+
+	if tutor == nil && animal == nil {
+		result.Status, result.Message = ImportRowSkipped, "linha sem animal e tutor já cadastrado"
+		if !hasTutorColumn {
+			result.Message = "linha sem animal"
+		}
+		return result
+	}
+
+	// Tutor novo e animal da linha são gravados juntos: se um falhar, nenhum dos
+	// dois fica no banco e a linha pode ser importada de novo
+	switch {
+	case dryRun:
+	case tutor != nil:
+		if err := s.tutors.CreateTutorWithAnimal(ctx, tutor, animal); err != nil {
+			return fail(fmt.Sprintf("erro ao salvar tutor e animal: %v", err))
+		}
+	default:
+		if err := s.animals.repo.SaveAnimal(animal); err != nil {
+			return fail(fmt.Sprintf("erro ao salvar animal: %v", err))
+		}
+	}
+	if tutor != nil {
+		batch.tutors[cpf] = true
+		result.TutorCreated = true
+	}
+	if animal != nil && !dryRun {
+		result.AnimalID = &animal.ID
+	}
+
+	result.Status = ImportRowCreated
+	return result
+}
+
+func buildImportAnimal(cpf string, fields map[string]string, now time.Time) (*model.Animal, error) {
+	animal := &model.Animal{
+		ID:          uuid.New(),
+		Name:        fields["name"],
+		Species:     fields["species"],
+		Breed:       fields["breed"],
+		Description: fields["description"],
+		CPFTutor:    cpf,
+	}
+	if microchip := fields["microchip"]; microchip != "" {
+		animal.Microchip = &microchip
+	}
+	if weight := fields["weight"]; weight != "" {
+		parsed, err := strconv.ParseFloat(strings.ReplaceAll(weight, ",", "."), 64)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("peso inválido: %s", weight)
+		}
+		animal.Weight = parsed
+	}
+
+	var age *int
+	if value := fields["age"]; value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("idade inválida: %s", value)
+		}
+		age = &parsed
+	}
+	birthDate, precision, err := ResolveBirthDate(isoDate(fields["birth_date"]), "", age, now)
+	if err != nil {
+		return nil, err
+	}
+	animal.BirthDate, animal.BirthDatePrecision = birthDate, precision
+	return animal, nil
+}
+
+// importAddress monta o endereço estruturado com as colunas de endereço do arquivo
+func importAddress(fields map[string]string) model.Address {
+	return model.Address{
+		CEP:          fields["cep"],
+		Street:       fields["street"],
+		Number:       fields["number"],
+		Complement:   fields["complement"],
+		Neighborhood: fields["neighborhood"],
+		City:         fields["city"],
+		UF:           fields["uf"],
+	}
+}
+
+// prepareImportAddress valida o endereço estruturado do tutor importado. Arquivos
+// de sistemas que só têm o endereço em texto livre o gravam em AddressText, para
+// a migração dos endereços antigos.
+func prepareImportAddress(tutor *model.Tutor, text string) error {
+	if tutor.Address.IsZero() {
+		if tutor.AddressText = text; text == "" {
+			return ErrIncompleteAddress
+		}
+		return nil
+	}
+	address, err := prepareAddress(nil, tutor.Address)
+	tutor.Address = address
+	return err
+}
+
+// importAnimalKey identifica um animal pelos mesmos atributos de FindByUniqueAttributes
+func importAnimalKey(animal model.Animal) string {
+	birthDate := ""
+	if animal.BirthDate != nil {
+		birthDate = animal.BirthDate.String()
+	}
+	return strings.Join([]string{animal.CPFTutor, animal.Name, animal.Species, animal.Breed, animal.Description, birthDate}, "\x00")
+}
+
+// mapImportColumns associa o índice de cada coluna do cabeçalho a um campo
+func mapImportColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	aliases := make(map[string]string)
+	for field, names := range importColumnAliases {
+		for _, name := range names {
+			aliases[model.NormalizeName(name)] = field
+		}
+	}
+	explicit := make(map[string]string, len(mapping))
+	for column, field := range mapping {
+		if _, known := importColumnAliases[field]; !known {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownImportField, field)
+		}
+		explicit[model.NormalizeName(column)] = field
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		normalized := model.NormalizeName(name)
+		field, ok := explicit[normalized]
+		if !ok {
+			field, ok = aliases[normalized]
+		}
+		if _, taken := columns[field]; ok && !taken {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["cpf_tutor"]; !ok {
+		return nil, fmt.Errorf("%w: cpf_tutor", ErrMissingImportColumn)
+	}
+	return columns, nil
+}
+
+func importRecord(columns map[string]int, record []string) map[string]string {
+	fields := make(map[string]string, len(columns))
+	for field, i := range columns {
+		if i < len(record) {
+			fields[field] = strings.TrimSpace(record[i])
+		} else {
+			fields[field] = ""
+		}
+	}
+	return fields
+}
+
+// newImportReader detecta o separador pela primeira linha: planilhas exportadas
+// em português costumam usar ponto e vírgula
+func newImportReader(r io.Reader) (*csv.Reader, error) {
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(3); err == nil && string(bom) == "\xef\xbb\xbf" {
+		_, _ = buffered.Discard(3)
+	}
+	firstLine, err := buffered.Peek(buffered.Size())
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("erro ao ler arquivo de importação: %w", err)
+	}
+	if i := strings.IndexByte(string(firstLine), '\n'); i >= 0 {
+		firstLine = firstLine[:i]
+	}
+
+	reader := csv.NewReader(buffered)
+	if strings.Count(string(firstLine), ";") > strings.Count(string(firstLine), ",") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return reader, nil
+}
+
+// isoDate converte datas no formato brasileiro (02/01/2006) para 2006-01-02;
+// outros formatos são repassados como estão
+func isoDate(value string) string {
+	if parsed, err := time.Parse("02/01/2006", value); err == nil {
+		return parsed.Format("2006-01-02")
+	}
+	return value
+}
+
+func digitsOnly(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+
+	"github.com/google/uuid"
+)
+
+// AnonymizedTutorName substitui o nome do tutor anonimizado
+const AnonymizedTutorName = "Tutor anonimizado"
+
+// ErrNotNaturalPerson indica um pedido de anonimização para uma organização; a
+// LGPD protege pessoas físicas, e os representantes legais são removidos um a um
+var ErrNotNaturalPerson = errors.New("apenas tutores pessoa física podem ser anonimizados")
+
+// TutorExportManifest descreve o arquivo de exportação; vai em manifest.json
+type TutorExportManifest struct {
+	CPFTutor    string    `json:"cpf_tutor"`
+	GeneratedAt time.Time `json:"generated_at"`
+	GeneratedBy string    `json:"generated_by"`
+	Files       []string  `json:"files"`
+}
+
+// TutorAnonymizationResult informa o pseudônimo que passou a identificar o tutor.
+// FirebaseUID é o usuário que estava vinculado ao tutor, para que seja removido do Firebase.
+type TutorAnonymizationResult struct {
+	Pseudonym    string    `json:"pseudonym"`
+	AnonymizedAt time.Time `json:"anonymized_at"`
+	FirebaseUID  string    `json:"-"`
+}
+
+// LGPDService atende os pedidos de titulares de dados (LGPD): exportação e
+// anonimização dos dados pessoais dos tutores. Toda execução vai para o log de auditoria.
+type LGPDService struct {
+	tutors repository.TutorRepository
+	repo   repository.LGPDRepository
+}
+
+// Cria uma nova instância do LGPDService com os repositórios de tutores e da LGPD
+func NewLGPDService(tutors repository.TutorRepository, repo repository.LGPDRepository) *LGPDService {
+	return &LGPDService{tutors: tutors, repo: repo}
+}
+
+// ExportTutorData escreve em w um arquivo zip com os dados pessoais do tutor e os
+// registros dos seus animais, um arquivo JSON por tipo de registro. Consultas e
+// prescrições de antes de o animal passar ao tutor ficam de fora, como no portal.
+func (s *LGPDService) ExportTutorData(ctx context.Context, cpf, requestedBy string, w io.Writer, now time.Time) error {
+	cpf = model.CleanDocument(cpf)
+	data, err := s.repo.FindTutorData(ctx, cpf)
+	if err != nil {
+		return err
+	}
+	data.Consultations, data.Prescriptions = recordsVisibleToTutor(data, cpf)
+
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"tutor.json", data.Tutor},
+		{"legal_representatives.json", data.LegalRepresentatives},
+		{"animals.json", data.Animals},
+		{"animal_tutors.json", data.AnimalTutors},
+		{"ownership_transfers.json", data.OwnershipTransfers},
+		{"consultations.json", data.Consultations},
+		{"prescriptions.json", data.Prescriptions},
+		{"hospitalizations.json", data.Hospitalizations},
+		{"vaccinations.json", data.Vaccinations},
+		{"weights.json", data.Weights},
+		{"allergies.json", data.Allergies},
+		{"chronic_conditions.json", data.ChronicConditions},
+		{"death_records.json", data.DeathRecords},
+		{"consents.json", data.Consents},
+	}
+	manifest := TutorExportManifest{CPFTutor: cpf, GeneratedAt: now, GeneratedBy: requestedBy}
+	for _, file := range files {
+		manifest.Files = append(manifest.Files, file.name)
+	}
+
+	archive := zip.NewWriter(w)
+	if err := writeZipJSON(archive, "manifest.json", manifest, now); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := writeZipJSON(archive, file.name, file.content, now); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("erro ao gerar arquivo de exportação: %w", err)
+	}
+
+	entry := newTutorAuditLog(model.AuditActionExport, cpf, requestedBy,
+		fmt.Sprintf("Exportação dos dados pessoais: %d animais", len(data.Animals)))
+	if err := s.repo.SaveAuditLog(ctx, entry); err != nil {
+		return fmt.Errorf("erro ao registrar exportação: %w", err)
+	}
+	log.Printf("Dados do tutor %s exportados por %s", cpf, requestedBy)
+	return nil
+}
+
+// AnonymizeTutor apaga os dados pessoais do tutor: o CPF é trocado por um
+// pseudônimo em todos os registros, nome e contatos são apagados, os
+// consentimentos são revogados e nome, CPF, e-mail e telefone são retirados dos
+// textos livres dos prontuários. Animais e
+// registros clínicos são mantidos e continuam agrupados pelo pseudônimo.
+func (s *LGPDService) AnonymizeTutor(ctx context.Context, cpf, performedBy string, now time.Time) (*TutorAnonymizationResult, error) {
+	tutor, err := s.tutors.FindTutorByCPF(ctx, model.CleanDocument(cpf))
+	if err != nil {
+		return nil, err
+	}
+	if tutor.AnonymizedAt != nil {
+		return nil, ErrTutorAnonymized
+	}
+	if tutor.OwnerType == model.OwnerTypeOrganization {
+		return nil, ErrNotNaturalPerson
+	}
+
+	pseudonym, err := newTutorPseudonym()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar pseudônimo: %w", err)
+	}
+	anonymization := repository.TutorAnonymization{
+		CPF:       tutor.CPFTutor,
+		Pseudonym: pseudonym,
+		Name:      AnonymizedTutorName,
+		Terms:     anonymizationTerms(tutor),
+		At:        now,
+	}
+	entry := newTutorAuditLog(model.AuditActionAnonymize, pseudonym, performedBy, "Dados pessoais do tutor anonimizados")
+	if err := s.repo.AnonymizeTutor(ctx, anonymization, entry); err != nil {
+		return nil, fmt.Errorf("erro ao anonimizar tutor: %w", err)
+	}
+
+	result := &TutorAnonymizationResult{Pseudonym: pseudonym, AnonymizedAt: now}
+	if tutor.FirebaseUID != nil {
+		result.FirebaseUID = *tutor.FirebaseUID
+	}
+	log.Printf("Tutor anonimizado como %s por %s", pseudonym, performedBy)
+	return result, nil
+}
+
+// anonymizationTerms lista os dados pessoais do tutor que podem aparecer nos
+// textos livres: nome completo, CPF com e sem pontuação, e-mail e telefone.
+// Partes do nome não entram, para não apagar homônimos como outro "Souza" ou
+// palavras comuns que coincidem com um prenome.
+func anonymizationTerms(tutor *model.Tutor) []string {
+	terms := []string{strings.Join(strings.Fields(tutor.Name), " "), tutor.CPFTutor, formatCPF(tutor.CPFTutor), tutor.Email, tutor.Phone}
+	if phone := digitsOnly(tutor.Phone); phone != tutor.Phone {
+		terms = append(terms, phone)
+	}
+	return terms
+}
+
+// newTutorPseudonym gera o identificador do tutor anonimizado: onze dígitos
+// aleatórios com dígitos verificadores inválidos, que nunca coincidem com um CPF
+// e continuam sendo aceitos nas rotas que recebem o CPF do tutor
+func newTutorPseudonym() (string, error) {
+	for {
+		n, err := rand.Int(rand.Reader, big.NewInt(1e11))
+		if err != nil {
+			return "", err
+		}
+		if pseudonym := fmt.Sprintf("%011d", n.Int64()); !model.IsValidCPF(pseudonym) {
+			return pseudonym, nil
+		}
+	}
+}
+
+func newTutorAuditLog(action, cpf, performedBy, details string) *model.AuditLog {
+	entry := model.NewAuditLog(action, "tutor", uuid.Nil, performedBy, details)
+	entry.EntityKey = cpf
+	return entry
+}
+
+func formatCPF(cpf string) string {
+	if len(cpf) != 11 {
+		return cpf
+	}
+	return cpf[:3] + "." + cpf[3:6] + "." + cpf[6:9] + "-" + cpf[9:]
+}
+
+func writeZipJSON(archive *zip.Writer, name string, content interface{}, modified time.Time) error {
+	file, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("erro ao gerar arquivo de exportação: %w", err)
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(content); err != nil {
+		return fmt.Errorf("erro ao gerar %s: %w", name, err)
+	}
+	return nil
+}
+
+// recordsVisibleToTutor limita as consultas e prescrições exportadas aos períodos
+// em que o tutor (ou o tutor principal, para co-tutores) era responsável pelo animal
+func recordsVisibleToTutor(data *repository.TutorData, cpf string) ([]model.Consultation, []model.Dosage) {
+	transfers := map[uuid.UUID][]model.OwnershipTransfer{}
+	for _, transfer := range data.AnimalTransfers {
+		transfers[transfer.AnimalID] = append(transfers[transfer.AnimalID], transfer)
+	}
+	consultations := []model.Consultation{}
+	prescriptions := []model.Dosage{}
+	for _, animal := range data.Animals {
+		periods := BuildOwnershipPeriods(animal.CPFTutor, transfers[animal.ID])
+		viewer := TutorViewOf(animal, cpf)
+		var animalConsultations []model.Consultation
+		for _, consultation := range data.Consultations {
+			if consultation.AnimalID == animal.ID {
+				animalConsultations = append(animalConsultations, consultation)
+			}
+		}
+		var animalPrescriptions []model.Dosage
+		for _, dosage := range data.Prescriptions {
+			if dosage.AnimalID == animal.ID {
+				animalPrescriptions = append(animalPrescriptions, dosage)
+			}
+		}
+		consultations = append(consultations, ConsultationsVisibleToTutor(animalConsultations, periods, viewer)...)
+		prescriptions = append(prescriptions, DosagesVisibleToTutor(animalPrescriptions, periods, viewer)...)
+	}
+	return consultations, prescriptions
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidLicense   = errors.New("inscrição inválida: informe o número no formato 123456-SP, da mesma UF da inscrição")
+	ErrLicenseNotFound  = errors.New("inscrição não encontrada")
+	ErrNoActiveLicense  = errors.New("o veterinário não tem inscrição ativa no CRMV da UF da clínica")
+	ErrInvalidAlertDays = errors.New("o prazo do alerta deve ser de 0 a 365 dias")
+)
+
+// Situação de uma inscrição nos alertas de vencimento
+const (
+	LicenseExpired   = "expired"
+	LicenseExpiring  = "expiring"
+	LicenseNoExpiry  = "no_expiry"
+	DefaultAlertDays = 30
+)
+
+// LicenseAlert é uma inscrição vencida, a vencer ou sem validade informada
+type LicenseAlert struct {
+	model.VetLicense
+	Status   string `json:"status"`
+	DaysLeft int    `json:"days_left"` // Até o vencimento ou, sem validade, até o fim do prazo para informá-la; negativo quando já passou
+}
+
+// ClinicUFFromEnv lê a UF da clínica da variável CLINIC_UF. Sem a variável, ou com
+// uma UF inválida, retorna vazio e qualquer inscrição ativa é aceita.
+func ClinicUFFromEnv() string {
+	uf := strings.ToUpper(strings.TrimSpace(os.Getenv("CLINIC_UF")))
+	if uf == "" {
+		log.Print("CLINIC_UF not set: any active veterinary license is accepted")
+		return ""
+	}
+	if !model.IsValidUF(uf) {
+		log.Printf("Invalid CLINIC_UF %q: any active veterinary license is accepted", uf)
+		return ""
+	}
+	return uf
+}
+
+// ListLicenses retorna as inscrições do veterinário, indicando as ativas hoje
+func (s *VeterinaryService) ListLicenses(ctx context.Context, crvm string) ([]model.VetLicense, error) {
+	veterinary, err := s.repo.FindVeterinaryByCRVM(ctx, model.CleanCRMV(crvm))
+	if err != nil {
+		return nil, err
+	}
+	licenses, err := s.repo.FindLicenses(ctx, veterinary.CRVM)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar inscrições: %w", err)
+	}
+	if licenses == nil {
+		licenses = []model.VetLicense{}
+	}
+	today := time.Now()
+	for i := range licenses {
+		licenses[i].Active = licenses[i].IsActiveOn(today)
+	}
+	return licenses, nil
+}
+
+// SetLicense cadastra ou renova a inscrição do veterinário na UF do número
+// informado. expiresAt nulo indica validade não informada, e a inscrição só vale
+// até LicenseGraceDays dias depois do cadastro.
+func (s *VeterinaryService) SetLicense(ctx context.Context, crvm, number string, expiresAt *time.Time) (*model.VetLicense, error) {
+	veterinary, err := s.repo.FindVeterinaryByCRVM(ctx, model.CleanCRMV(crvm))
+	if err != nil {
+		return nil, err
+	}
+	number = model.CleanCRMV(number)
+	if !model.IsValidCRMV(number) {
+		return nil, ErrInvalidLicense
+	}
+	uf := number[len(number)-2:]
+
+	licenses, err := s.repo.FindLicenses(ctx, veterinary.CRVM)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar inscrições: %w", err)
+	}
+	license := &model.VetLicense{ID: uuid.New(), CRVM: veterinary.CRVM, UF: uf}
+	for i := range licenses {
+		if licenses[i].UF == uf {
+			license = &licenses[i]
+		}
+	}
+	license.Number = number
+	license.ExpiresAt = nil
+	if expiresAt != nil {
+		license.ExpiresAt = &model.CustomDate{Time: *expiresAt}
+	}
+	if err := veterinaryValidator.Struct(license); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveLicense(ctx, license); err != nil {
+		return nil, fmt.Errorf("erro ao salvar inscrição: %w", err)
+	}
+	license.Active = license.IsActiveOn(time.Now())
+	log.Printf("Inscrição %s do veterinário %s salva", license.Number, veterinary.CRVM)
+	return license, nil
+}
+
+// DeleteLicense remove a inscrição do veterinário na UF
+func (s *VeterinaryService) DeleteLicense(ctx context.Context, crvm, uf string) error {
+	licenses, err := s.repo.FindLicenses(ctx, model.CleanCRMV(crvm))
+	if err != nil {
+		return fmt.Errorf("erro ao buscar inscrições: %w", err)
+	}
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+	for i := range licenses {
+		if licenses[i].UF == uf {
+			if err := s.repo.DeleteLicense(ctx, &licenses[i]); err != nil {
+				return fmt.Errorf("erro ao remover inscrição: %w", err)
+			}
+			log.Printf("Inscrição %s do veterinário %s removida", licenses[i].Number, licenses[i].CRVM)
+			return nil
+		}
+	}
+	return ErrLicenseNotFound
+}
+
+// LicenseAlerts lista as inscrições dos veterinários ativos vencidas, que vencem
+// nos próximos days dias ou sem validade informada
+func (s *VeterinaryService) LicenseAlerts(ctx context.Context, days int, now time.Time) ([]LicenseAlert, error) {
+	if days < 0 || days > 365 {
+		return nil, ErrInvalidAlertDays
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	licenses, err := s.repo.FindLicensesExpiringBy(ctx, today.AddDate(0, 0, days).Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar inscrições: %w", err)
+	}
+
+	alerts := []LicenseAlert{}
+	for _, license := range licenses {
+		license.Active = license.IsActiveOn(today)
+		alert := LicenseAlert{VetLicense: license, Status: LicenseNoExpiry, DaysLeft: int(license.ValidUntil().Sub(today).Hours() / 24)}
+		if license.ExpiresAt != nil {
+			alert.Status = LicenseExpiring
+			if !license.Active {
+				alert.Status = LicenseExpired
+			}
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+// FindLicensedVeterinary busca o veterinário que assina uma prescrição e exige
+// inscrição ativa hoje na UF da clínica (ver FindVeterinaryLicensedOn)
+func (s *VeterinaryService) FindLicensedVeterinary(crvm string) (*model.Veterinary, error) {
+	return s.FindVeterinaryLicensedOn(crvm, time.Now())
+}
+
+// FindVeterinaryLicensedOn busca o veterinário que atende uma consulta e exige
+// inscrição ativa na UF da clínica na data da consulta. Retorna
+// ErrUnknownVeterinarian se o CRMV não estiver cadastrado e ErrNoActiveLicense
+// se não houver inscrição ativa na data.
+func (s *VeterinaryService) FindVeterinaryLicensedOn(crvm string, date time.Time) (*model.Veterinary, error) {
+	veterinary, err := s.FindVeterinary(crvm)
+	if err != nil {
+		return nil, err
+	}
+	licenses, err := s.repo.FindLicenses(context.Background(), veterinary.CRVM)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("erro ao buscar inscrições: %w", err)
+	}
+	for _, license := range licenses {
+		if (s.clinicUF == "" || license.UF == s.clinicUF) && license.IsActiveOn(date) {
+			return veterinary, nil
+		}
+	}
+	return nil, ErrNoActiveLicense
+}
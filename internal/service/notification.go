@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"os"
+	"strings"
+	"vetblock/internal/db/model"
+)
+
+var (
+	ErrNoNotificationSender = errors.New("nenhum envio configurado para o canal")
+	ErrNoContact            = errors.New("o tutor não tem contato cadastrado para o canal")
+)
+
+// Notification é uma mensagem para um tutor, por um canal e com uma finalidade
+// (model.ConsentChannel* e model.ConsentPurpose*)
+type Notification struct {
+	CPFTutor string
+	Channel  string
+	Purpose  string
+	Subject  string
+	Message  string
+}
+
+// NotificationSender entrega mensagens por um canal (WhatsApp, SMS ou e-mail),
+// usando os contatos do cadastro do tutor
+type NotificationSender interface {
+	Send(ctx context.Context, tutor *model.Tutor, notification Notification) error
+}
+
+// NotificationService é o único caminho para enviar mensagens aos tutores: antes
+// de cada envio confere o consentimento do tutor para o canal e a finalidade.
+type NotificationService struct {
+	consents *ConsentService
+	senders  map[string]NotificationSender
+}
+
+// Cria uma nova instância do NotificationService com os envios de cada canal
+func NewNotificationService(consents *ConsentService, senders map[string]NotificationSender) *NotificationService {
+	return &NotificationService{consents: consents, senders: senders}
+}
+
+// Notify envia a mensagem se o tutor estiver ativo e tiver autorizado o contato;
+// caso contrário retorna ErrNoConsent sem enviar
+func (s *NotificationService) Notify(ctx context.Context, notification Notification) error {
+	tutor, err := s.consents.tutors.FindTutorByCPF(ctx, notification.CPFTutor)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar tutor: %w", err)
+	}
+	if !tutor.Active {
+		return ErrNoConsent
+	}
+	granted, err := s.consents.HasConsent(ctx, tutor.CPFTutor, notification.Channel, notification.Purpose)
+	if err != nil {
+		return err
+	}
+	if !granted {
+		return ErrNoConsent
+	}
+
+	sender, ok := s.senders[notification.Channel]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoNotificationSender, notification.Channel)
+	}
+	if err := sender.Send(ctx, tutor, notification); err != nil {
+		return fmt.Errorf("erro ao enviar mensagem: %w", err)
+	}
+	log.Printf("Mensagem (%s) enviada ao tutor %s por %s", notification.Purpose, tutor.CPFTutor, notification.Channel)
+	return nil
+}
+
+// EmailSender envia as mensagens por e-mail, pelo servidor SMTP configurado
+type EmailSender struct {
+	Addr string // host:porta do servidor SMTP
+	From string
+	Auth smtp.Auth
+}
+
+// EmailSenderFromEnv configura o envio de e-mails pelas variáveis SMTP_HOST,
+// SMTP_PORT (padrão 587), SMTP_USERNAME, SMTP_PASSWORD e SMTP_FROM. Sem SMTP_HOST
+// ou SMTP_FROM retorna nil e nenhum e-mail é enviado.
+func EmailSenderFromEnv() *EmailSender {
+	host := strings.TrimSpace(os.Getenv("SMTP_HOST"))
+	from := strings.TrimSpace(os.Getenv("SMTP_FROM"))
+	if host == "" || from == "" {
+		log.Print("SMTP_HOST or SMTP_FROM not set: e-mail notifications are disabled")
+		return nil
+	}
+	port := strings.TrimSpace(os.Getenv("SMTP_PORT"))
+	if port == "" {
+		port = "587"
+	}
+	sender := &EmailSender{Addr: host + ":" + port, From: from}
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		sender.Auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+	return sender
+}
+
+// Send envia a mensagem em texto simples para o e-mail do cadastro do tutor
+func (s *EmailSender) Send(ctx context.Context, tutor *model.Tutor, notification Notification) error {
+	if tutor.Email == "" {
+		return ErrNoContact
+	}
+	message := strings.Join([]string{
+		"From: " + s.From,
+		"To: " + tutor.Email,
+		"Subject: " + mime.QEncoding.Encode("utf-8", notification.Subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		notification.Message,
+	}, "\r\n")
+	return smtp.SendMail(s.Addr, s.Auth, s.From, []string{tutor.Email}, []byte(message))
+}
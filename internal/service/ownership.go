@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+	"vetblock/internal/db/model"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCPF            = errors.New("CPF ou CNPJ inválido")
+	ErrSameTutor             = errors.New("animal already belongs to this tutor")
+	ErrFutureEffectiveDate   = errors.New("effective date cannot be in the future")
+	ErrEffectiveDateOutdated = errors.New("effective date is before the last transfer")
+	ErrTutorChangeNotAllowed = errors.New("cpf_tutor cannot be edited; use POST /animals/:id/transfer to transfer the animal")
+)
+
+// OwnershipPeriod é o intervalo em que um tutor foi responsável pelo animal.
+// From nulo indica "desde o cadastro"; To nulo indica o tutor atual.
+type OwnershipPeriod struct {
+	CPFTutor string     `json:"cpf_tutor"`
+	From     *time.Time `json:"from"`
+	To       *time.Time `json:"to"`
+	Reason   string     `json:"reason,omitempty"` // motivo da transferência que iniciou o período
+}
+
+// TransferOwnership moves an animal to a new tutor, recording the previous and
+// new owner, the effective date and the reason.
+func (s *AnimalService) TransferOwnership(id uuid.UUID, newCPF string, effectiveDate time.Time, reason, transferredBy string) (*model.OwnershipTransfer, error) {
+	if !model.IsValidDocument(newCPF) {
+		return nil, ErrInvalidCPF
+	}
+
+	animal, err := s.repo.FindAnimalByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("error finding animal: %w", err)
+	}
+	if animal.CPFTutor == newCPF {
+		return nil, ErrSameTutor
+	}
+	if err := s.checkTutor(newCPF); err != nil {
+		return nil, err
+	}
+	if effectiveDate.After(time.Now()) {
+		return nil, ErrFutureEffectiveDate
+	}
+
+	transfers, err := s.repo.FindOwnershipTransfers(id)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving ownership transfers: %w", err)
+	}
+	if len(transfers) > 0 && effectiveDate.Before(transfers[len(transfers)-1].EffectiveDate.Time) {
+		return nil, ErrEffectiveDateOutdated
+	}
+
+	transfer := &model.OwnershipTransfer{
+		ID:            uuid.New(),
+		AnimalID:      id,
+		PreviousCPF:   animal.CPFTutor,
+		NewCPF:        newCPF,
+		EffectiveDate: model.CustomDate{Time: effectiveDate},
+		Reason:        reason,
+		TransferredBy: transferredBy,
+	}
+	if err := animalValidator.Struct(transfer); err != nil {
+		return nil, fmt.Errorf("invalid transfer: %w", err)
+	}
+
+	history := &model.AnimalHistory{
+		ID:        uuid.New(),
+		AnimalID:  id,
+		ChangedBy: transferredBy,
+		Changes:   []model.Change{{Field: "cpf_tutor", OldValue: animal.CPFTutor, NewValue: newCPF}},
+		Timestamp: time.Now(),
+	}
+
+	if err := s.repo.TransferOwnership(animal, transfer, history); err != nil {
+		return nil, fmt.Errorf("error transferring animal: %w", err)
+	}
+
+	log.Printf("Animal %s transferred from %s to %s", id, transfer.PreviousCPF, newCPF)
+	return transfer, nil
+}
+
+// GetOwnershipPeriods lists every tutor the animal has had, oldest first.
+func (s *AnimalService) GetOwnershipPeriods(id uuid.UUID) ([]OwnershipPeriod, error) {
+	animal, err := s.repo.FindAnimalByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("error finding animal: %w", err)
+	}
+
+	transfers, err := s.repo.FindOwnershipTransfers(id)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving ownership transfers: %w", err)
+	}
+	return BuildOwnershipPeriods(animal.CPFTutor, transfers), nil
+}
+
+// BuildOwnershipPeriods monta os períodos de cada tutor a partir das transferências
+func BuildOwnershipPeriods(currentCPF string, transfers []model.OwnershipTransfer) []OwnershipPeriod {
+	if len(transfers) == 0 {
+		return []OwnershipPeriod{{CPFTutor: currentCPF}}
+	}
+
+	sorted := make([]model.OwnershipTransfer, len(transfers))
+	copy(sorted, transfers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].EffectiveDate.Before(sorted[j].EffectiveDate.Time)
+	})
+
+	firstEnd := sorted[0].EffectiveDate.Time
+	periods := []OwnershipPeriod{{CPFTutor: sorted[0].PreviousCPF, To: &firstEnd}}
+	for i, transfer := range sorted {
+		start := transfer.EffectiveDate.Time
+		period := OwnershipPeriod{CPFTutor: transfer.NewCPF, From: &start, Reason: transfer.Reason}
+		if i+1 < len(sorted) {
+			end := sorted[i+1].EffectiveDate.Time
+			period.To = &end
+		}
+		periods = append(periods, period)
+	}
+	return periods
+}
+
+// ConsultationsVisibleToTutor mantém apenas as consultas realizadas enquanto o
+// tutor era responsável pelo animal. A equipe da clínica continua vendo todas;
+// este filtro vale somente para o acesso do próprio tutor.
+func ConsultationsVisibleToTutor(consultations []model.Consultation, periods []OwnershipPeriod, cpf string) []model.Consultation {
+	visible := []model.Consultation{}
+	for _, consultation := range consultations {
+		if tutorWasResponsible(periods, cpf, consultation.ConsultationDate.Time) {
+			visible = append(visible, consultation)
+		}
+	}
+	return visible
+}
+
+// DosagesVisibleToTutor mantém apenas as prescrições iniciadas enquanto o tutor
+// era responsável pelo animal, com a mesma regra das consultas
+func DosagesVisibleToTutor(dosages []model.Dosage, periods []OwnershipPeriod, cpf string) []model.Dosage {
+	visible := []model.Dosage{}
+	for _, dosage := range dosages {
+		if tutorWasResponsible(periods, cpf, dosage.StartDate.Time) {
+			visible = append(visible, dosage)
+		}
+	}
+	return visible
+}
+
+// TutorViewOf devolve o CPF cujos períodos definem o que o tutor vê do animal. Os
+// co-tutores acompanham o tutor principal e veem o mesmo histórico que ele.
+func TutorViewOf(animal model.Animal, cpf string) string {
+	if animal.CPFTutor != cpf {
+		return animal.CPFTutor
+	}
+	return cpf
+}
+
+// tutorWasResponsible informa se a data está dentro de algum período do tutor
+func tutorWasResponsible(periods []OwnershipPeriod, cpf string, date time.Time) bool {
+	for _, period := range periods {
+		if period.CPFTutor != cpf {
+			continue
+		}
+		if period.From != nil && date.Before(*period.From) {
+			continue
+		}
+		if period.To != nil && !date.Before(*period.To) {
+			continue
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ConsultationSlot é o tempo reservado para cada consulta, o mesmo intervalo
+// usado na verificação de conflitos entre consultas
+const ConsultationSlot = 15 * time.Minute
+
+// MaxExceptionDays limita o período de uma ausência e das consultas de ausências
+const MaxExceptionDays = 366
+
+var (
+	ErrInvalidWorkingHours     = errors.New("horário de atendimento inválido: informe dia da semana de 0 a 6 e início anterior ao fim")
+	ErrOverlappingWorkingHours = errors.New("os intervalos de atendimento do mesmo dia se sobrepõem")
+	ErrInvalidException        = errors.New("ausência inválida: a data de início deve ser anterior ou igual à de término e o horário, se informado, deve ter início e fim")
+	ErrExceptionNotFound       = errors.New("ausência não encontrada")
+	ErrOutsideWorkingHours     = errors.New("horário fora do expediente do veterinário")
+	ErrVeterinaryUnavailable   = errors.New("o veterinário está ausente no horário da consulta")
+)
+
+var scheduleValidator = validator.New()
+
+// DefaultClinicTimeZone é o fuso dos horários das consultas quando CLINIC_TZ não é definida
+const DefaultClinicTimeZone = "America/Sao_Paulo"
+
+// ClinicLocationFromEnv lê o fuso horário da clínica da variável CLINIC_TZ (ex.:
+// America/Manaus). Os horários das consultas são gravados nesse fuso.
+func ClinicLocationFromEnv() *time.Location {
+	name := strings.TrimSpace(os.Getenv("CLINIC_TZ"))
+	if name == "" {
+		name = DefaultClinicTimeZone
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("Invalid CLINIC_TZ %q: using UTC", name)
+		return time.UTC
+	}
+	return location
+}
+
+// TimeInterval é um intervalo de horário em um dia, no formato 15:04
+type TimeInterval struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// DayAvailability são os horários de atendimento do veterinário em uma data, já
+// descontadas as ausências
+type DayAvailability struct {
+	CRVM       string                        `json:"crvm"`
+	Date       string                        `json:"date"`
+	Intervals  []TimeInterval                `json:"intervals"`
+	Exceptions []model.AvailabilityException `json:"exceptions"`
+}
+
+// clockInterval é um intervalo em minutos desde a meia-noite
+type clockInterval struct{ start, end int }
+
+type ScheduleService struct {
+	repo         repository.ScheduleRepository
+	veterinaries repository.VeterinaryRepository
+}
+
+// Cria uma nova instância do ScheduleService com os repositórios de agenda e de veterinários
+func NewScheduleService(repo repository.ScheduleRepository, veterinaries repository.VeterinaryRepository) *ScheduleService {
+	return &ScheduleService{repo: repo, veterinaries: veterinaries}
+}
+
+// GetWorkingHours retorna os intervalos de atendimento semanais do veterinário
+func (s *ScheduleService) GetWorkingHours(ctx context.Context, crvm string) ([]model.WorkingHours, error) {
+	veterinary, err := s.veterinaries.FindVeterinaryByCRVM(ctx, model.CleanCRMV(crvm))
+	if err != nil {
+		return nil, err
+	}
+	hours, err := s.repo.FindWorkingHours(ctx, veterinary.CRVM)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar horários de atendimento: %w", err)
+	}
+	if hours == nil {
+		hours = []model.WorkingHours{}
+	}
+	return hours, nil
+}
+
+// SetWorkingHours substitui os intervalos de atendimento semanais do veterinário.
+// Uma lista vazia remove todos os horários, e o veterinário deixa de receber
+// consultas até que um novo horário seja cadastrado.
+func (s *ScheduleService) SetWorkingHours(ctx context.Context, crvm string, hours []model.WorkingHours) ([]model.WorkingHours, error) {
+	veterinary, err := s.veterinaries.FindVeterinaryByCRVM(ctx, model.CleanCRMV(crvm))
+	if err != nil {
+		return nil, err
+	}
+
+	byDay := make(map[int][]clockInterval)
+	for i := range hours {
+		h := &hours[i]
+		h.ID = uuid.New()
+		h.CRVM = veterinary.CRVM
+		h.StartTime = strings.TrimSpace(h.StartTime)
+		h.EndTime = strings.TrimSpace(h.EndTime)
+		if err := scheduleValidator.Struct(h); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidWorkingHours, err)
+		}
+		interval, err := parseClockInterval(h.StartTime, h.EndTime)
+		if err != nil {
+			return nil, ErrInvalidWorkingHours
+		}
+		byDay[h.Weekday] = append(byDay[h.Weekday], interval)
+	}
+	for _, intervals := range byDay {
+		sort.Slice(intervals, func(i, j int) bool { return intervals[i].start < intervals[j].start })
+		for i := 1; i < len(intervals); i++ {
+			if intervals[i].start < intervals[i-1].end {
+				return nil, ErrOverlappingWorkingHours
+			}
+		}
+	}
+
+	if err := s.repo.ReplaceWorkingHours(ctx, veterinary.CRVM, hours); err != nil {
+		return nil, fmt.Errorf("erro ao salvar horários de atendimento: %w", err)
+	}
+	log.Printf("Horários de atendimento do veterinário %s alterados: %d intervalos", veterinary.CRVM, len(hours))
+	return s.GetWorkingHours(ctx, veterinary.CRVM)
+}
+
+// ListExceptions retorna as ausências do veterinário que se sobrepõem ao período
+func (s *ScheduleService) ListExceptions(ctx context.Context, crvm string, from, to time.Time) ([]model.AvailabilityException, error) {
+	veterinary, err := s.veterinaries.FindVeterinaryByCRVM(ctx, model.CleanCRMV(crvm))
+	if err != nil {
+		return nil, err
+	}
+	if to.Before(from) || to.Sub(from) > MaxExceptionDays*24*time.Hour {
+		return nil, ErrInvalidException
+	}
+	exceptions, err := s.repo.FindExceptions(ctx, veterinary.CRVM, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar ausências: %w", err)
+	}
+	if exceptions == nil {
+		exceptions = []model.AvailabilityException{}
+	}
+	return exceptions, nil
+}
+
+// AddException registra uma ausência do veterinário, como férias, curso ou
+// atestado médico. As consultas já agendadas no período não são alteradas.
+func (s *ScheduleService) AddException(ctx context.Context, crvm string, exception model.AvailabilityException, createdBy string) (*model.AvailabilityException, error) {
+	veterinary, err := s.veterinaries.FindVeterinaryByCRVM(ctx, model.CleanCRMV(crvm))
+	if err != nil {
+		return nil, err
+	}
+
+	exception.ID = uuid.New()
+	exception.CRVM = veterinary.CRVM
+	exception.CreatedBy = createdBy
+	exception.StartTime = strings.TrimSpace(exception.StartTime)
+	exception.EndTime = strings.TrimSpace(exception.EndTime)
+	exception.Notes = strings.TrimSpace(exception.Notes)
+	if err := scheduleValidator.Struct(&exception); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidException, err)
+	}
+	if exception.EndDate.Before(exception.StartDate.Time) ||
+		exception.EndDate.Sub(exception.StartDate.Time) > MaxExceptionDays*24*time.Hour {
+		return nil, ErrInvalidException
+	}
+	if !exception.IsFullDay() {
+		if _, err := parseClockInterval(exception.StartTime, exception.EndTime); err != nil {
+			return nil, ErrInvalidException
+		}
+	}
+
+	if err := s.repo.SaveException(ctx, &exception); err != nil {
+		return nil, fmt.Errorf("erro ao salvar ausência: %w", err)
+	}
+	log.Printf("Ausência do veterinário %s registrada de %s a %s (%s)", veterinary.CRVM, exception.StartDate, exception.EndDate, exception.Reason)
+	return &exception, nil
+}
+
+// DeleteException remove uma ausência do veterinário
+func (s *ScheduleService) DeleteException(ctx context.Context, crvm string, id uuid.UUID) error {
+	exception, err := s.repo.FindExceptionByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && exception.CRVM != model.CleanCRMV(crvm)) {
+		return ErrExceptionNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao buscar ausência: %w", err)
+	}
+	if err := s.repo.DeleteException(ctx, id); err != nil {
+		return fmt.Errorf("erro ao remover ausência: %w", err)
+	}
+	return nil
+}
+
+// GetDayAvailability retorna os horários de atendimento do veterinário na data,
+// descontadas as ausências
+func (s *ScheduleService) GetDayAvailability(ctx context.Context, crvm string, date time.Time) (*DayAvailability, error) {
+	veterinary, err := s.veterinaries.FindVeterinaryByCRVM(ctx, model.CleanCRMV(crvm))
+	if err != nil {
+		return nil, err
+	}
+	hours, err := s.repo.FindWorkingHours(ctx, veterinary.CRVM)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar horários de atendimento: %w", err)
+	}
+	intervals, _, exceptions, err := s.dayIntervals(ctx, veterinary, hours, date)
+	if err != nil {
+		return nil, err
+	}
+
+	availability := &DayAvailability{
+		CRVM:       veterinary.CRVM,
+		Date:       date.Format("2006-01-02"),
+		Intervals:  []TimeInterval{},
+		Exceptions: exceptions,
+	}
+	for _, interval := range intervals {
+		availability.Intervals = append(availability.Intervals, TimeInterval{Start: formatClock(interval.start), End: formatClock(interval.end)})
+	}
+	return availability, nil
+}
+
+// CheckAvailability confirma que o veterinário atende no horário de início da
+// consulta e durante todo o ConsultationSlot, sem ausência registrada. Veterinários
+// que nunca tiveram horário semanal cadastrado atendem o dia todo, descontadas as
+// ausências; os que tiveram o horário esvaziado não recebem consultas.
+func (s *ScheduleService) CheckAvailability(crvm string, start time.Time) error {
+	ctx := context.Background()
+	veterinary, err := s.veterinaries.FindVeterinaryByCRVM(ctx, model.CleanCRMV(crvm))
+	if err != nil {
+		return err
+	}
+	hours, err := s.repo.FindWorkingHours(ctx, veterinary.CRVM)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar horários de atendimento: %w", err)
+	}
+	if len(hours) == 0 && veterinary.HoursSetAt == nil {
+		log.Printf("Aviso: veterinário %s sem horário de atendimento cadastrado; agendamento sem restrição de expediente", veterinary.CRVM)
+	}
+	available, working, _, err := s.dayIntervals(ctx, veterinary, hours, start)
+	if err != nil {
+		return err
+	}
+
+	begin := start.Hour()*60 + start.Minute()
+	slot := clockInterval{begin, begin + int(ConsultationSlot/time.Minute)}
+	if !containsInterval(working, slot) {
+		return ErrOutsideWorkingHours
+	}
+	if !containsInterval(available, slot) {
+		return ErrVeterinaryUnavailable
+	}
+	return nil
+}
+
+// dayIntervals retorna os intervalos de atendimento do dia da semana da data
+// (working) e o que resta deles depois de descontadas as ausências do dia
+// (available). Se o veterinário nunca teve horário semanal cadastrado, o dia todo
+// é de atendimento; um horário cadastrado e depois esvaziado não tem atendimento.
+func (s *ScheduleService) dayIntervals(ctx context.Context, veterinary *model.Veterinary, hours []model.WorkingHours, date time.Time) (available, working []clockInterval, exceptions []model.AvailabilityException, err error) {
+	if len(hours) == 0 && veterinary.HoursSetAt == nil {
+		working = []clockInterval{{0, 24 * 60}}
+	}
+	for _, h := range hours {
+		if h.Weekday != int(date.Weekday()) {
+			continue
+		}
+		if interval, err := parseClockInterval(h.StartTime, h.EndTime); err == nil {
+			working = append(working, interval)
+		}
+	}
+
+	day := date.Format("2006-01-02")
+	exceptions, err = s.repo.FindExceptions(ctx, veterinary.CRVM, day, day)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("erro ao buscar ausências: %w", err)
+	}
+	if exceptions == nil {
+		exceptions = []model.AvailabilityException{}
+	}
+
+	available = working
+	for _, exception := range exceptions {
+		absence := clockInterval{0, 24 * 60}
+		if !exception.IsFullDay() {
+			if absence, err = parseClockInterval(exception.StartTime, exception.EndTime); err != nil {
+				continue
+			}
+		}
+		available = subtractInterval(available, absence)
+	}
+	return available, working, exceptions, nil
+}
+
+// parseClockInterval converte um intervalo no formato 15:04, exigindo início anterior ao fim
+func parseClockInterval(start, end string) (clockInterval, error) {
+	from, err := time.Parse("15:04", start)
+	if err != nil {
+		return clockInterval{}, err
+	}
+	to, err := time.Parse("15:04", end)
+	if err != nil {
+		return clockInterval{}, err
+	}
+	interval := clockInterval{from.Hour()*60 + from.Minute(), to.Hour()*60 + to.Minute()}
+	if interval.start >= interval.end {
+		return clockInterval{}, ErrInvalidWorkingHours
+	}
+	return interval, nil
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// containsInterval indica se inner está inteiramente dentro de um dos intervalos
+func containsInterval(intervals []clockInterval, inner clockInterval) bool {
+	for _, interval := range intervals {
+		if interval.start <= inner.start && inner.end <= interval.end {
+			return true
+		}
+	}
+	return false
+}
+
+// subtractInterval remove cut dos intervalos, dividindo os que o contêm
+func subtractInterval(intervals []clockInterval, cut clockInterval) []clockInterval {
+	var result []clockInterval
+	for _, interval := range intervals {
+		if cut.end <= interval.start || cut.start >= interval.end {
+			result = append(result, interval)
+			continue
+		}
+		if interval.start < cut.start {
+			result = append(result, clockInterval{interval.start, cut.start})
+		}
+		if cut.end < interval.end {
+			result = append(result, clockInterval{cut.end, interval.end})
+		}
+	}
+	return result
+}
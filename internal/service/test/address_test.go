@@ -0,0 +1,166 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const cepTable = "cep;logradouro;bairro;cidade;uf\n" +
+	"01310-100;Avenida Paulista;Bela Vista;São Paulo;SP\n" +
+	"13100000;;;Campinas;sp\n"
+
+func TestAddressValidation(t *testing.T) {
+	assert.True(t, model.IsValidCEP("01310-100"))
+	assert.True(t, model.IsValidCEP("01310100"))
+	assert.False(t, model.IsValidCEP("1310-100"))
+	assert.False(t, model.IsValidCEP("01.310-100a"))
+	assert.False(t, model.IsValidCEP("00000-000"))
+	assert.True(t, model.IsValidUF("DF"))
+	assert.False(t, model.IsValidUF("df"))
+	assert.False(t, model.IsValidUF("XX"))
+
+	address := model.Address{CEP: " 01310-100", Street: "Avenida Paulista ", Number: "1000", Complement: "apto 12", Neighborhood: "Bela Vista", City: "São Paulo", UF: "sp"}.Normalize()
+	assert.Equal(t, "01310100", address.CEP)
+	assert.Equal(t, "SP", address.UF)
+	assert.True(t, address.IsComplete())
+	assert.Equal(t, "Avenida Paulista, 1000 - apto 12 - Bela Vista - São Paulo/SP - CEP 01310-100", address.String())
+}
+
+func TestCEPDirectory(t *testing.T) {
+	directory, err := service.LoadCEPDirectory(strings.NewReader(cepTable))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, directory.Len())
+
+	address, ok := directory.Lookup("01310-100")
+	assert.True(t, ok)
+	assert.Equal(t, "Bela Vista", address.Neighborhood)
+	_, ok = directory.Lookup("99999999")
+	assert.False(t, ok)
+
+	_, err = service.LoadCEPDirectory(strings.NewReader("cep;cidade;uf\n0131-100;São Paulo;SP\n"))
+	assert.ErrorIs(t, err, service.ErrInvalidCEP)
+	_, err = service.LoadCEPDirectory(strings.NewReader("cep;cidade\n01310100;São Paulo\n"))
+	assert.ErrorIs(t, err, service.ErrMissingImportColumn)
+
+	var empty *service.CEPDirectory
+	_, ok = empty.Lookup("01310100")
+	assert.False(t, ok)
+}
+
+func TestTutorAddress(t *testing.T) {
+	ctx := context.Background()
+	directory, err := service.LoadCEPDirectory(strings.NewReader(cepTable))
+	assert.NoError(t, err)
+	newService := func() (*MockTutorRepo, *service.TutorService) {
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("FindTutorByCPF", ctx, "52998224725").Return(nil, gorm.ErrRecordNotFound)
+		tutorRepo.On("CreateTutor", ctx, mock.AnythingOfType("*model.Tutor")).Return(nil)
+		return tutorRepo, service.NewTutorService(tutorRepo, service.WithCEPDirectory(directory))
+	}
+
+	t.Run("completes the address from the CEP table", func(t *testing.T) {
+		_, tutorService := newService()
+
+		tutor, err := tutorService.CreateTutor(ctx, "52998224725", "Maria Souza", "maria@example.com", "11987654321", model.Address{CEP: "01310-100", Number: "1000"})
+		assert.NoError(t, err)
+		assert.Equal(t, model.Address{CEP: "01310100", Street: "Avenida Paulista", Number: "1000", Neighborhood: "Bela Vista", City: "São Paulo", UF: "SP"}, tutor.Address)
+	})
+
+	t.Run("rejects invalid, inconsistent and incomplete addresses", func(t *testing.T) {
+		tutorRepo, tutorService := newService()
+
+		_, err := tutorService.CreateTutor(ctx, "52998224725", "Maria Souza", "maria@example.com", "11987654321", model.Address{CEP: "0131-100", Street: "Avenida Paulista", Neighborhood: "Bela Vista", City: "São Paulo", UF: "SP"})
+		assert.ErrorIs(t, err, service.ErrInvalidCEP)
+		_, err = tutorService.CreateTutor(ctx, "52998224725", "Maria Souza", "maria@example.com", "11987654321", model.Address{CEP: "01310-100", UF: "RJ"})
+		assert.ErrorIs(t, err, service.ErrCEPMismatch)
+		_, err = tutorService.CreateTutor(ctx, "52998224725", "Maria Souza", "maria@example.com", "11987654321", model.Address{CEP: "13100-000", Number: "S/N"})
+		assert.ErrorIs(t, err, service.ErrIncompleteAddress)
+		_, err = tutorService.CreateTutor(ctx, "52998224725", "Maria Souza", "maria@example.com", "11987654321", model.Address{})
+		assert.ErrorIs(t, err, service.ErrIncompleteAddress)
+		tutorRepo.AssertNotCalled(t, "CreateTutor", mock.Anything, mock.Anything)
+	})
+
+	t.Run("a new address replaces the free-text one", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		existing := &model.Tutor{CPFTutor: "52998224725", Name: "Maria Souza", User: model.User{Email: "maria@example.com", Phone: "11987654321", UserType: model.TutorType},
+			OwnerType: model.OwnerTypePerson, AddressText: "Av. Paulista 1000", Active: true}
+		tutorRepo.On("FindTutorByCPF", ctx, "52998224725").Return(existing, nil)
+		tutorRepo.On("UpdateTutor", ctx, existing).Return(nil)
+
+		tutor, err := service.NewTutorService(tutorRepo, service.WithCEPDirectory(directory)).
+			UpdateTutor(ctx, "52998224725", service.TutorPatch{Address: &model.Address{CEP: "01310100", Number: "1000"}})
+		assert.NoError(t, err)
+		assert.Equal(t, "Bela Vista", tutor.Address.Neighborhood)
+		assert.Empty(t, tutor.AddressText)
+	})
+
+	t.Run("looks up CEPs and counts tutors by region", func(t *testing.T) {
+		tutorRepo, tutorService := newService()
+		tutorRepo.On("CountTutorsByRegion", ctx, "SP", "").Return([]repository.TutorRegionCount{{UF: "SP", City: "São Paulo", Neighborhood: "Bela Vista", Tutors: 3}}, nil)
+
+		address, err := tutorService.LookupCEP("01310-100")
+		assert.NoError(t, err)
+		assert.Equal(t, "São Paulo", address.City)
+		_, err = tutorService.LookupCEP("99999-999")
+		assert.ErrorIs(t, err, service.ErrCEPNotFound)
+		_, err = tutorService.LookupCEP("abc")
+		assert.ErrorIs(t, err, service.ErrInvalidCEP)
+
+		regions, err := tutorService.TutorRegions(ctx, "sp", "")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), regions[0].Tutors)
+		_, err = tutorService.TutorRegions(ctx, "XX", "")
+		assert.ErrorIs(t, err, service.ErrInvalidUF)
+	})
+}
+
+func TestMigrateLegacyAddresses(t *testing.T) {
+	ctx := context.Background()
+	directory, err := service.LoadCEPDirectory(strings.NewReader(cepTable))
+	assert.NoError(t, err)
+	newService := func() (*MockTutorRepo, *service.TutorService) {
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("FindTutorsWithLegacyAddress", ctx).Return([]model.Tutor{
+			{CPFTutor: "52998224725", AddressText: "Av. Paulista, nº 1000, apto 12 - CEP 01310-100"},
+			{CPFTutor: "11144477735", AddressText: "Rua das Flores, 10 - Centro - Campinas/SP"},
+		}, nil)
+		return tutorRepo, service.NewTutorService(tutorRepo, service.WithCEPDirectory(directory))
+	}
+
+	t.Run("dry run reports without writing", func(t *testing.T) {
+		tutorRepo, tutorService := newService()
+
+		report, err := tutorService.MigrateLegacyAddresses(ctx, true)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Total)
+		assert.Equal(t, 1, report.Migrated)
+		assert.Equal(t, 1, report.NeedsReview)
+		assert.Equal(t, service.AddressMigrated, report.Tutors[0].Status)
+		assert.Equal(t, model.Address{CEP: "01310100", Street: "Avenida Paulista", Number: "1000", Neighborhood: "Bela Vista", City: "São Paulo", UF: "SP"}, report.Tutors[0].Address)
+		assert.Equal(t, service.AddressNeedsReview, report.Tutors[1].Status)
+		assert.Equal(t, "SP", report.Tutors[1].Address.UF)
+		assert.Equal(t, "10", report.Tutors[1].Address.Number)
+		assert.Equal(t, service.ErrIncompleteAddress.Error(), report.Tutors[1].Message)
+		tutorRepo.AssertNotCalled(t, "UpdateTutor", mock.Anything, mock.Anything)
+	})
+
+	t.Run("saves the complete addresses and keeps the original text", func(t *testing.T) {
+		tutorRepo, tutorService := newService()
+		tutorRepo.On("UpdateTutor", ctx, mock.MatchedBy(func(tutor *model.Tutor) bool {
+			return tutor.CPFTutor == "52998224725" && tutor.Address.CEP == "01310100" && tutor.AddressText != ""
+		})).Return(nil).Once()
+
+		report, err := tutorService.MigrateLegacyAddresses(ctx, false)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Migrated)
+		tutorRepo.AssertExpectations(t)
+	})
+}
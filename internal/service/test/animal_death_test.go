@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecordDeath(t *testing.T) {
+	animalID := uuid.New()
+	now := time.Date(2024, 5, 10, 16, 45, 0, 0, time.UTC)
+	newAnimal := func() *model.Animal {
+		return &model.Animal{ID: animalID, Name: "Rex", Species: "Dog", CPFTutor: "52998224725", BirthDate: &model.CustomDate{Time: day(2015, 1, 1)}}
+	}
+	newRecord := func() model.DeathRecord {
+		return model.DeathRecord{
+			DateOfDeath:     model.CustomDate{Time: day(2024, 5, 9)},
+			Cause:           "Insuficiência renal crônica",
+			Euthanasia:      true,
+			CRVM:            "valid-crvm",
+			BodyDisposition: model.BodyDispositionCremation,
+		}
+	}
+
+	t.Run("should record the death and cancel scheduled consultations from today", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+		animal := newAnimal()
+
+		mockRepo.On("FindAnimalByID", animalID).Return(animal, nil)
+		mockRepo.On("RecordDeath", animal, mock.AnythingOfType("*model.DeathRecord"), mock.MatchedBy(func(history *model.AnimalHistory) bool {
+			return len(history.Changes) == 1 && history.Changes[0].Field == "deceased_at" && history.Changes[0].NewValue == "2024-05-09"
+		}), day(2024, 5, 10)).Return(2, nil)
+
+		record, cancelled, err := animalService.RecordDeath(animalID, newRecord(), "vet-uid", MockGetVeterinaryByCRVM, now)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), cancelled)
+		assert.Equal(t, animalID, record.AnimalID)
+		assert.Equal(t, "vet-uid", record.RecordedBy)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject an animal already deceased", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+		animal := newAnimal()
+		animal.DeceasedAt = &model.CustomDate{Time: day(2024, 1, 1)}
+
+		mockRepo.On("FindAnimalByID", animalID).Return(animal, nil)
+
+		_, _, err := animalService.RecordDeath(animalID, newRecord(), "vet-uid", MockGetVeterinaryByCRVM, now)
+		assert.ErrorIs(t, err, service.ErrAnimalAlreadyDeceased)
+		mockRepo.AssertNotCalled(t, "RecordDeath", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should validate the record", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+		mockRepo.On("FindAnimalByID", animalID).Return(newAnimal(), nil)
+
+		invalidDisposition := newRecord()
+		invalidDisposition.BodyDisposition = "taxidermy"
+		_, _, err := animalService.RecordDeath(animalID, invalidDisposition, "vet-uid", MockGetVeterinaryByCRVM, now)
+		var validationErrs validator.ValidationErrors
+		assert.ErrorAs(t, err, &validationErrs)
+
+		future := newRecord()
+		future.DateOfDeath = model.CustomDate{Time: day(2024, 5, 11)}
+		_, _, err = animalService.RecordDeath(animalID, future, "vet-uid", MockGetVeterinaryByCRVM, now)
+		assert.ErrorIs(t, err, service.ErrFutureDeathDate)
+
+		beforeBirth := newRecord()
+		beforeBirth.DateOfDeath = model.CustomDate{Time: day(2014, 12, 31)}
+		_, _, err = animalService.RecordDeath(animalID, beforeBirth, "vet-uid", MockGetVeterinaryByCRVM, now)
+		assert.ErrorIs(t, err, service.ErrDeathBeforeBirth)
+
+		unknownVet := newRecord()
+		unknownVet.CRVM = "invalid-crvm"
+		_, _, err = animalService.RecordDeath(animalID, unknownVet, "vet-uid", MockGetVeterinaryByCRVM, now)
+		assert.ErrorIs(t, err, service.ErrUnknownVeterinarian)
+
+		mockRepo.AssertNotCalled(t, "RecordDeath", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeceasedAnimalBlocksNewActivity(t *testing.T) {
+	deceased := &model.Animal{ID: uuid.New(), Name: "Rex", DeceasedAt: &model.CustomDate{Time: day(2024, 5, 9)}}
+	getDeceasedAnimal := func(uuid.UUID) (*model.Animal, error) { return deceased, nil }
+
+	t.Run("AddConsultation", func(t *testing.T) {
+		mockRepo := new(MockConsultationRepo)
+		consultation := &model.Consultation{ID: uuid.New(), AnimalID: deceased.ID, CRVM: "valid-crvm", ConsultationDate: model.CustomDate{Time: day(2024, 6, 1)}, ConsultationHour: "10:00"}
+		mockRepo.On("FindConsultationByID", mock.Anything, consultation.ID).Return(nil, nil)
+
+		err := service.AddConsultation(mockRepo, consultation, MockGetVeterinaryByCRVM, getDeceasedAnimal, MockAlwaysAvailable)
+		assert.ErrorIs(t, err, service.ErrAnimalDeceased)
+		mockRepo.AssertNotCalled(t, "SaveConsultation", mock.Anything, mock.Anything)
+	})
+
+	t.Run("AddDosage", func(t *testing.T) {
+		dosageRepo := new(MockDosageRepo)
+		animalRepo := new(repository.MockAnimalRepository)
+		animalRepo.On("FindAnimalByID", deceased.ID).Return(deceased, nil)
+		dosageService := service.NewDosageService(dosageRepo, service.NewClinicalRecordService(new(MockClinicalRepo)), animalRepo, MockGetVeterinaryByCRVM)
+
+		err := dosageService.AddDosage(context.Background(), &model.Dosage{ID: uuid.New(), AnimalID: deceased.ID, MedicationID: uuid.New()})
+		assert.ErrorIs(t, err, service.ErrAnimalDeceased)
+		dosageRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("age stops at the date of death", func(t *testing.T) {
+		animal := model.Animal{BirthDate: &model.CustomDate{Time: day(2015, 1, 1)}, BirthDatePrecision: model.BirthDatePrecisionExact, DeceasedAt: &model.CustomDate{Time: day(2020, 6, 1)}}
+		animal.RefreshAge(day(2024, 6, 1))
+		assert.Equal(t, 5, animal.Age)
+		assert.Equal(t, 5, animal.AgeMonths)
+	})
+}
@@ -0,0 +1,105 @@
+package service_test
+
+import (
+	"testing"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNameSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, service.NameSimilarity("Thor", "thor"))
+	assert.Equal(t, 1.0, service.NameSimilarity("Pipoca  ", "pipóca"))
+	assert.Equal(t, 0.86, service.NameSimilarity("Bolinha", "Bolnha"))
+	assert.GreaterOrEqual(t, service.NameSimilarity("Bolinha", "Bolnha"), service.DuplicateNameSimilarity)
+	assert.Less(t, service.NameSimilarity("Rex", "Mel"), service.DuplicateNameSimilarity)
+}
+
+func TestFindDuplicateCandidates(t *testing.T) {
+	mockRepo := new(repository.MockAnimalRepository)
+	animalService := service.NewAnimalService(mockRepo)
+
+	animal := &model.Animal{ID: uuid.New(), Name: "Bolinha", Species: "Dog", CPFTutor: "12345678909"}
+	typo := model.Animal{ID: uuid.New(), Name: "Bolnha", Species: "dog", CPFTutor: "12345678909"}
+	exact := model.Animal{ID: uuid.New(), Name: "bolinha", Species: "Dog", CPFTutor: "12345678909"}
+	other := model.Animal{ID: uuid.New(), Name: "Rex", Species: "Dog", CPFTutor: "12345678909"}
+
+	mockRepo.On("FindAnimalByID", animal.ID).Return(animal, nil)
+	mockRepo.On("FindAnimalsByTutorAndSpecies", "12345678909", "Dog").Return([]model.Animal{*animal, typo, other, exact}, nil)
+
+	candidates, err := animalService.FindDuplicateCandidates(animal.ID)
+	assert.NoError(t, err)
+	assert.Len(t, candidates, 2)
+	assert.Equal(t, exact.ID, candidates[0].Animal.ID)
+	assert.Equal(t, typo.ID, candidates[1].Animal.ID)
+}
+
+func TestFindDuplicatePairs(t *testing.T) {
+	animals := []model.Animal{
+		{ID: uuid.New(), Name: "Thor", Species: "Dog", CPFTutor: "12345678909"},
+		{ID: uuid.New(), Name: "Tor", Species: "Dog", CPFTutor: "12345678909"},
+		{ID: uuid.New(), Name: "Thor", Species: "Cat", CPFTutor: "12345678909"}, // outra espécie
+		{ID: uuid.New(), Name: "Thor", Species: "Dog", CPFTutor: "52998224725"}, // outro tutor
+		{ID: uuid.New(), Name: "Luna", Species: "Dog", CPFTutor: "12345678909"},
+	}
+
+	pairs := service.FindDuplicatePairs(animals)
+	assert.Len(t, pairs, 1)
+	assert.Equal(t, animals[0].ID, pairs[0].First.ID)
+	assert.Equal(t, animals[1].ID, pairs[0].Second.ID)
+}
+
+func TestMergeAnimals(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		microchip := "076012345678901"
+		target := &model.Animal{ID: uuid.New(), Name: "Bolinha", Species: "Dog", CPFTutor: "12345678909"}
+		source := &model.Animal{ID: uuid.New(), Name: "Bolnha", Species: "Dog", Breed: "Poodle", CPFTutor: "12345678909", Microchip: &microchip}
+
+		mockRepo.On("FindAnimalByID", target.ID).Return(target, nil)
+		mockRepo.On("FindAnimalByID", source.ID).Return(source, nil)
+		mockRepo.On("MergeAnimals", source, target,
+			mock.MatchedBy(func(m *model.AnimalMerge) bool {
+				return m.SourceAnimalID == source.ID && m.TargetAnimalID == target.ID && m.MergedBy == "vet-1"
+			}),
+			mock.MatchedBy(func(h *model.AnimalHistory) bool {
+				return h.AnimalID == target.ID && len(h.Changes) == 3 // breed, microchip e merged_from
+			})).Return(nil)
+
+		merge, err := animalService.MergeAnimals(target.ID, source.ID, "vet-1")
+		assert.NoError(t, err)
+		assert.Equal(t, source.ID, merge.SourceAnimalID)
+		assert.Equal(t, "Poodle", target.Breed)
+		assert.Equal(t, &microchip, target.Microchip)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Same animal", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		id := uuid.New()
+		_, err := animalService.MergeAnimals(id, id, "vet-1")
+		assert.ErrorIs(t, err, service.ErrMergeSameAnimal)
+	})
+
+	t.Run("Different tutors", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		target := &model.Animal{ID: uuid.New(), Name: "Thor", Species: "Dog", CPFTutor: "12345678909"}
+		source := &model.Animal{ID: uuid.New(), Name: "Thor", Species: "Dog", CPFTutor: "52998224725"}
+		mockRepo.On("FindAnimalByID", target.ID).Return(target, nil)
+		mockRepo.On("FindAnimalByID", source.ID).Return(source, nil)
+
+		_, err := animalService.MergeAnimals(target.ID, source.ID, "vet-1")
+		assert.ErrorIs(t, err, service.ErrMergeMismatch)
+		mockRepo.AssertNotCalled(t, "MergeAnimals", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
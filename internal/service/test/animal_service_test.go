@@ -1,15 +1,19 @@
 package service_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 	"vetblock/internal/db/model"
 	"vetblock/internal/db/repository"
 	"vetblock/internal/service"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // Test for AddAnimal
@@ -93,29 +97,36 @@ func TestGetAnimalByID(t *testing.T) {
 // Test for UpdateAnimal
 func TestUpdateAnimal(t *testing.T) {
 	animalID := uuid.New()
-	existingAnimal := &model.Animal{
-		ID:      animalID,
-		Name:    "Rex",
-		Species: "Dog",
+	newExistingAnimal := func() *model.Animal {
+		return &model.Animal{
+			ID:       animalID,
+			Name:     "Rex",
+			Species:  "Dog",
+			Breed:    "SRD",
+			CPFTutor: "12345678909",
+		}
 	}
 
 	updatedAnimal := model.Animal{
-		Name:    "Max",
-		Species: "Dog",
-		Breed:   "Golden Retriever",
-		Age:     3,
+		Name:     "Max",
+		Species:  "Dog",
+		Breed:    "Golden Retriever",
+		Age:      3,
+		CPFTutor: "12345678909",
 	}
 
 	t.Run("should update animal", func(t *testing.T) {
 		mockRepo := new(repository.MockAnimalRepository) // Fresh mock per test
 		animalService := service.NewAnimalService(mockRepo)
+		existingAnimal := newExistingAnimal()
 
 		mockRepo.On("FindAnimalByID", animalID).Return(existingAnimal, nil)
-		mockRepo.On("SaveAnimal", existingAnimal).Return(nil)
+		mockRepo.On("UpdateAnimal", existingAnimal, mock.AnythingOfType("*model.AnimalHistory")).Return(nil)
 
-		err := animalService.UpdateAnimal(animalID, updatedAnimal)
+		result, err := animalService.UpdateAnimal(animalID, updatedAnimal, "vet-uid")
 		assert.NoError(t, err)
 		assert.Equal(t, updatedAnimal.Name, existingAnimal.Name)
+		assert.Equal(t, existingAnimal, result)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -125,7 +136,7 @@ func TestUpdateAnimal(t *testing.T) {
 
 		mockRepo.On("FindAnimalByID", animalID).Return(nil, errors.New("animal not found"))
 
-		err := animalService.UpdateAnimal(animalID, updatedAnimal)
+		_, err := animalService.UpdateAnimal(animalID, updatedAnimal, "vet-uid")
 		assert.EqualError(t, err, "error finding animal to update: animal not found")
 		mockRepo.AssertExpectations(t)
 	})
@@ -133,14 +144,146 @@ func TestUpdateAnimal(t *testing.T) {
 	t.Run("should return error if save fails", func(t *testing.T) {
 		mockRepo := new(repository.MockAnimalRepository) // Fresh mock per test
 		animalService := service.NewAnimalService(mockRepo)
+		existingAnimal := newExistingAnimal()
 
 		mockRepo.On("FindAnimalByID", animalID).Return(existingAnimal, nil)
-		mockRepo.On("SaveAnimal", existingAnimal).Return(errors.New("save failed"))
+		mockRepo.On("UpdateAnimal", existingAnimal, mock.Anything).Return(errors.New("save failed"))
 
-		err := animalService.UpdateAnimal(animalID, updatedAnimal)
+		_, err := animalService.UpdateAnimal(animalID, updatedAnimal, "vet-uid")
 		assert.EqualError(t, err, "error updating animal: save failed")
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("should reject invalid animal", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository) // Fresh mock per test
+		animalService := service.NewAnimalService(mockRepo)
+
+		mockRepo.On("FindAnimalByID", animalID).Return(newExistingAnimal(), nil)
+
+		invalid := updatedAnimal
+		invalid.Name = "R"
+		_, err := animalService.UpdateAnimal(animalID, invalid, "vet-uid")
+
+		var validationErrs validator.ValidationErrors
+		assert.ErrorAs(t, err, &validationErrs)
+		mockRepo.AssertNotCalled(t, "UpdateAnimal", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should keep a known birth date when only the legacy age is sent", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository) // Fresh mock per test
+		animalService := service.NewAnimalService(mockRepo)
+		existingAnimal := newExistingAnimal()
+		existingAnimal.BirthDate = &model.CustomDate{Time: time.Date(2020, 8, 20, 0, 0, 0, 0, time.UTC)}
+		existingAnimal.BirthDatePrecision = model.BirthDatePrecisionExact
+
+		mockRepo.On("FindAnimalByID", animalID).Return(existingAnimal, nil)
+		mockRepo.On("UpdateAnimal", existingAnimal, mock.AnythingOfType("*model.AnimalHistory")).Return(nil)
+
+		result, err := animalService.UpdateAnimal(animalID, updatedAnimal, "vet-uid")
+		assert.NoError(t, err)
+		assert.Equal(t, "2020-08-20", result.BirthDate.String())
+		assert.Equal(t, model.BirthDatePrecisionExact, result.BirthDatePrecision)
+	})
+}
+
+// Test for CustomDate JSON
+func TestCustomDateJSON(t *testing.T) {
+	animal := model.Animal{BirthDate: &model.CustomDate{Time: time.Date(2020, 8, 20, 0, 0, 0, 0, time.UTC)}}
+	data, err := json.Marshal(animal)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"birth_date":"2020-08-20"`)
+
+	var date model.CustomDate
+	assert.NoError(t, json.Unmarshal([]byte(`"2020-08-20"`), &date))
+	assert.Equal(t, "2020-08-20", date.String())
+	assert.NoError(t, json.Unmarshal([]byte(`"2020-08-20T00:00:00Z"`), &date))
+	assert.Equal(t, "2020-08-20", date.String())
+	assert.Error(t, json.Unmarshal([]byte(`"20/08/2020"`), &date))
+}
+
+// Test for PatchAnimal
+func TestPatchAnimal(t *testing.T) {
+	animalID := uuid.New()
+
+	t.Run("should change only the given fields and record history", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository) // Fresh mock per test
+		animalService := service.NewAnimalService(mockRepo)
+		existingAnimal := &model.Animal{
+			ID:       animalID,
+			Name:     "Rex",
+			Species:  "Dog",
+			Breed:    "SRD",
+			Weight:   10,
+			CPFTutor: "12345678909",
+		}
+
+		var saved *model.AnimalHistory
+		mockRepo.On("FindAnimalByID", animalID).Return(existingAnimal, nil)
+		mockRepo.On("UpdateAnimal", existingAnimal, mock.AnythingOfType("*model.AnimalHistory")).
+			Run(func(args mock.Arguments) { saved = args.Get(1).(*model.AnimalHistory) }).
+			Return(nil)
+
+		weight := 12.5
+		breed := "Labrador"
+		result, err := animalService.PatchAnimal(animalID, service.AnimalPatch{Weight: &weight, Breed: &breed}, "vet-uid")
+		assert.NoError(t, err)
+		assert.Equal(t, "Rex", result.Name)
+		assert.Equal(t, 12.5, result.Weight)
+		assert.Equal(t, "Labrador", result.Breed)
+
+		assert.Equal(t, animalID, saved.AnimalID)
+		assert.Equal(t, "vet-uid", saved.ChangedBy)
+		assert.Equal(t, []model.Change{
+			{Field: "breed", OldValue: "SRD", NewValue: "Labrador"},
+			{Field: "weight", OldValue: "10", NewValue: "12.5"},
+		}, saved.Changes)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a tutor change outside of a transfer", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository) // Fresh mock per test
+		animalService := service.NewAnimalService(mockRepo)
+		existingAnimal := &model.Animal{ID: animalID, Name: "Rex", Species: "Dog", Breed: "SRD", CPFTutor: "12345678909"}
+
+		mockRepo.On("FindAnimalByID", animalID).Return(existingAnimal, nil)
+
+		cpf := "52998224725"
+		_, err := animalService.PatchAnimal(animalID, service.AnimalPatch{CPFTutor: &cpf}, "vet-uid")
+		assert.ErrorIs(t, err, service.ErrTutorChangeNotAllowed)
+		mockRepo.AssertNotCalled(t, "UpdateAnimal", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not save when nothing changes", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository) // Fresh mock per test
+		animalService := service.NewAnimalService(mockRepo)
+		existingAnimal := &model.Animal{ID: animalID, Name: "Rex", Species: "Dog", Breed: "SRD", CPFTutor: "12345678909"}
+
+		mockRepo.On("FindAnimalByID", animalID).Return(existingAnimal, nil)
+
+		name := "Rex"
+		_, err := animalService.PatchAnimal(animalID, service.AnimalPatch{Name: &name}, "vet-uid")
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "UpdateAnimal", mock.Anything, mock.Anything)
+	})
+}
+
+// Test for GetAnimalHistory
+func TestGetAnimalHistory(t *testing.T) {
+	animalID := uuid.New()
+	history := []model.AnimalHistory{
+		{AnimalID: animalID, ChangedBy: "vet-uid", Changes: []model.Change{{Field: "name", OldValue: "Rex", NewValue: "Max"}}},
+	}
+
+	mockRepo := new(repository.MockAnimalRepository)
+	animalService := service.NewAnimalService(mockRepo)
+
+	mockRepo.On("FindAnimalByID", animalID).Return(&model.Animal{ID: animalID}, nil)
+	mockRepo.On("FindAnimalHistory", animalID).Return(history, nil)
+
+	result, err := animalService.GetAnimalHistory(animalID)
+	assert.NoError(t, err)
+	assert.Equal(t, history, result)
+	mockRepo.AssertExpectations(t)
 }
 
 // Test for DeleteAnimal
@@ -229,3 +372,119 @@ func TestGetAllAnimals(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+// Test for ListAnimals
+func TestListAnimals(t *testing.T) {
+	page := &repository.AnimalPage{
+		Animals:    []model.Animal{{Name: "Rex", Species: "Dog"}},
+		NextCursor: "next",
+	}
+
+	t.Run("should apply the default page size", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		mockRepo.On("QueryAnimals", repository.AnimalQuery{Species: "Dog", Limit: service.DefaultAnimalPageSize}).Return(page, nil)
+
+		result, err := animalService.ListAnimals(repository.AnimalQuery{Species: "Dog"})
+		assert.NoError(t, err)
+		assert.Equal(t, page, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should cap the page size", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		mockRepo.On("QueryAnimals", repository.AnimalQuery{Limit: service.MaxAnimalPageSize}).Return(page, nil)
+
+		_, err := animalService.ListAnimals(repository.AnimalQuery{Limit: 5000})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject an inverted date range", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+		_, err := animalService.ListAnimals(repository.AnimalQuery{CreatedFrom: &from, CreatedTo: &to})
+		assert.ErrorIs(t, err, service.ErrInvalidDateRange)
+		assert.EqualError(t, err, "created_to must not be before created_from")
+		mockRepo.AssertNotCalled(t, "QueryAnimals", mock.Anything)
+	})
+
+	t.Run("should wrap repository errors", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		mockRepo.On("QueryAnimals", mock.Anything).Return(nil, repository.ErrInvalidCursor)
+
+		_, err := animalService.ListAnimals(repository.AnimalQuery{Cursor: "bogus"})
+		assert.ErrorIs(t, err, repository.ErrInvalidCursor)
+	})
+}
+
+// Test for ResolveBirthDate
+func TestResolveBirthDate(t *testing.T) {
+	now := time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC)
+
+	t.Run("should keep the precision implied by the format", func(t *testing.T) {
+		date, precision, err := service.ResolveBirthDate("2020-03", "", nil, now)
+		assert.NoError(t, err)
+		assert.Equal(t, "2020-03-01", date.String())
+		assert.Equal(t, model.BirthDatePrecisionMonth, precision)
+	})
+
+	t.Run("should estimate the birth date from a legacy age", func(t *testing.T) {
+		age := 3
+		date, precision, err := service.ResolveBirthDate("", "", &age, now)
+		assert.NoError(t, err)
+		assert.Equal(t, "2021-01-01", date.String())
+		assert.Equal(t, model.BirthDatePrecisionEstimated, precision)
+
+		// O mesmo animal enviado outro dia do ano tem a mesma data estimada
+		later, _, err := service.ResolveBirthDate("", "", &age, now.AddDate(0, 4, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, date.String(), later.String())
+	})
+
+	t.Run("should leave the birth date unknown without date or age", func(t *testing.T) {
+		date, precision, err := service.ResolveBirthDate("", "", nil, now)
+		assert.NoError(t, err)
+		assert.Nil(t, date)
+		assert.Empty(t, precision)
+	})
+
+	t.Run("should reject future and malformed dates", func(t *testing.T) {
+		_, _, err := service.ResolveBirthDate("2025-01-01", "", nil, now)
+		assert.EqualError(t, err, "birth date cannot be in the future")
+
+		_, _, err = service.ResolveBirthDate("15/06/2020", "", nil, now)
+		assert.EqualError(t, err, "invalid birth date: 15/06/2020")
+
+		_, _, err = service.ResolveBirthDate("2020-01-01", "roughly", nil, now)
+		assert.EqualError(t, err, "invalid birth date precision: roughly")
+	})
+}
+
+// Test for Animal.AgeAt
+func TestAnimalAgeAt(t *testing.T) {
+	animal := model.Animal{
+		BirthDate:          &model.CustomDate{Time: time.Date(2020, 8, 20, 0, 0, 0, 0, time.UTC)},
+		BirthDatePrecision: model.BirthDatePrecisionExact,
+	}
+
+	age := animal.AgeAt(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, &model.AnimalAge{Years: 3, Months: 9}, age)
+
+	age = animal.AgeAt(time.Date(2024, 8, 20, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, &model.AnimalAge{Years: 4, Months: 0}, age)
+
+	assert.Nil(t, animal.AgeAt(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Nil(t, model.Animal{}.AgeAt(time.Now()))
+
+	animal.BirthDatePrecision = model.BirthDatePrecisionEstimated
+	assert.True(t, animal.AgeAt(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)).Estimated)
+}
@@ -0,0 +1,167 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestRestoreAnimal(t *testing.T) {
+	newDeletedAnimal := func(id uuid.UUID) *model.Animal {
+		return &model.Animal{
+			ID:        id,
+			Name:      "Rex",
+			Species:   "Dog",
+			CPFTutor:  "12345678909",
+			DeletedAt: gorm.DeletedAt{Time: time.Now().Add(-time.Hour), Valid: true},
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		animalID := uuid.New()
+		animal := newDeletedAnimal(animalID)
+		mockRepo.On("FindDeletedAnimalByID", animalID).Return(animal, nil)
+		mockRepo.On("FindAnimalMerges", animalID).Return(nil, nil)
+		mockRepo.On("FindByUniqueAttributes", *animal).Return(nil, nil)
+		mockRepo.On("RestoreAnimal", animal, mock.MatchedBy(func(entry *model.AuditLog) bool {
+			return entry.Action == model.AuditActionRestore && entry.EntityID == animalID && entry.PerformedBy == "vet-1"
+		})).Return(nil)
+
+		restored, err := animalService.RestoreAnimal(animalID, "vet-1")
+		assert.NoError(t, err)
+		assert.Equal(t, animalID, restored.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not in trash", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		animalID := uuid.New()
+		mockRepo.On("FindDeletedAnimalByID", animalID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := animalService.RestoreAnimal(animalID, "vet-1")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Microchip taken by another animal", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		animalID := uuid.New()
+		animal := newDeletedAnimal(animalID)
+		microchip := "076012345678901"
+		animal.Microchip = &microchip
+		mockRepo.On("FindDeletedAnimalByID", animalID).Return(animal, nil)
+		mockRepo.On("FindAnimalMerges", animalID).Return(nil, nil)
+		mockRepo.On("FindAnimalByMicrochip", microchip).Return(&model.Animal{ID: uuid.New()}, nil)
+
+		_, err := animalService.RestoreAnimal(animalID, "vet-1")
+		assert.ErrorIs(t, err, service.ErrMicrochipInUse)
+		mockRepo.AssertNotCalled(t, "RestoreAnimal", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Identical animal already active", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		animalID := uuid.New()
+		animal := newDeletedAnimal(animalID)
+		mockRepo.On("FindDeletedAnimalByID", animalID).Return(animal, nil)
+		mockRepo.On("FindAnimalMerges", animalID).Return(nil, nil)
+		mockRepo.On("FindByUniqueAttributes", *animal).Return(&model.Animal{ID: uuid.New()}, nil)
+
+		_, err := animalService.RestoreAnimal(animalID, "vet-1")
+		assert.ErrorIs(t, err, service.ErrAnimalExists)
+	})
+
+	t.Run("Merged into another animal", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		animalID := uuid.New()
+		mockRepo.On("FindDeletedAnimalByID", animalID).Return(newDeletedAnimal(animalID), nil)
+		mockRepo.On("FindAnimalMerges", animalID).Return([]model.AnimalMerge{
+			{ID: uuid.New(), SourceAnimalID: animalID, TargetAnimalID: uuid.New()},
+		}, nil)
+
+		_, err := animalService.RestoreAnimal(animalID, "vet-1")
+		assert.ErrorIs(t, err, service.ErrAnimalMerged)
+		mockRepo.AssertNotCalled(t, "RestoreAnimal", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Merge target can still be restored", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		animalID := uuid.New()
+		animal := newDeletedAnimal(animalID)
+		mockRepo.On("FindDeletedAnimalByID", animalID).Return(animal, nil)
+		mockRepo.On("FindAnimalMerges", animalID).Return([]model.AnimalMerge{
+			{ID: uuid.New(), SourceAnimalID: uuid.New(), TargetAnimalID: animalID},
+		}, nil)
+		mockRepo.On("FindByUniqueAttributes", *animal).Return(nil, nil)
+		mockRepo.On("RestoreAnimal", animal, mock.Anything).Return(nil)
+
+		_, err := animalService.RestoreAnimal(animalID, "vet-1")
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestPurgeAnimalTrash(t *testing.T) {
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Purges animals deleted before the retention period", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		ids := []uuid.UUID{uuid.New(), uuid.New()}
+		mockRepo.On("PurgeDeletedAnimals", now.Add(-30*24*time.Hour), "system").Return(ids, nil)
+
+		purged, err := animalService.PurgeAnimalTrash(30*24*time.Hour, now, "system")
+		assert.NoError(t, err)
+		assert.Equal(t, ids, purged)
+	})
+
+	t.Run("Rejects an invalid retention", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		_, err := animalService.PurgeAnimalTrash(0, now, "system")
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "PurgeDeletedAnimals", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		mockRepo.On("PurgeDeletedAnimals", mock.Anything, "system").Return(nil, errors.New("db down"))
+
+		_, err := animalService.PurgeAnimalTrash(24*time.Hour, now, "system")
+		assert.Error(t, err)
+	})
+}
+
+func TestAnimalTrashRetention(t *testing.T) {
+	t.Setenv("ANIMAL_TRASH_RETENTION_DAYS", "")
+	assert.Equal(t, service.DefaultAnimalTrashRetention, service.AnimalTrashRetention())
+
+	t.Setenv("ANIMAL_TRASH_RETENTION_DAYS", "90")
+	assert.Equal(t, 90*24*time.Hour, service.AnimalTrashRetention())
+
+	t.Setenv("ANIMAL_TRASH_RETENTION_DAYS", "abc")
+	assert.Equal(t, service.DefaultAnimalTrashRetention, service.AnimalTrashRetention())
+}
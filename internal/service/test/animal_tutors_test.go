@@ -0,0 +1,148 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestAnimalTutors(t *testing.T) {
+	animalID := uuid.New()
+	newAnimal := func() *model.Animal {
+		return &model.Animal{ID: animalID, Name: "Rex", Species: "Dog", CPFTutor: "52998224725"}
+	}
+	newServices := func() (*repository.MockAnimalRepository, *service.AnimalService) {
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("FindTutorByCPF", mock.Anything, "11144477735").Return(&model.Tutor{CPFTutor: "11144477735", Active: true}, nil)
+		tutorRepo.On("FindTutorByCPF", mock.Anything, "12345678909").Return(nil, gorm.ErrRecordNotFound)
+
+		animalRepo := new(repository.MockAnimalRepository)
+		animalRepo.On("FindAnimalByID", animalID).Return(newAnimal(), nil)
+		return animalRepo, service.NewAnimalService(animalRepo, service.WithTutors(service.NewTutorService(tutorRepo)))
+	}
+
+	t.Run("links a co-owner and records the history", func(t *testing.T) {
+		animalRepo, animalService := newServices()
+		animalRepo.On("FindAnimalTutors", animalID).Return([]model.AnimalTutor{}, nil)
+		animalRepo.On("SaveAnimalTutor", mock.AnythingOfType("*model.AnimalTutor"), mock.MatchedBy(func(history *model.AnimalHistory) bool {
+			return history.Changes[0] == model.Change{Field: "tutors", OldValue: "", NewValue: "11144477735:co_owner"}
+		})).Return(nil)
+
+		link, err := animalService.SetAnimalTutor(animalID, "11144477735", model.TutorRoleCoOwner, "vet-uid")
+		assert.NoError(t, err)
+		assert.Equal(t, model.TutorRoleCoOwner, link.Role)
+		assert.Equal(t, "vet-uid", link.AddedBy)
+		animalRepo.AssertExpectations(t)
+	})
+
+	t.Run("changes the role of a linked tutor", func(t *testing.T) {
+		animalRepo, animalService := newServices()
+		existing := model.AnimalTutor{ID: uuid.New(), AnimalID: animalID, CPFTutor: "11144477735", Role: model.TutorRoleCoOwner}
+		animalRepo.On("FindAnimalTutors", animalID).Return([]model.AnimalTutor{existing}, nil)
+		animalRepo.On("SaveAnimalTutor", mock.MatchedBy(func(link *model.AnimalTutor) bool { return link.ID == existing.ID }), mock.Anything).Return(nil)
+
+		link, err := animalService.SetAnimalTutor(animalID, "11144477735", model.TutorRoleEmergencyContact, "vet-uid")
+		assert.NoError(t, err)
+		assert.Equal(t, model.TutorRoleEmergencyContact, link.Role)
+		animalRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects the primary owner role, the primary owner and unknown tutors", func(t *testing.T) {
+		animalRepo, animalService := newServices()
+
+		_, err := animalService.SetAnimalTutor(animalID, "11144477735", model.TutorRolePrimaryOwner, "vet-uid")
+		assert.ErrorIs(t, err, service.ErrInvalidTutorRole)
+
+		_, err = animalService.SetAnimalTutor(animalID, "11144477735", "neighbour", "vet-uid")
+		assert.ErrorIs(t, err, service.ErrInvalidTutorRole)
+
+		_, err = animalService.SetAnimalTutor(animalID, "52998224725", model.TutorRoleCoOwner, "vet-uid")
+		assert.ErrorIs(t, err, service.ErrTutorIsPrimaryOwner)
+
+		_, err = animalService.SetAnimalTutor(animalID, "12345678909", model.TutorRoleCoOwner, "vet-uid")
+		assert.ErrorIs(t, err, service.ErrUnknownTutor)
+		animalRepo.AssertNotCalled(t, "SaveAnimalTutor", mock.Anything, mock.Anything)
+	})
+
+	t.Run("lists the primary owner first and removes links", func(t *testing.T) {
+		animalRepo, animalService := newServices()
+		link := model.AnimalTutor{ID: uuid.New(), AnimalID: animalID, CPFTutor: "11144477735", Role: model.TutorRoleAuthorizedPickup, CreatedAt: time.Now()}
+		animalRepo.On("FindAnimalTutors", animalID).Return([]model.AnimalTutor{link}, nil)
+		animalRepo.On("DeleteAnimalTutor", mock.MatchedBy(func(l *model.AnimalTutor) bool { return l.ID == link.ID }), mock.Anything).Return(nil)
+
+		tutors, err := animalService.ListAnimalTutors(animalID)
+		assert.NoError(t, err)
+		assert.Len(t, tutors, 2)
+		assert.Equal(t, service.AnimalTutorLink{CPFTutor: "52998224725", Role: model.TutorRolePrimaryOwner}, tutors[0])
+		assert.Equal(t, model.TutorRoleAuthorizedPickup, tutors[1].Role)
+
+		assert.NoError(t, animalService.RemoveAnimalTutor(animalID, "11144477735", "vet-uid"))
+		assert.ErrorIs(t, animalService.RemoveAnimalTutor(animalID, "12345678909", "vet-uid"), service.ErrAnimalTutorNotFound)
+		assert.ErrorIs(t, animalService.RemoveAnimalTutor(animalID, "52998224725", "vet-uid"), service.ErrTutorIsPrimaryOwner)
+	})
+
+	t.Run("rejects an unknown role filter when listing animals", func(t *testing.T) {
+		_, animalService := newServices()
+
+		_, err := animalService.ListAnimals(repository.AnimalQuery{CPFTutor: "11144477735", TutorRole: "neighbour"})
+		assert.ErrorIs(t, err, service.ErrInvalidTutorRole)
+	})
+}
+
+func TestAuthorizeProcedure(t *testing.T) {
+	animal := &model.Animal{ID: uuid.New(), CPFTutor: "52998224725"}
+	getAnimal := func(uuid.UUID) (*model.Animal, error) { return animal, nil }
+
+	assert.NoError(t, service.AuthorizeProcedure(animal, "52998224725"))
+
+	t.Run("consultations authorized by a co-owner are rejected", func(t *testing.T) {
+		mockRepo := new(MockConsultationRepo)
+		consultation := &model.Consultation{ID: uuid.New(), AnimalID: animal.ID, CRVM: "valid-crvm", AuthorizedBy: "11144477735"}
+		mockRepo.On("FindConsultationByID", mock.Anything, consultation.ID).Return(nil, nil)
+
+		err := service.AddConsultation(mockRepo, consultation, MockGetVeterinaryByCRVM, getAnimal, MockAlwaysAvailable)
+		assert.ErrorIs(t, err, service.ErrNotPrimaryOwner)
+		mockRepo.AssertNotCalled(t, "SaveConsultation", mock.Anything, mock.Anything)
+	})
+
+	t.Run("consultations without authorized_by are authorized by the primary owner", func(t *testing.T) {
+		mockRepo := new(MockConsultationRepo)
+		consultation := &model.Consultation{
+			ID:               uuid.New(),
+			AnimalID:         animal.ID,
+			CRVM:             "valid-crvm",
+			ConsultationDate: model.CustomDate{Time: day(2024, 5, 6)},
+			ConsultationHour: "10:00",
+		}
+		mockRepo.On("FindConsultationByID", mock.Anything, consultation.ID).Return(nil, nil)
+		mockRepo.On("FindConsultationByDate", mock.Anything, "2024-05-06").Return([]model.Consultation{}, nil)
+		mockRepo.On("SaveConsultation", mock.Anything, consultation).Return(nil)
+
+		assert.NoError(t, service.AddConsultation(mockRepo, consultation, MockGetVeterinaryByCRVM, getAnimal, MockAlwaysAvailable))
+		assert.Equal(t, "52998224725", consultation.AuthorizedBy)
+	})
+
+	t.Run("euthanasia authorized by a co-owner is rejected", func(t *testing.T) {
+		animalRepo := new(repository.MockAnimalRepository)
+		animalRepo.On("FindAnimalByID", animal.ID).Return(animal, nil)
+		animalService := service.NewAnimalService(animalRepo)
+
+		_, _, err := animalService.RecordDeath(animal.ID, model.DeathRecord{
+			DateOfDeath:     model.CustomDate{Time: day(2024, 5, 9)},
+			Cause:           "Insuficiência renal crônica",
+			Euthanasia:      true,
+			CRVM:            "valid-crvm",
+			BodyDisposition: model.BodyDispositionCremation,
+			AuthorizedBy:    "11144477735",
+		}, "vet-uid", MockGetVeterinaryByCRVM, day(2024, 5, 10))
+		assert.ErrorIs(t, err, service.ErrNotPrimaryOwner)
+		animalRepo.AssertNotCalled(t, "RecordDeath", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
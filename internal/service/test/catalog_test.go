@@ -0,0 +1,162 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock do repositório do catálogo de espécies
+type MockCatalogRepo struct {
+	mock.Mock
+}
+
+var _ repository.CatalogRepository = (*MockCatalogRepo)(nil)
+
+func (m *MockCatalogRepo) ListSpecies(ctx context.Context) ([]model.Species, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Species), args.Error(1)
+}
+
+func (m *MockCatalogRepo) FindSpeciesByID(ctx context.Context, id uuid.UUID) (*model.Species, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Species), args.Error(1)
+}
+
+func (m *MockCatalogRepo) SaveSpecies(ctx context.Context, species *model.Species) error {
+	args := m.Called(ctx, species)
+	return args.Error(0)
+}
+
+func (m *MockCatalogRepo) DeleteSpecies(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCatalogRepo) FindBreedByID(ctx context.Context, id uuid.UUID) (*model.Breed, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Breed), args.Error(1)
+}
+
+func (m *MockCatalogRepo) SaveBreed(ctx context.Context, breed *model.Breed) error {
+	args := m.Called(ctx, breed)
+	return args.Error(0)
+}
+
+func (m *MockCatalogRepo) DeleteBreed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func testCatalog() []model.Species {
+	dogID := uuid.New()
+	return []model.Species{
+		{ID: dogID, Code: "dog", NamePT: "Cão", NameEN: "Dog", Aliases: []string{"cachorro", "canino", "canine"}, Breeds: []model.Breed{
+			{ID: uuid.New(), SpeciesID: dogID, Code: "mixed", NamePT: "Sem raça definida (SRD)", NameEN: "Mixed breed", Aliases: []string{"srd", "vira-lata"}},
+			{ID: uuid.New(), SpeciesID: dogID, Code: "labrador_retriever", NamePT: "Labrador", NameEN: "Labrador Retriever"},
+		}},
+		{ID: uuid.New(), Code: "cat", NamePT: "Gato", NameEN: "Cat", Aliases: []string{"felino"}},
+	}
+}
+
+func TestResolveSpecies(t *testing.T) {
+	mockRepo := new(MockCatalogRepo)
+	catalogService := service.NewCatalogService(mockRepo)
+	mockRepo.On("ListSpecies", mock.Anything).Return(testCatalog(), nil)
+
+	for _, name := range []string{"Dog", "dog", "cão", "Cao", "Cachorro", " CANINE "} {
+		species, err := catalogService.ResolveSpecies(context.Background(), name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, "Dog", species.NameEN, name)
+	}
+
+	_, err := catalogService.ResolveSpecies(context.Background(), "Dinossauro")
+	assert.ErrorIs(t, err, service.ErrUnknownSpecies)
+}
+
+func TestAddAnimalWithCatalog(t *testing.T) {
+	newAnimal := func(species, breed string) model.Animal {
+		return model.Animal{Name: "Rex", Species: species, Breed: breed, CPFTutor: "12345678909"}
+	}
+
+	t.Run("Maps species and breed to the catalog", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		catalogRepo := new(MockCatalogRepo)
+		animalService := service.NewAnimalService(mockRepo, service.WithCatalog(service.NewCatalogService(catalogRepo)))
+
+		catalogRepo.On("ListSpecies", mock.Anything).Return(testCatalog(), nil)
+		mockRepo.On("FindByUniqueAttributes", newAnimal("Dog", "Mixed breed")).Return(nil, nil)
+		mockRepo.On("SaveAnimal", mock.MatchedBy(func(a *model.Animal) bool {
+			return a.Species == "Dog" && a.Breed == "Mixed breed"
+		})).Return(nil)
+
+		err := animalService.AddAnimal(newAnimal("cachorro", "vira-lata"))
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects an unknown species", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		catalogRepo := new(MockCatalogRepo)
+		animalService := service.NewAnimalService(mockRepo, service.WithCatalog(service.NewCatalogService(catalogRepo)))
+
+		catalogRepo.On("ListSpecies", mock.Anything).Return(testCatalog(), nil)
+
+		err := animalService.AddAnimal(newAnimal("Dragão", "Mixed breed"))
+		assert.ErrorIs(t, err, service.ErrUnknownSpecies)
+		mockRepo.AssertNotCalled(t, "SaveAnimal", mock.Anything)
+	})
+
+	t.Run("Rejects a breed of another species", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		catalogRepo := new(MockCatalogRepo)
+		animalService := service.NewAnimalService(mockRepo, service.WithCatalog(service.NewCatalogService(catalogRepo)))
+
+		catalogRepo.On("ListSpecies", mock.Anything).Return(testCatalog(), nil)
+
+		err := animalService.AddAnimal(newAnimal("Gato", "Labrador"))
+		assert.ErrorIs(t, err, service.ErrUnknownBreed)
+	})
+}
+
+func TestCreateSpecies(t *testing.T) {
+	t.Run("Generates the code from the English name", func(t *testing.T) {
+		mockRepo := new(MockCatalogRepo)
+		catalogService := service.NewCatalogService(mockRepo)
+
+		mockRepo.On("ListSpecies", mock.Anything).Return(testCatalog(), nil)
+		mockRepo.On("SaveSpecies", mock.Anything, mock.AnythingOfType("*model.Species")).Return(nil)
+
+		species := &model.Species{NamePT: "Porquinho-da-índia", NameEN: "Guinea Pig"}
+		err := catalogService.CreateSpecies(context.Background(), species)
+		assert.NoError(t, err)
+		assert.Equal(t, "guinea_pig", species.Code)
+		assert.NotEqual(t, uuid.Nil, species.ID)
+	})
+
+	t.Run("Rejects a name already used by another species", func(t *testing.T) {
+		mockRepo := new(MockCatalogRepo)
+		catalogService := service.NewCatalogService(mockRepo)
+
+		mockRepo.On("ListSpecies", mock.Anything).Return(testCatalog(), nil)
+
+		err := catalogService.CreateSpecies(context.Background(), &model.Species{NamePT: "Canino", NameEN: "Canine"})
+		assert.ErrorIs(t, err, service.ErrCatalogConflict)
+		mockRepo.AssertNotCalled(t, "SaveSpecies", mock.Anything, mock.Anything)
+	})
+}
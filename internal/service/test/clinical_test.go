@@ -0,0 +1,150 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock do repositório de alergias e condições crônicas
+type MockClinicalRepo struct {
+	mock.Mock
+}
+
+var _ repository.ClinicalRecordRepository = (*MockClinicalRepo)(nil)
+
+func (m *MockClinicalRepo) CreateAllergy(ctx context.Context, allergy *model.Allergy) error {
+	args := m.Called(ctx, allergy)
+	return args.Error(0)
+}
+
+func (m *MockClinicalRepo) FindAllergiesByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.Allergy, error) {
+	args := m.Called(ctx, animalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Allergy), args.Error(1)
+}
+
+func (m *MockClinicalRepo) DeleteAllergy(ctx context.Context, animalID, allergyID uuid.UUID) error {
+	args := m.Called(ctx, animalID, allergyID)
+	return args.Error(0)
+}
+
+func (m *MockClinicalRepo) CreateCondition(ctx context.Context, condition *model.ChronicCondition) error {
+	args := m.Called(ctx, condition)
+	return args.Error(0)
+}
+
+func (m *MockClinicalRepo) FindConditionsByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.ChronicCondition, error) {
+	args := m.Called(ctx, animalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ChronicCondition), args.Error(1)
+}
+
+func (m *MockClinicalRepo) DeleteCondition(ctx context.Context, animalID, conditionID uuid.UUID) error {
+	args := m.Called(ctx, animalID, conditionID)
+	return args.Error(0)
+}
+
+func TestFindContraindications(t *testing.T) {
+	allergies := []model.Allergy{
+		{ID: uuid.New(), Allergen: "Penicilinas", ActivePrinciples: []string{"Amoxicilina", "Penicilina G"}, Severity: model.SeveritySevere},
+	}
+	conditions := []model.ChronicCondition{
+		{ID: uuid.New(), Name: "Doença renal crônica", ActivePrinciples: []string{"meloxicam"}, Severity: model.SeverityModerate},
+		{ID: uuid.New(), Name: "Epilepsia", Severity: model.SeverityMild},
+	}
+
+	found := service.FindContraindications([]string{"amoxicilina ", "Clavulanato de potássio", "Meloxicam"}, allergies, conditions)
+	assert.Len(t, found, 2)
+	assert.Equal(t, service.ContraindicationAllergy, found[0].Kind)
+	assert.Equal(t, "Penicilinas", found[0].Name)
+	assert.Equal(t, "amoxicilina ", found[0].ActivePrinciple)
+	assert.Equal(t, service.ContraindicationCondition, found[1].Kind)
+	assert.Equal(t, conditions[0].ID, found[1].RecordID)
+
+	assert.Empty(t, service.FindContraindications([]string{"Dipirona"}, allergies, conditions))
+}
+
+func TestEnforceContraindications(t *testing.T) {
+	animalID := uuid.New()
+	medication := &model.Medication{ID: uuid.New(), Name: "Agemoxi", ActivePrinciples: []string{"Amoxicilina"}}
+	allergies := []model.Allergy{
+		{ID: uuid.New(), AnimalID: animalID, Allergen: "Penicilinas", ActivePrinciples: []string{"amoxicilina"}, Severity: model.SeveritySevere},
+	}
+
+	newService := func(allergies []model.Allergy) *service.ClinicalRecordService {
+		mockRepo := new(MockClinicalRepo)
+		mockRepo.On("FindAllergiesByAnimalID", mock.Anything, animalID).Return(allergies, nil)
+		mockRepo.On("FindConditionsByAnimalID", mock.Anything, animalID).Return([]model.ChronicCondition{}, nil)
+		return service.NewClinicalRecordService(mockRepo)
+	}
+
+	t.Run("Refuses a contraindicated medication", func(t *testing.T) {
+		dosage := &model.Dosage{AnimalID: animalID, MedicationID: medication.ID}
+
+		err := newService(allergies).EnforceContraindications(context.Background(), dosage, medication)
+		assert.ErrorIs(t, err, service.ErrContraindicated)
+		var contraindicationErr *service.ContraindicationError
+		assert.ErrorAs(t, err, &contraindicationErr)
+		assert.Len(t, contraindicationErr.Contraindications, 1)
+	})
+
+	t.Run("Refuses a too short override reason", func(t *testing.T) {
+		dosage := &model.Dosage{AnimalID: animalID, MedicationID: medication.ID, OverrideReason: "  ok  "}
+
+		err := newService(allergies).EnforceContraindications(context.Background(), dosage, medication)
+		assert.ErrorIs(t, err, service.ErrContraindicated)
+	})
+
+	t.Run("Accepts an explicit override reason", func(t *testing.T) {
+		dosage := &model.Dosage{AnimalID: animalID, MedicationID: medication.ID, OverrideReason: "Sem alternativa disponível, teste de sensibilidade feito", OverriddenBy: "vet-1"}
+
+		err := newService(allergies).EnforceContraindications(context.Background(), dosage, medication)
+		assert.NoError(t, err)
+		assert.Equal(t, "vet-1", dosage.OverriddenBy)
+	})
+
+	t.Run("Clears the override when nothing matches", func(t *testing.T) {
+		dosage := &model.Dosage{AnimalID: animalID, MedicationID: medication.ID, OverrideReason: "Justificativa desnecessária", OverriddenBy: "vet-1"}
+
+		err := newService(nil).EnforceContraindications(context.Background(), dosage, medication)
+		assert.NoError(t, err)
+		assert.Empty(t, dosage.OverrideReason)
+		assert.Empty(t, dosage.OverriddenBy)
+	})
+}
+
+func TestAddAllergy(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockClinicalRepo)
+		clinicalService := service.NewClinicalRecordService(mockRepo)
+		mockRepo.On("CreateAllergy", mock.Anything, mock.AnythingOfType("*model.Allergy")).Return(nil)
+
+		allergy := &model.Allergy{AnimalID: uuid.New(), Allergen: "Sulfas", ActivePrinciples: []string{" Sulfametoxazol ", ""}, Severity: model.SeverityModerate}
+		err := clinicalService.AddAllergy(context.Background(), allergy)
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, allergy.ID)
+		assert.Equal(t, []string{"Sulfametoxazol"}, []string(allergy.ActivePrinciples))
+	})
+
+	t.Run("Requires severity and active principles", func(t *testing.T) {
+		mockRepo := new(MockClinicalRepo)
+		clinicalService := service.NewClinicalRecordService(mockRepo)
+
+		err := clinicalService.AddAllergy(context.Background(), &model.Allergy{AnimalID: uuid.New(), Allergen: "Sulfas", Severity: "fatal"})
+		var validationErrs validator.ValidationErrors
+		assert.ErrorAs(t, err, &validationErrs)
+		mockRepo.AssertNotCalled(t, "CreateAllergy", mock.Anything, mock.Anything)
+	})
+}
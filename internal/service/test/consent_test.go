@@ -0,0 +1,158 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock do repositório de consentimentos
+type MockConsentRepo struct {
+	mock.Mock
+}
+
+func (m *MockConsentRepo) FindConsentsByTutor(ctx context.Context, cpf string) ([]model.TutorConsent, error) {
+	args := m.Called(ctx, cpf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TutorConsent), args.Error(1)
+}
+
+func (m *MockConsentRepo) SaveConsent(ctx context.Context, consent *model.TutorConsent) error {
+	args := m.Called(ctx, consent)
+	return args.Error(0)
+}
+
+// Mock de um canal de envio de mensagens
+type MockNotificationSender struct {
+	mock.Mock
+}
+
+func (m *MockNotificationSender) Send(ctx context.Context, tutor *model.Tutor, notification service.Notification) error {
+	args := m.Called(ctx, tutor, notification)
+	return args.Error(0)
+}
+
+func TestConsentService(t *testing.T) {
+	ctx := context.Background()
+	cpf := "52998224725"
+	now := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+	revokedAt := now.AddDate(0, -1, 0)
+	history := []model.TutorConsent{
+		{ID: uuid.New(), CPFTutor: cpf, Channel: model.ConsentChannelSMS, Purpose: model.ConsentPurposeMarketing, GrantedAt: now.AddDate(-1, 0, 0), Source: model.ConsentSourceForm, RevokedAt: &revokedAt},
+		{ID: uuid.New(), CPFTutor: cpf, Channel: model.ConsentChannelWhatsApp, Purpose: model.ConsentPurposeClinicalReminders, GrantedAt: now.AddDate(0, -2, 0), Source: model.ConsentSourceReception},
+	}
+	newService := func() (*MockConsentRepo, *MockTutorRepo, *service.ConsentService) {
+		consentRepo := new(MockConsentRepo)
+		consentRepo.On("FindConsentsByTutor", ctx, cpf).Return(append([]model.TutorConsent{}, history...), nil)
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("FindTutorByCPF", ctx, cpf).Return(&model.Tutor{CPFTutor: cpf, Active: true}, nil)
+		return consentRepo, tutorRepo, service.NewConsentService(consentRepo, tutorRepo)
+	}
+
+	t.Run("returns the current state of every channel and the history", func(t *testing.T) {
+		_, _, consentService := newService()
+
+		state, err := consentService.GetConsents(ctx, cpf)
+		assert.NoError(t, err)
+		assert.Len(t, state.History, 2)
+		assert.Len(t, state.Current, len(service.ConsentChannels)*len(service.ConsentPurposes))
+		for _, status := range state.Current {
+			granted := status.Channel == model.ConsentChannelWhatsApp && status.Purpose == model.ConsentPurposeClinicalReminders
+			assert.Equal(t, granted, status.Granted, "%s/%s", status.Channel, status.Purpose)
+		}
+	})
+
+	t.Run("grants a new consent after a revocation", func(t *testing.T) {
+		consentRepo, _, consentService := newService()
+		consentRepo.On("SaveConsent", ctx, mock.AnythingOfType("*model.TutorConsent")).Return(nil)
+
+		consent, err := consentService.GrantConsent(ctx, cpf, model.ConsentChannelSMS, model.ConsentPurposeMarketing, model.ConsentSourcePhone, "vet-uid", now)
+		assert.NoError(t, err)
+		assert.NotEqual(t, history[0].ID, consent.ID)
+		assert.Equal(t, now, consent.GrantedAt)
+		assert.True(t, consent.Active())
+		consentRepo.AssertExpectations(t)
+	})
+
+	t.Run("keeps the consent already in force", func(t *testing.T) {
+		consentRepo, _, consentService := newService()
+
+		consent, err := consentService.GrantConsent(ctx, cpf, model.ConsentChannelWhatsApp, model.ConsentPurposeClinicalReminders, model.ConsentSourcePortal, "tutor-uid", now)
+		assert.NoError(t, err)
+		assert.Equal(t, history[1].ID, consent.ID)
+		consentRepo.AssertNotCalled(t, "SaveConsent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects unknown channels, purposes and sources", func(t *testing.T) {
+		_, _, consentService := newService()
+
+		_, err := consentService.GrantConsent(ctx, cpf, "pigeon", model.ConsentPurposeMarketing, model.ConsentSourcePhone, "vet-uid", now)
+		assert.ErrorIs(t, err, service.ErrInvalidConsent)
+		_, err = consentService.GrantConsent(ctx, cpf, model.ConsentChannelEmail, model.ConsentPurposeMarketing, "rumour", "vet-uid", now)
+		assert.ErrorIs(t, err, service.ErrInvalidConsent)
+	})
+
+	t.Run("revokes the consent in force", func(t *testing.T) {
+		consentRepo, _, consentService := newService()
+		consentRepo.On("SaveConsent", ctx, mock.MatchedBy(func(consent *model.TutorConsent) bool {
+			return consent.ID == history[1].ID && consent.RevokedAt != nil && consent.RevocationSource == model.ConsentSourcePortal
+		})).Return(nil)
+
+		consent, err := consentService.RevokeConsent(ctx, cpf, model.ConsentChannelWhatsApp, model.ConsentPurposeClinicalReminders, model.ConsentSourcePortal, "tutor-uid", now)
+		assert.NoError(t, err)
+		assert.False(t, consent.Active())
+
+		_, err = consentService.RevokeConsent(ctx, cpf, model.ConsentChannelSMS, model.ConsentPurposeMarketing, model.ConsentSourcePortal, "tutor-uid", now)
+		assert.ErrorIs(t, err, service.ErrNoConsent)
+	})
+}
+
+func TestNotificationService(t *testing.T) {
+	ctx := context.Background()
+	cpf := "52998224725"
+	consentRepo := new(MockConsentRepo)
+	consentRepo.On("FindConsentsByTutor", ctx, cpf).Return([]model.TutorConsent{
+		{ID: uuid.New(), CPFTutor: cpf, Channel: model.ConsentChannelWhatsApp, Purpose: model.ConsentPurposeClinicalReminders, Source: model.ConsentSourceReception},
+	}, nil)
+	tutorRepo := new(MockTutorRepo)
+	tutor := &model.Tutor{CPFTutor: cpf, Active: true}
+	tutorRepo.On("FindTutorByCPF", ctx, cpf).Return(tutor, nil)
+
+	whatsapp := new(MockNotificationSender)
+	sms := new(MockNotificationSender)
+	notifications := service.NewNotificationService(service.NewConsentService(consentRepo, tutorRepo), map[string]service.NotificationSender{
+		model.ConsentChannelWhatsApp: whatsapp,
+		model.ConsentChannelSMS:      sms,
+	})
+
+	reminder := service.Notification{CPFTutor: cpf, Channel: model.ConsentChannelWhatsApp, Purpose: model.ConsentPurposeClinicalReminders, Message: "Vacina do Rex vence amanhã"}
+	whatsapp.On("Send", ctx, tutor, reminder).Return(nil)
+	assert.NoError(t, notifications.Notify(ctx, reminder))
+	whatsapp.AssertExpectations(t)
+
+	t.Run("does not send without consent for the channel and purpose", func(t *testing.T) {
+		err := notifications.Notify(ctx, service.Notification{CPFTutor: cpf, Channel: model.ConsentChannelSMS, Purpose: model.ConsentPurposeClinicalReminders})
+		assert.ErrorIs(t, err, service.ErrNoConsent)
+		err = notifications.Notify(ctx, service.Notification{CPFTutor: cpf, Channel: model.ConsentChannelWhatsApp, Purpose: model.ConsentPurposeMarketing})
+		assert.ErrorIs(t, err, service.ErrNoConsent)
+		sms.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reports sender failures", func(t *testing.T) {
+		failing := new(MockNotificationSender)
+		failing.On("Send", ctx, tutor, reminder).Return(errors.New("gateway indisponível"))
+		notifications := service.NewNotificationService(service.NewConsentService(consentRepo, tutorRepo), map[string]service.NotificationSender{
+			model.ConsentChannelWhatsApp: failing,
+		})
+		assert.Error(t, notifications.Notify(ctx, reminder))
+	})
+}
@@ -48,6 +48,11 @@ func (m *MockConsultationRepo) FindConsultationByVeterinaryCRVM(ctx context.Cont
 	return args.Get(0).([]model.Consultation), args.Error(1)
 }
 
+func (m *MockConsultationRepo) FindConsultationByVeterinaryCRVMAndDateRange(ctx context.Context, crvm, startDate, endDate string) ([]model.Consultation, error) {
+	args := m.Called(ctx, crvm, startDate, endDate)
+	return args.Get(0).([]model.Consultation), args.Error(1)
+}
+
 // Adicionando outros métodos da interface caso necessário
 // Para que a interface seja cumprida, todos os métodos são mockados, mesmo que não utilizados diretamente no teste.
 
@@ -83,6 +88,11 @@ func MockGetVeterinaryByCRVM(crvm string) (*model.Veterinary, error) {
 	return nil, errors.New("veterinário não encontrado")
 }
 
+// MockAlwaysAvailable aceita qualquer horário; a disponibilidade é testada em schedule_test.go
+func MockAlwaysAvailable(crvm string, start time.Time) error {
+	return nil
+}
+
 func MockGetAnimalByID(id uuid.UUID) (*model.Animal, error) {
 	if id == uuid.Nil {
 		return nil, errors.New("animal não encontrado")
@@ -114,14 +124,26 @@ func TestAddConsultation(t *testing.T) {
 		mockRepo.On("SaveConsultation", mock.Anything, consultation).Return(nil)
 	
 		// Chamada do serviço para adicionar a consulta
-		err := service.AddConsultation(mockRepo, consultation, MockGetVeterinaryByCRVM, MockGetAnimalByID)
+		err := service.AddConsultation(mockRepo, consultation, MockGetVeterinaryByCRVM, MockGetAnimalByID, MockAlwaysAvailable)
 	
 		// Verificar que nenhum erro foi retornado
 		assert.NoError(t, err)
+		assert.Equal(t, model.ConsultationScheduled, consultation.ConsultationStatus)
 	
 		// Verificar que as expectativas do mock foram cumpridas
 		mockRepo.AssertExpectations(t)
 	})
+
+	// Consultas novas não podem nascer concluídas, canceladas ou com falta
+	t.Run("Consulta criada com outra situação", func(t *testing.T) {
+		for _, status := range []string{model.ConsultationCompleted, model.ConsultationCanceled, model.ConsultationNoShow} {
+			consultation := &model.Consultation{ID: uuid.New(), CRVM: "valid-crvm", ConsultationStatus: status}
+
+			err := service.AddConsultation(mockRepo, consultation, MockGetVeterinaryByCRVM, MockGetAnimalByID, MockAlwaysAvailable)
+			assert.ErrorIs(t, err, service.ErrConsultationNotScheduled)
+			mockRepo.AssertNotCalled(t, "SaveConsultation", mock.Anything, consultation)
+		}
+	})
 	
 	
 
@@ -131,7 +153,7 @@ func TestAddConsultation(t *testing.T) {
 
 		mockRepo.On("FindConsultationByID", mock.Anything, consultation.ID).Return(consultation, nil)
 
-		err := service.AddConsultation(mockRepo, consultation, MockGetVeterinaryByCRVM, MockGetAnimalByID)
+		err := service.AddConsultation(mockRepo, consultation, MockGetVeterinaryByCRVM, MockGetAnimalByID, MockAlwaysAvailable)
 		assert.EqualError(t, err, "consulta já existe")
 	})
 
@@ -146,7 +168,7 @@ func TestAddConsultation(t *testing.T) {
 		// O veterinário não será encontrado, então SaveConsultation não deve ser chamado
 		mockRepo.On("FindConsultationByID", mock.Anything, consultation.ID).Return(nil, nil)
 
-		err := service.AddConsultation(mockRepo, consultation, MockGetVeterinaryByCRVM, MockGetAnimalByID)
+		err := service.AddConsultation(mockRepo, consultation, MockGetVeterinaryByCRVM, MockGetAnimalByID, MockAlwaysAvailable)
 		assert.EqualError(t, err, "veterinário não encontrado")
 
 		// Verifica que SaveConsultation NÃO foi chamado
@@ -327,3 +349,34 @@ func TestDeleteConsultation(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestAnnotateAnimalAge(t *testing.T) {
+	animalID := uuid.New()
+	animal := &model.Animal{
+		ID:                 animalID,
+		BirthDate:          &model.CustomDate{Time: time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC)},
+		BirthDatePrecision: model.BirthDatePrecisionExact,
+	}
+
+	calls := 0
+	getAnimal := func(id uuid.UUID) (*model.Animal, error) {
+		calls++
+		if id == animalID {
+			return animal, nil
+		}
+		return nil, errors.New("animal não encontrado")
+	}
+
+	consultations := []model.Consultation{
+		{AnimalID: animalID, ConsultationDate: model.CustomDate{Time: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)}},
+		{AnimalID: animalID, ConsultationDate: model.CustomDate{Time: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)}},
+		{AnimalID: uuid.New(), ConsultationDate: model.CustomDate{Time: time.Now()}},
+	}
+
+	service.AnnotateAnimalAge(consultations, getAnimal)
+
+	assert.Equal(t, &model.AnimalAge{Years: 1, Months: 1}, consultations[0].AnimalAge)
+	assert.Equal(t, &model.AnimalAge{Years: 2, Months: 0}, consultations[1].AnimalAge)
+	assert.Nil(t, consultations[2].AnimalAge)
+	assert.Equal(t, 2, calls) // o animal repetido é buscado uma única vez
+}
@@ -0,0 +1,172 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const legacyCSV = "\xef\xbb\xbfCPF;Nome do Tutor;E-mail;Telefone;Endereço;Nome do Animal;Espécie;Raça;Nascimento;Peso\n" +
+	"529.982.247-25;Maria Souza;maria@example.com;11987654321;Rua das Flores, 10;Rex;Dog;Labrador;15/03/2019;32,5\n" +
+	"529.982.247-25;Maria Souza;maria@example.com;11987654321;Rua das Flores, 10;Mia;Cat;Siamese;2020;\n" +
+	"529.982.247-25;Maria Souza;maria@example.com;11987654321;Rua das Flores, 10;Mia;Cat;Siamese;2020;\n" +
+	"111.111.111-11;João;joao@example.com;11912345678;Rua B, 20;Thor;Dog;Poodle;;\n" +
+	"111.444.777-35;Ana Lima;ana@example.com;11955554444;Av. Central, 300;Bidu;Dog;Beagle;;\n" +
+	"123.456.789-09;Carlos;carlos@example.com;11933332222;Rua C, 30;;;;;\n"
+
+func newImportService() (*MockTutorRepo, *repository.MockAnimalRepository, *service.ImportService) {
+	tutorRepo := new(MockTutorRepo)
+	animalRepo := new(repository.MockAnimalRepository)
+
+	tutorRepo.On("FindTutorByCPF", mock.Anything, "52998224725").Return(nil, gorm.ErrRecordNotFound)
+	tutorRepo.On("FindTutorByCPF", mock.Anything, "11144477735").Return(&model.Tutor{CPFTutor: "11144477735", Active: true}, nil)
+	tutorRepo.On("FindTutorByCPF", mock.Anything, "12345678909").Return(&model.Tutor{CPFTutor: "12345678909", Active: true}, nil)
+
+	animalRepo.On("FindByUniqueAttributes", mock.MatchedBy(func(a model.Animal) bool { return a.Name == "Bidu" })).Return(&model.Animal{Name: "Bidu"}, nil)
+	animalRepo.On("FindByUniqueAttributes", mock.Anything).Return(nil, nil)
+
+	return tutorRepo, animalRepo, service.NewImportService(tutorRepo, service.NewAnimalService(animalRepo))
+}
+
+func TestImportCSV(t *testing.T) {
+	now := day(2024, 6, 1)
+
+	t.Run("dry run reports every row without writing", func(t *testing.T) {
+		tutorRepo, animalRepo, importService := newImportService()
+
+		report, err := importService.ImportCSV(context.Background(), strings.NewReader(legacyCSV), service.ImportOptions{DryRun: true}, now)
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 6, report.Total)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 3, report.Skipped)
+		assert.Equal(t, 1, report.Failed)
+
+		statuses := make([]string, 0, len(report.Rows))
+		for _, row := range report.Rows {
+			statuses = append(statuses, row.Status)
+		}
+		assert.Equal(t, []string{"created", "created", "skipped", "failed", "skipped", "skipped"}, statuses)
+		assert.Equal(t, 2, report.Rows[0].Row)
+		assert.True(t, report.Rows[0].TutorCreated)
+		assert.False(t, report.Rows[1].TutorCreated)
+		assert.Equal(t, "animal repetido no arquivo", report.Rows[2].Message)
+		assert.Equal(t, service.ErrInvalidCPF.Error(), report.Rows[3].Message)
+		assert.Equal(t, "animal já cadastrado", report.Rows[4].Message)
+		assert.Nil(t, report.Rows[0].AnimalID)
+
+		tutorRepo.AssertNotCalled(t, "CreateTutorWithAnimal", mock.Anything, mock.Anything, mock.Anything)
+		animalRepo.AssertNotCalled(t, "SaveAnimal", mock.Anything)
+	})
+
+	t.Run("import saves tutors and animals", func(t *testing.T) {
+		tutorRepo, animalRepo, importService := newImportService()
+		var saved []*model.Animal
+		tutorRepo.On("CreateTutorWithAnimal", mock.Anything, mock.MatchedBy(func(tutor *model.Tutor) bool {
+			return tutor.CPFTutor == "52998224725" && tutor.Name == "Maria Souza" && tutor.UserType == model.TutorType
+		}), mock.AnythingOfType("*model.Animal")).Run(func(args mock.Arguments) {
+			saved = append(saved, args.Get(2).(*model.Animal))
+		}).Return(nil).Once()
+		animalRepo.On("SaveAnimal", mock.AnythingOfType("*model.Animal")).Run(func(args mock.Arguments) {
+			saved = append(saved, args.Get(0).(*model.Animal))
+		}).Return(nil)
+
+		report, err := importService.ImportCSV(context.Background(), strings.NewReader(legacyCSV), service.ImportOptions{}, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Created)
+		assert.NotNil(t, report.Rows[0].AnimalID)
+
+		assert.Len(t, saved, 2)
+		assert.Equal(t, "52998224725", saved[0].CPFTutor)
+		assert.Equal(t, 32.5, saved[0].Weight)
+		assert.Equal(t, day(2019, 3, 15), saved[0].BirthDate.Time)
+		assert.Equal(t, model.BirthDatePrecisionYear, saved[1].BirthDatePrecision)
+		tutorRepo.AssertExpectations(t)
+	})
+
+	t.Run("a failed row leaves neither the tutor nor the animal", func(t *testing.T) {
+		tutorRepo, animalRepo, importService := newImportService()
+		tutorRepo.On("CreateTutorWithAnimal", mock.Anything, mock.AnythingOfType("*model.Tutor"), mock.AnythingOfType("*model.Animal")).Return(errors.New("duplicate key")).Once()
+		csv := "cpf;tutor;animal;especie\n52998224725;Maria Souza;Rex;Dog\n"
+
+		report, err := importService.ImportCSV(context.Background(), strings.NewReader(csv), service.ImportOptions{}, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Failed)
+		assert.False(t, report.Rows[0].TutorCreated)
+		assert.Nil(t, report.Rows[0].AnimalID)
+		animalRepo.AssertNotCalled(t, "SaveAnimal", mock.Anything)
+	})
+
+	t.Run("explicit mapping and comma separated files", func(t *testing.T) {
+		_, _, importService := newImportService()
+		csv := "Documento,Paciente,Tipo\n11144477735,Rex,Dog\n"
+
+		report, err := importService.ImportCSV(context.Background(), strings.NewReader(csv), service.ImportOptions{
+			DryRun:  true,
+			Mapping: map[string]string{"Documento": "cpf_tutor", "Tipo": "species"},
+		}, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.False(t, report.Rows[0].TutorCreated) // sem coluna de nome do tutor, só o animal é importado
+	})
+
+	t.Run("rows of unregistered or inactive tutors fail without a tutor name column", func(t *testing.T) {
+		tutorRepo, _, importService := newImportService()
+		tutorRepo.On("FindTutorByCPF", mock.Anything, "39053344705").Return(&model.Tutor{CPFTutor: "39053344705"}, nil)
+		csv := "cpf,animal,especie\n52998224725,Rex,Dog\n39053344705,Mia,Cat\n"
+
+		report, err := importService.ImportCSV(context.Background(), strings.NewReader(csv), service.ImportOptions{DryRun: true}, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Failed)
+		assert.Equal(t, service.ErrUnknownTutor.Error(), report.Rows[0].Message)
+		assert.Equal(t, service.ErrTutorInactive.Error(), report.Rows[1].Message)
+	})
+
+	t.Run("imports structured addresses and keeps free-text ones for migration", func(t *testing.T) {
+		tutorRepo, _, importService := newImportService()
+		tutorRepo.On("FindTutorByCPF", mock.Anything, "39053344705").Return(nil, gorm.ErrRecordNotFound)
+		tutorRepo.On("FindTutorByCPF", mock.Anything, "11222333000181").Return(nil, gorm.ErrRecordNotFound)
+		var created []*model.Tutor
+		tutorRepo.On("CreateTutorWithAnimal", mock.Anything, mock.AnythingOfType("*model.Tutor"), mock.Anything).Run(func(args mock.Arguments) {
+			created = append(created, args.Get(1).(*model.Tutor))
+		}).Return(nil)
+		csv := "cpf;tutor;email;telefone;endereco;cep;logradouro;numero;bairro;cidade;uf\n" +
+			"52998224725;Maria Souza;maria@example.com;11987654321;;01310-100;Av. Paulista;1000;Bela Vista;São Paulo;sp\n" +
+			"39053344705;Ana Lima;ana@example.com;11955554444;Rua C, 30 - Centro;;;;;;\n" +
+			"12345678909;Carlos;carlos@example.com;11933332222;;;;;;;\n" +
+			"11222333000181;Abrigo Patas;contato@patas.org;1133334444;;01310-100;Av. Paulista;1000;Bela Vista;São Paulo;XX\n"
+
+		report, err := importService.ImportCSV(context.Background(), strings.NewReader(csv), service.ImportOptions{}, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Created)
+		assert.Len(t, created, 2)
+		assert.Equal(t, "01310100", created[0].Address.CEP)
+		assert.Equal(t, "SP", created[0].Address.UF)
+		assert.True(t, created[1].Address.IsZero())
+		assert.Equal(t, "Rua C, 30 - Centro", created[1].AddressText)
+		assert.Equal(t, "skipped", report.Rows[2].Status) // tutor já cadastrado e linha sem animal
+		assert.Equal(t, "failed", report.Rows[3].Status)
+		assert.Contains(t, report.Rows[3].Message, service.ErrInvalidUF.Error())
+	})
+
+	t.Run("rejects files without a CPF column or with unknown fields", func(t *testing.T) {
+		_, _, importService := newImportService()
+
+		_, err := importService.ImportCSV(context.Background(), strings.NewReader("nome,especie\nRex,Dog\n"), service.ImportOptions{}, now)
+		assert.ErrorIs(t, err, service.ErrMissingImportColumn)
+
+		_, err = importService.ImportCSV(context.Background(), strings.NewReader("cpf\n"), service.ImportOptions{Mapping: map[string]string{"cpf": "rg"}}, now)
+		assert.ErrorIs(t, err, service.ErrUnknownImportField)
+
+		_, err = importService.ImportCSV(context.Background(), strings.NewReader(""), service.ImportOptions{}, now)
+		assert.ErrorIs(t, err, service.ErrEmptyImport)
+	})
+}
@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock do repositório da LGPD
+type MockLGPDRepo struct {
+	mock.Mock
+}
+
+func (m *MockLGPDRepo) FindTutorData(ctx context.Context, cpf string) (*repository.TutorData, error) {
+	args := m.Called(ctx, cpf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.TutorData), args.Error(1)
+}
+
+func (m *MockLGPDRepo) AnonymizeTutor(ctx context.Context, anonymization repository.TutorAnonymization, entry *model.AuditLog) error {
+	args := m.Called(ctx, anonymization, entry)
+	return args.Error(0)
+}
+
+func (m *MockLGPDRepo) SaveAuditLog(ctx context.Context, entry *model.AuditLog) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func TestLGPDService(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+
+	t.Run("exports the tutor data as a zip and logs the export", func(t *testing.T) {
+		lgpdRepo := new(MockLGPDRepo)
+		tutor, _ := model.NewTutor("52998224725", "Maria Souza", "maria@example.com", "11987654321", floresAddress, "")
+		rexID := uuid.New()
+		lgpdRepo.On("FindTutorData", ctx, "52998224725").Return(&repository.TutorData{
+			Tutor:   *tutor,
+			Animals: []model.Animal{{ID: rexID, Name: "Rex", CPFTutor: "52998224725"}},
+			Consultations: []model.Consultation{
+				{ID: uuid.New(), AnimalID: rexID, Reason: "Vacinação anual", ConsultationDate: model.CustomDate{Time: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)}},
+				{ID: uuid.New(), AnimalID: rexID, Reason: "Castração", ConsultationDate: model.CustomDate{Time: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)}},
+			},
+			AnimalTransfers: []model.OwnershipTransfer{{
+				AnimalID: rexID, PreviousCPF: "11144477735", NewCPF: "52998224725",
+				EffectiveDate: model.CustomDate{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			}},
+		}, nil)
+		lgpdRepo.On("SaveAuditLog", ctx, mock.MatchedBy(func(entry *model.AuditLog) bool {
+			return entry.Action == model.AuditActionExport && entry.EntityKey == "52998224725" && entry.PerformedBy == "admin-uid"
+		})).Return(nil)
+
+		var archive bytes.Buffer
+		err := service.NewLGPDService(new(MockTutorRepo), lgpdRepo).ExportTutorData(ctx, "529.982.247-25", "admin-uid", &archive, now)
+		assert.NoError(t, err)
+		lgpdRepo.AssertExpectations(t)
+
+		reader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+		assert.NoError(t, err)
+		files := map[string]*zip.File{}
+		for _, file := range reader.File {
+			files[file.Name] = file
+		}
+		assert.Contains(t, files, "manifest.json")
+		assert.Contains(t, files, "tutor.json")
+		assert.Contains(t, files, "consultations.json")
+
+		content, err := files["animals.json"].Open()
+		assert.NoError(t, err)
+		var animals []model.Animal
+		assert.NoError(t, json.NewDecoder(content).Decode(&animals))
+		assert.Len(t, animals, 1)
+		assert.Equal(t, "Rex", animals[0].Name)
+
+		content, err = files["consultations.json"].Open()
+		assert.NoError(t, err)
+		var consultations []model.Consultation
+		assert.NoError(t, json.NewDecoder(content).Decode(&consultations))
+		assert.Len(t, consultations, 1)
+		assert.Equal(t, "Vacinação anual", consultations[0].Reason)
+	})
+
+	t.Run("anonymizes the tutor under a pseudonym", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		uid := "tutor-uid"
+		tutor, _ := model.NewTutor("52998224725", "Maria da Souza", "maria@example.com", "(11) 98765-4321", floresAddress, "")
+		tutor.FirebaseUID = &uid
+		tutorRepo.On("FindTutorByCPF", ctx, "52998224725").Return(tutor, nil)
+
+		lgpdRepo := new(MockLGPDRepo)
+		var anonymization repository.TutorAnonymization
+		var entry *model.AuditLog
+		lgpdRepo.On("AnonymizeTutor", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			anonymization = args.Get(1).(repository.TutorAnonymization)
+			entry = args.Get(2).(*model.AuditLog)
+		}).Return(nil)
+
+		result, err := service.NewLGPDService(tutorRepo, lgpdRepo).AnonymizeTutor(ctx, "52998224725", "admin-uid", now)
+		assert.NoError(t, err)
+		assert.Equal(t, "tutor-uid", result.FirebaseUID)
+		assert.Len(t, result.Pseudonym, 11)
+		assert.False(t, model.IsValidCPF(result.Pseudonym))
+
+		assert.Equal(t, "52998224725", anonymization.CPF)
+		assert.Equal(t, result.Pseudonym, anonymization.Pseudonym)
+		assert.Equal(t, service.AnonymizedTutorName, anonymization.Name)
+		assert.Subset(t, anonymization.Terms, []string{"Maria da Souza", "529.982.247-25", "maria@example.com", "11987654321"})
+		assert.NotContains(t, anonymization.Terms, "Maria")
+		assert.NotContains(t, anonymization.Terms, "Souza")
+
+		assert.Equal(t, model.AuditActionAnonymize, entry.Action)
+		assert.Equal(t, result.Pseudonym, entry.EntityKey)
+		assert.NotContains(t, entry.Details, "52998224725")
+	})
+
+	t.Run("anonymized tutors cannot be anonymized again or changed", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("FindTutorByCPF", ctx, "01234567891").Return(&model.Tutor{CPFTutor: "01234567891", Name: service.AnonymizedTutorName, AnonymizedAt: &now}, nil)
+		lgpdRepo := new(MockLGPDRepo)
+
+		_, err := service.NewLGPDService(tutorRepo, lgpdRepo).AnonymizeTutor(ctx, "01234567891", "admin-uid", now)
+		assert.ErrorIs(t, err, service.ErrTutorAnonymized)
+		lgpdRepo.AssertNotCalled(t, "AnonymizeTutor", mock.Anything, mock.Anything, mock.Anything)
+
+		tutorService := service.NewTutorService(tutorRepo)
+		_, err = tutorService.SetTutorActive(ctx, "01234567891", true)
+		assert.ErrorIs(t, err, service.ErrTutorAnonymized)
+		name := "Maria Souza"
+		_, err = tutorService.UpdateTutor(ctx, "01234567891", service.TutorPatch{Name: &name})
+		assert.ErrorIs(t, err, service.ErrTutorAnonymized)
+	})
+}
+
+func TestAnonymizationPattern(t *testing.T) {
+	// \y do PostgreSQL equivale a \b nos textos sem acentos nas pontas dos termos
+	pattern := repository.AnonymizationPattern([]string{"Maria da Souza", "529.982.247-25", "(11) 98765-4321", "+55 11 98765-4321", "maria@example.com", " "})
+	scrub := regexp.MustCompile("(?i)" + strings.ReplaceAll(pattern, `\y`, `\b`))
+	anonymize := func(text string) string { return scrub.ReplaceAllString(text, repository.AnonymizedText) }
+
+	assert.Equal(t, "Tutora [anonimizado] ligou de [anonimizado].", anonymize("Tutora MARIA DA SOUZA ligou de (11) 98765-4321."))
+	assert.Equal(t, "Retorno: [anonimizado], [anonimizado]", anonymize("Retorno: +55 11 98765-4321, maria@example.com"))
+	assert.Equal(t, "CPF [anonimizado]", anonymize("CPF 529.982.247-25"))
+	// Partes do nome e números mais longos não são alterados
+	assert.Equal(t, "Dona Maria e o Sr. Souza", anonymize("Dona Maria e o Sr. Souza"))
+	assert.Equal(t, "Lote 1529.982.247-250", anonymize("Lote 1529.982.247-250"))
+	assert.Empty(t, repository.AnonymizationPattern([]string{"", "  "}))
+}
+
+func TestAnonymizedFreeText(t *testing.T) {
+	scrubbed := map[string]repository.FreeTextColumns{}
+	for _, columns := range repository.AnonymizedFreeText {
+		scrubbed[columns.Table] = columns
+	}
+
+	assert.Equal(t, []string{"description"}, scrubbed["animals"].Columns)
+	for _, table := range []string{"animal_histories", "consultation_histories"} {
+		assert.True(t, scrubbed[table].JSON, table)
+		assert.Equal(t, []string{"changes"}, scrubbed[table].Columns, table)
+	}
+	assert.Equal(t, "consultation_id", scrubbed["consultation_histories"].Key)
+	assert.Contains(t, scrubbed["consultations"].Columns, "observation")
+}
@@ -0,0 +1,143 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func licenseExpiring(uf string, expires time.Time) model.VetLicense {
+	return model.VetLicense{ID: uuid.New(), CRVM: "654321-RJ", UF: uf, Number: "654321-" + uf, ExpiresAt: &model.CustomDate{Time: expires}}
+}
+
+func newLicenseService(licenses []model.VetLicense, opts ...service.VeterinaryServiceOption) (*MockVeterinaryRepo, *service.VeterinaryService) {
+	repo := new(MockVeterinaryRepo)
+	repo.On("FindVeterinaryByCRVM", mock.Anything, "654321-RJ").Return(&model.Veterinary{CRVM: "654321-RJ"}, nil)
+	repo.On("FindLicenses", mock.Anything, "654321-RJ").Return(licenses, nil)
+	return repo, service.NewVeterinaryService(repo, opts...)
+}
+
+func TestVetLicenseIsActiveOn(t *testing.T) {
+	license := licenseExpiring("RJ", at("2024-05-10 00:00"))
+	assert.True(t, license.IsActiveOn(at("2024-05-10 23:00")))
+	assert.False(t, license.IsActiveOn(at("2024-05-11 00:00")))
+
+	// Sem validade, a inscrição vale até LicenseGraceDays dias depois do cadastro
+	noExpiry := model.VetLicense{CreatedAt: at("2024-05-01 10:00")}
+	assert.True(t, noExpiry.IsActiveOn(at("2024-05-31 23:00")))
+	assert.False(t, noExpiry.IsActiveOn(at("2024-06-01 00:00")))
+	assert.False(t, model.VetLicense{}.IsActiveOn(time.Now()))
+}
+
+func TestSetLicense(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("renews the license of the same UF", func(t *testing.T) {
+		existing := licenseExpiring("SP", at("2023-12-31 00:00"))
+		repo, vetService := newLicenseService([]model.VetLicense{existing})
+		repo.On("SaveLicense", ctx, mock.AnythingOfType("*model.VetLicense")).Return(nil).Once()
+
+		expires := time.Now().AddDate(1, 0, 0)
+		license, err := vetService.SetLicense(ctx, "654321-rj", "sp 654321", &expires)
+		assert.NoError(t, err)
+		assert.Equal(t, existing.ID, license.ID)
+		assert.Equal(t, "654321-SP", license.Number)
+		assert.True(t, license.Active)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid numbers", func(t *testing.T) {
+		repo, vetService := newLicenseService(nil)
+
+		_, err := vetService.SetLicense(ctx, "654321-RJ", "654321", nil)
+		assert.ErrorIs(t, err, service.ErrInvalidLicense)
+		_, err = vetService.SetLicense(ctx, "654321-RJ", "654321-XX", nil)
+		assert.ErrorIs(t, err, service.ErrInvalidLicense)
+		repo.AssertNotCalled(t, "SaveLicense", mock.Anything, mock.Anything)
+	})
+
+	t.Run("removes only existing licenses", func(t *testing.T) {
+		_, vetService := newLicenseService([]model.VetLicense{licenseExpiring("SP", at("2030-01-01 00:00"))})
+		assert.ErrorIs(t, vetService.DeleteLicense(ctx, "654321-RJ", "MG"), service.ErrLicenseNotFound)
+	})
+}
+
+func TestFindLicensedVeterinary(t *testing.T) {
+	nextYear := time.Now().AddDate(1, 0, 0)
+	lastYear := time.Now().AddDate(-1, 0, 0)
+
+	t.Run("accepts an active license in the clinic UF", func(t *testing.T) {
+		_, vetService := newLicenseService([]model.VetLicense{licenseExpiring("RJ", nextYear)}, service.WithClinicUF("RJ"))
+		vet, err := vetService.FindLicensedVeterinary("654321-RJ")
+		assert.NoError(t, err)
+		assert.Equal(t, "654321-RJ", vet.CRVM)
+	})
+
+	t.Run("rejects expired licenses and licenses of other UFs", func(t *testing.T) {
+		_, vetService := newLicenseService([]model.VetLicense{licenseExpiring("RJ", lastYear), licenseExpiring("SP", nextYear)}, service.WithClinicUF("RJ"))
+		_, err := vetService.FindLicensedVeterinary("654321-RJ")
+		assert.ErrorIs(t, err, service.ErrNoActiveLicense)
+	})
+
+	t.Run("without a clinic UF any active license is accepted", func(t *testing.T) {
+		_, vetService := newLicenseService([]model.VetLicense{licenseExpiring("SP", nextYear)})
+		_, err := vetService.FindLicensedVeterinary("654321-RJ")
+		assert.NoError(t, err)
+	})
+
+	t.Run("checks the license on the consultation date", func(t *testing.T) {
+		expires := time.Now().AddDate(0, 1, 0)
+		_, vetService := newLicenseService([]model.VetLicense{licenseExpiring("RJ", expires)}, service.WithClinicUF("RJ"))
+		_, err := vetService.FindVeterinaryLicensedOn("654321-RJ", expires)
+		assert.NoError(t, err)
+		_, err = vetService.FindVeterinaryLicensedOn("654321-RJ", expires.AddDate(0, 0, 1))
+		assert.ErrorIs(t, err, service.ErrNoActiveLicense)
+	})
+
+	t.Run("AddDosage requires the prescriber", func(t *testing.T) {
+		animal := &model.Animal{ID: uuid.New()}
+		animalRepo := new(repository.MockAnimalRepository)
+		animalRepo.On("FindAnimalByID", animal.ID).Return(animal, nil)
+		dosageRepo := new(MockDosageRepo)
+		_, vetService := newLicenseService([]model.VetLicense{licenseExpiring("RJ", lastYear)}, service.WithClinicUF("RJ"))
+		dosageService := service.NewDosageService(dosageRepo, service.NewClinicalRecordService(new(MockClinicalRepo)), animalRepo, vetService.FindLicensedVeterinary)
+
+		err := dosageService.AddDosage(context.Background(), &model.Dosage{ID: uuid.New(), AnimalID: animal.ID, MedicationID: uuid.New()})
+		assert.ErrorIs(t, err, service.ErrPrescriberRequired)
+		err = dosageService.AddDosage(context.Background(), &model.Dosage{ID: uuid.New(), AnimalID: animal.ID, MedicationID: uuid.New(), CRVM: "654321-RJ"})
+		assert.ErrorIs(t, err, service.ErrNoActiveLicense)
+		dosageRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestLicenseAlerts(t *testing.T) {
+	ctx := context.Background()
+	now := at("2024-05-10 09:00")
+	repo := new(MockVeterinaryRepo)
+	repo.On("FindLicensesExpiringBy", ctx, "2024-06-09").Return([]model.VetLicense{
+		licenseExpiring("RJ", at("2024-05-01 00:00")),
+		licenseExpiring("SP", at("2024-05-20 00:00")),
+		{ID: uuid.New(), CRVM: "654321-RJ", UF: "MG", Number: "654321-MG", CreatedAt: at("2024-05-01 10:00")},
+	}, nil)
+
+	alerts, err := service.NewVeterinaryService(repo).LicenseAlerts(ctx, service.DefaultAlertDays, now)
+	assert.NoError(t, err)
+	assert.Len(t, alerts, 3)
+	assert.Equal(t, service.LicenseExpired, alerts[0].Status)
+	assert.Equal(t, -9, alerts[0].DaysLeft)
+	assert.Equal(t, service.LicenseExpiring, alerts[1].Status)
+	assert.Equal(t, 10, alerts[1].DaysLeft)
+	assert.Equal(t, service.LicenseNoExpiry, alerts[2].Status)
+	assert.True(t, alerts[2].Active)
+	assert.Equal(t, 21, alerts[2].DaysLeft)
+
+	_, err = service.NewVeterinaryService(repo).LicenseAlerts(ctx, -1, now)
+	assert.ErrorIs(t, err, service.ErrInvalidAlertDays)
+}
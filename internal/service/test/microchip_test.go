@@ -0,0 +1,143 @@
+package service_test
+
+import (
+	"testing"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestParseMicrochip(t *testing.T) {
+	t.Run("Country code", func(t *testing.T) {
+		info, err := model.ParseMicrochip("076 0123-4567.8901")
+		assert.NoError(t, err)
+		assert.Equal(t, "076012345678901", info.Number)
+		assert.Equal(t, "076", info.Code)
+		assert.Equal(t, model.MicrochipCountryCode, info.CodeType)
+		assert.Equal(t, "012345678901", info.NationalID)
+	})
+
+	t.Run("Manufacturer code", func(t *testing.T) {
+		info, err := model.ParseMicrochip("985112003456789")
+		assert.NoError(t, err)
+		assert.Equal(t, model.MicrochipManufacturerCode, info.CodeType)
+	})
+
+	t.Run("Invalid numbers", func(t *testing.T) {
+		for _, number := range []string{
+			"",
+			"07601234567890",   // 14 dígitos
+			"0760123456789012", // 16 dígitos
+			"07601234567890A",
+			"999012345678901", // transponder de teste
+			"001012345678901", // código de país inexistente
+		} {
+			_, err := model.ParseMicrochip(number)
+			assert.ErrorIs(t, err, model.ErrInvalidMicrochip, number)
+		}
+	})
+}
+
+func TestAnimalMicrochip(t *testing.T) {
+	newAnimal := func(microchip string) model.Animal {
+		return model.Animal{
+			Name:      "Rex",
+			Species:   "Dog",
+			Breed:     "Labrador",
+			CPFTutor:  "12345678909",
+			Microchip: &microchip,
+		}
+	}
+
+	t.Run("AddAnimal normalizes the microchip", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		mockRepo.On("FindAnimalByMicrochip", "076012345678901").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("FindByUniqueAttributes", mock.Anything).Return(nil, nil)
+		mockRepo.On("SaveAnimal", mock.MatchedBy(func(a *model.Animal) bool {
+			return a.Microchip != nil && *a.Microchip == "076012345678901"
+		})).Return(nil)
+
+		err := animalService.AddAnimal(newAnimal("076 012345678901"))
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("AddAnimal rejects a microchip in use", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		mockRepo.On("FindAnimalByMicrochip", "076012345678901").Return(&model.Animal{ID: uuid.New()}, nil)
+
+		err := animalService.AddAnimal(newAnimal("076012345678901"))
+		assert.ErrorIs(t, err, service.ErrMicrochipInUse)
+		mockRepo.AssertNotCalled(t, "SaveAnimal", mock.Anything)
+	})
+
+	t.Run("AddAnimal rejects an invalid microchip", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		err := animalService.AddAnimal(newAnimal("12345"))
+		assert.ErrorIs(t, err, model.ErrInvalidMicrochip)
+		mockRepo.AssertNotCalled(t, "FindAnimalByMicrochip", mock.Anything)
+	})
+
+	t.Run("PatchAnimal keeps the animal's own microchip", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		animalID := uuid.New()
+		existing := newAnimal("076012345678901")
+		existing.ID = animalID
+		microchip := "076012345678901"
+
+		mockRepo.On("FindAnimalByID", animalID).Return(&existing, nil)
+		mockRepo.On("FindAnimalByMicrochip", "076012345678901").Return(&model.Animal{ID: animalID}, nil)
+		mockRepo.On("UpdateAnimal", &existing, mock.AnythingOfType("*model.AnimalHistory")).Return(nil)
+
+		_, err := animalService.PatchAnimal(animalID, service.AnimalPatch{Microchip: &microchip}, "vet-1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("PatchAnimal removes the microchip", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		animalID := uuid.New()
+		existing := newAnimal("076012345678901")
+		existing.ID = animalID
+		empty := ""
+
+		mockRepo.On("FindAnimalByID", animalID).Return(&existing, nil)
+		mockRepo.On("UpdateAnimal", &existing, mock.MatchedBy(func(h *model.AnimalHistory) bool {
+			return len(h.Changes) == 1 && h.Changes[0].Field == "microchip"
+		})).Return(nil)
+
+		updated, err := animalService.PatchAnimal(animalID, service.AnimalPatch{Microchip: &empty}, "vet-1")
+		assert.NoError(t, err)
+		assert.Nil(t, updated.Microchip)
+	})
+
+	t.Run("UpdateAnimal normalises and checks the microchip", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		animalID := uuid.New()
+		existing := newAnimal("")
+		existing.ID, existing.Microchip = animalID, nil
+
+		mockRepo.On("FindAnimalByID", animalID).Return(&existing, nil)
+		mockRepo.On("FindAnimalByMicrochip", "076012345678901").Return(&model.Animal{ID: uuid.New()}, nil)
+
+		_, err := animalService.UpdateAnimal(animalID, newAnimal("076.012-345 678 901"), "vet-1")
+		assert.ErrorIs(t, err, service.ErrMicrochipInUse)
+		mockRepo.AssertNotCalled(t, "UpdateAnimal", mock.Anything, mock.Anything)
+	})
+}
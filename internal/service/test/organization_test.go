@@ -0,0 +1,115 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestDocumentValidation(t *testing.T) {
+	assert.True(t, model.IsValidCNPJ("11.222.333/0001-81"))
+	assert.True(t, model.IsValidCNPJ("12.ABC.345/01DE-35"))
+	assert.False(t, model.IsValidCNPJ("11.222.333/0001-82"))
+	assert.False(t, model.IsValidCNPJ("00000000000000"))
+
+	assert.True(t, model.IsValidDocument("529.982.247-25"))
+	assert.True(t, model.IsValidDocument("11222333000181"))
+	assert.False(t, model.IsValidDocument("1122233300018"))
+	assert.Equal(t, model.OwnerTypePerson, model.OwnerTypeOf("52998224725"))
+	assert.Equal(t, model.OwnerTypeOrganization, model.OwnerTypeOf("11.222.333/0001-81"))
+}
+
+func TestOrganizationOwners(t *testing.T) {
+	ctx := context.Background()
+	cnpj := "11222333000181"
+	shelter := &model.Tutor{CPFTutor: cnpj, OwnerType: model.OwnerTypeOrganization, Name: "Abrigo Patas", Active: true}
+
+	t.Run("registers an organization by its CNPJ", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("FindTutorByCPF", ctx, cnpj).Return(nil, gorm.ErrRecordNotFound)
+		tutorRepo.On("CreateTutor", ctx, mock.AnythingOfType("*model.Tutor")).Return(nil)
+
+		tutor, err := service.NewTutorService(tutorRepo).CreateTutor(ctx, "11.222.333/0001-81", "Abrigo Patas", "contato@patas.org", "1133334444", model.Address{CEP: "13100-000", Street: "Estrada do Sítio", Number: "km 3", Neighborhood: "Zona Rural", City: "Campinas", UF: "SP"})
+		assert.NoError(t, err)
+		assert.Equal(t, cnpj, tutor.CPFTutor)
+		assert.Equal(t, model.OwnerTypeOrganization, tutor.OwnerType)
+
+		_, err = service.NewTutorService(tutorRepo).CreateTutor(ctx, "11.222.333/0001-82", "Abrigo Patas", "contato@patas.org", "1133334444", model.Address{CEP: "13100-000", Street: "Estrada do Sítio", Number: "km 3", Neighborhood: "Zona Rural", City: "Campinas", UF: "SP"})
+		assert.ErrorIs(t, err, service.ErrInvalidCPF)
+	})
+
+	t.Run("organizations can own and receive animals", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("FindTutorByCPF", mock.Anything, cnpj).Return(shelter, nil)
+		animalID := uuid.New()
+		animalRepo := new(repository.MockAnimalRepository)
+		animalRepo.On("FindAnimalByID", animalID).Return(&model.Animal{ID: animalID, Name: "Rex", Species: "Dog", CPFTutor: "52998224725"}, nil)
+		animalRepo.On("FindOwnershipTransfers", animalID).Return([]model.OwnershipTransfer{}, nil)
+		animalRepo.On("TransferOwnership", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		animalService := service.NewAnimalService(animalRepo, service.WithTutors(service.NewTutorService(tutorRepo)))
+		transfer, err := animalService.TransferOwnership(animalID, cnpj, time.Now().AddDate(0, 0, -1), "Resgate pelo abrigo", "vet-uid")
+		assert.NoError(t, err)
+		assert.Equal(t, cnpj, transfer.NewCPF)
+	})
+
+	t.Run("manages legal representatives", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("FindTutorByCPF", ctx, cnpj).Return(shelter, nil)
+		tutorRepo.On("FindTutorByCPF", ctx, "52998224725").Return(&model.Tutor{CPFTutor: "52998224725", OwnerType: model.OwnerTypePerson}, nil)
+		tutorRepo.On("SaveLegalRepresentative", ctx, mock.AnythingOfType("*model.LegalRepresentative")).Return(nil)
+		tutorService := service.NewTutorService(tutorRepo)
+
+		representative, err := tutorService.AddLegalRepresentative(ctx, "11.222.333/0001-81", model.LegalRepresentative{
+			Name: "Maria Souza", CPF: "529.982.247-25", Position: "Presidente", Phone: "11987654321",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, cnpj, representative.CNPJ)
+		assert.Equal(t, "52998224725", representative.CPF)
+
+		_, err = tutorService.AddLegalRepresentative(ctx, cnpj, model.LegalRepresentative{Name: "Maria Souza", CPF: "52998224725"})
+		assert.ErrorIs(t, err, service.ErrRepresentativeContact)
+
+		_, err = tutorService.AddLegalRepresentative(ctx, cnpj, model.LegalRepresentative{Name: "Maria Souza", CPF: "12345678900", Phone: "11987654321"})
+		assert.Error(t, err)
+
+		_, err = tutorService.AddLegalRepresentative(ctx, "52998224725", model.LegalRepresentative{Name: "Maria Souza", CPF: "52998224725", Phone: "11987654321"})
+		assert.ErrorIs(t, err, service.ErrNotOrganization)
+
+		tutorRepo.On("FindLegalRepresentatives", ctx, cnpj).Return([]model.LegalRepresentative{*representative}, nil)
+		tutorRepo.On("DeleteLegalRepresentative", ctx, mock.AnythingOfType("*model.LegalRepresentative")).Return(nil)
+		assert.NoError(t, tutorService.RemoveLegalRepresentative(ctx, cnpj, representative.ID))
+		assert.ErrorIs(t, tutorService.RemoveLegalRepresentative(ctx, cnpj, uuid.New()), service.ErrRepresentativeNotFound)
+	})
+
+	t.Run("filters tutor searches by owner type", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("ListTutors", ctx, repository.TutorQuery{OwnerType: model.OwnerTypeOrganization, Limit: service.DefaultTutorPageSize}).
+			Return([]model.Tutor{*shelter}, nil)
+		tutorService := service.NewTutorService(tutorRepo)
+
+		tutors, err := tutorService.SearchTutors(ctx, repository.TutorQuery{OwnerType: model.OwnerTypeOrganization})
+		assert.NoError(t, err)
+		assert.Len(t, tutors, 1)
+
+		_, err = tutorService.SearchTutors(ctx, repository.TutorQuery{OwnerType: "company"})
+		assert.ErrorIs(t, err, service.ErrInvalidOwnerType)
+	})
+
+	t.Run("organizations are not anonymized", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("FindTutorByCPF", ctx, cnpj).Return(shelter, nil)
+		lgpdRepo := new(MockLGPDRepo)
+
+		_, err := service.NewLGPDService(tutorRepo, lgpdRepo).AnonymizeTutor(ctx, cnpj, "admin-uid", time.Now())
+		assert.ErrorIs(t, err, service.ErrNotNaturalPerson)
+	})
+}
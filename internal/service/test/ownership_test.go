@@ -0,0 +1,94 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestTransferOwnership(t *testing.T) {
+	animalID := uuid.New()
+	newAnimal := func() *model.Animal {
+		return &model.Animal{ID: animalID, Name: "Rex", Species: "Dog", CPFTutor: "52998224725"}
+	}
+
+	t.Run("should record the transfer and the history entry", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+		animal := newAnimal()
+
+		mockRepo.On("FindAnimalByID", animalID).Return(animal, nil)
+		mockRepo.On("FindOwnershipTransfers", animalID).Return([]model.OwnershipTransfer{}, nil)
+		mockRepo.On("TransferOwnership", animal, mock.AnythingOfType("*model.OwnershipTransfer"), mock.AnythingOfType("*model.AnimalHistory")).Return(nil)
+
+		transfer, err := animalService.TransferOwnership(animalID, "11144477735", day(2024, 3, 1), "Adoção", "vet-uid")
+		assert.NoError(t, err)
+		assert.Equal(t, "52998224725", transfer.PreviousCPF)
+		assert.Equal(t, "11144477735", transfer.NewCPF)
+		assert.Equal(t, "Adoção", transfer.Reason)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject an invalid CPF", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		_, err := animalService.TransferOwnership(animalID, "11111111111", day(2024, 3, 1), "Adoção", "vet-uid")
+		assert.ErrorIs(t, err, service.ErrInvalidCPF)
+		mockRepo.AssertNotCalled(t, "FindAnimalByID", mock.Anything)
+	})
+
+	t.Run("should reject a transfer to the current tutor", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		mockRepo.On("FindAnimalByID", animalID).Return(newAnimal(), nil)
+
+		_, err := animalService.TransferOwnership(animalID, "52998224725", day(2024, 3, 1), "Adoção", "vet-uid")
+		assert.ErrorIs(t, err, service.ErrSameTutor)
+	})
+
+	t.Run("should reject an effective date before the last transfer", func(t *testing.T) {
+		mockRepo := new(repository.MockAnimalRepository)
+		animalService := service.NewAnimalService(mockRepo)
+
+		mockRepo.On("FindAnimalByID", animalID).Return(newAnimal(), nil)
+		mockRepo.On("FindOwnershipTransfers", animalID).Return([]model.OwnershipTransfer{
+			{PreviousCPF: "11144477735", NewCPF: "52998224725", EffectiveDate: model.CustomDate{Time: day(2024, 5, 1)}},
+		}, nil)
+
+		_, err := animalService.TransferOwnership(animalID, "11144477735", day(2024, 3, 1), "Devolução", "vet-uid")
+		assert.ErrorIs(t, err, service.ErrEffectiveDateOutdated)
+		mockRepo.AssertNotCalled(t, "TransferOwnership", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestConsultationsVisibleToTutor(t *testing.T) {
+	periods := service.BuildOwnershipPeriods("11144477735", []model.OwnershipTransfer{
+		{PreviousCPF: "52998224725", NewCPF: "11144477735", EffectiveDate: model.CustomDate{Time: day(2024, 3, 1)}, Reason: "Adoção"},
+	})
+
+	assert.Len(t, periods, 2)
+	assert.Equal(t, "52998224725", periods[0].CPFTutor)
+	assert.Nil(t, periods[0].From)
+	assert.Equal(t, "11144477735", periods[1].CPFTutor)
+	assert.Nil(t, periods[1].To)
+
+	before := model.Consultation{ID: uuid.New(), ConsultationDate: model.CustomDate{Time: day(2024, 2, 10)}}
+	after := model.Consultation{ID: uuid.New(), ConsultationDate: model.CustomDate{Time: day(2024, 3, 1)}}
+	consultations := []model.Consultation{before, after}
+
+	assert.Equal(t, []model.Consultation{after}, service.ConsultationsVisibleToTutor(consultations, periods, "11144477735"))
+	assert.Equal(t, []model.Consultation{before}, service.ConsultationsVisibleToTutor(consultations, periods, "52998224725"))
+	assert.Empty(t, service.ConsultationsVisibleToTutor(consultations, periods, "12345678909"))
+}
@@ -0,0 +1,258 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockScheduleRepo struct {
+	mock.Mock
+}
+
+var _ repository.ScheduleRepository = (*MockScheduleRepo)(nil)
+
+func (m *MockScheduleRepo) FindWorkingHours(ctx context.Context, crvm string) ([]model.WorkingHours, error) {
+	args := m.Called(ctx, crvm)
+	return args.Get(0).([]model.WorkingHours), args.Error(1)
+}
+
+func (m *MockScheduleRepo) ReplaceWorkingHours(ctx context.Context, crvm string, hours []model.WorkingHours) error {
+	args := m.Called(ctx, crvm, hours)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepo) FindExceptions(ctx context.Context, crvm, startDate, endDate string) ([]model.AvailabilityException, error) {
+	args := m.Called(ctx, crvm, startDate, endDate)
+	return args.Get(0).([]model.AvailabilityException), args.Error(1)
+}
+
+func (m *MockScheduleRepo) FindExceptionByID(ctx context.Context, id uuid.UUID) (*model.AvailabilityException, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AvailabilityException), args.Error(1)
+}
+
+func (m *MockScheduleRepo) SaveException(ctx context.Context, exception *model.AvailabilityException) error {
+	args := m.Called(ctx, exception)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepo) DeleteException(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Segunda a sexta das 08:00 às 12:00 e das 14:00 às 18:00
+func weekdayHours() []model.WorkingHours {
+	var hours []model.WorkingHours
+	for day := 1; day <= 5; day++ {
+		hours = append(hours,
+			model.WorkingHours{CRVM: "654321-RJ", Weekday: day, StartTime: "08:00", EndTime: "12:00"},
+			model.WorkingHours{CRVM: "654321-RJ", Weekday: day, StartTime: "14:00", EndTime: "18:00"})
+	}
+	return hours
+}
+
+func newScheduleService(hours []model.WorkingHours, exceptions []model.AvailabilityException) (*MockScheduleRepo, *service.ScheduleService) {
+	ctx := context.Background()
+	scheduleRepo := new(MockScheduleRepo)
+	scheduleRepo.On("FindWorkingHours", ctx, "654321-RJ").Return(hours, nil)
+	scheduleRepo.On("FindExceptions", ctx, "654321-RJ", mock.Anything, mock.Anything).Return(exceptions, nil)
+	vetRepo := new(MockVeterinaryRepo)
+	vetRepo.On("FindVeterinaryByCRVM", ctx, "654321-RJ").Return(&model.Veterinary{CRVM: "654321-RJ"}, nil)
+	return scheduleRepo, service.NewScheduleService(scheduleRepo, vetRepo)
+}
+
+func at(value string) time.Time {
+	t, _ := time.Parse("2006-01-02 15:04", value)
+	return t
+}
+
+func TestWorkingHours(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("replaces the weekly template", func(t *testing.T) {
+		scheduleRepo, scheduleService := newScheduleService(weekdayHours(), nil)
+		replaced := mock.MatchedBy(func(hours []model.WorkingHours) bool {
+			return len(hours) == 10 && hours[0].CRVM == "654321-RJ" && hours[0].ID != uuid.Nil
+		})
+		scheduleRepo.On("ReplaceWorkingHours", ctx, "654321-RJ", replaced).Return(nil).Once()
+
+		hours, err := scheduleService.SetWorkingHours(ctx, "654321/rj", weekdayHours())
+		assert.NoError(t, err)
+		assert.Len(t, hours, 10)
+		scheduleRepo.AssertCalled(t, "ReplaceWorkingHours", ctx, "654321-RJ", replaced)
+	})
+
+	t.Run("rejects invalid and overlapping intervals", func(t *testing.T) {
+		scheduleRepo, scheduleService := newScheduleService(nil, nil)
+
+		_, err := scheduleService.SetWorkingHours(ctx, "654321-RJ", []model.WorkingHours{{Weekday: 1, StartTime: "18:00", EndTime: "08:00"}})
+		assert.ErrorIs(t, err, service.ErrInvalidWorkingHours)
+		_, err = scheduleService.SetWorkingHours(ctx, "654321-RJ", []model.WorkingHours{{Weekday: 7, StartTime: "08:00", EndTime: "12:00"}})
+		assert.ErrorIs(t, err, service.ErrInvalidWorkingHours)
+		_, err = scheduleService.SetWorkingHours(ctx, "654321-RJ", []model.WorkingHours{{Weekday: 1, StartTime: "8h", EndTime: "12:00"}})
+		assert.ErrorIs(t, err, service.ErrInvalidWorkingHours)
+		_, err = scheduleService.SetWorkingHours(ctx, "654321-RJ", []model.WorkingHours{
+			{Weekday: 1, StartTime: "08:00", EndTime: "12:00"},
+			{Weekday: 1, StartTime: "11:30", EndTime: "18:00"},
+		})
+		assert.ErrorIs(t, err, service.ErrOverlappingWorkingHours)
+		scheduleRepo.AssertNotCalled(t, "ReplaceWorkingHours", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAvailabilityExceptions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("registers an absence", func(t *testing.T) {
+		scheduleRepo, scheduleService := newScheduleService(nil, nil)
+		scheduleRepo.On("SaveException", ctx, mock.AnythingOfType("*model.AvailabilityException")).Return(nil)
+
+		exception, err := scheduleService.AddException(ctx, "654321-RJ", model.AvailabilityException{
+			StartDate: model.CustomDate{Time: at("2024-07-01 00:00")},
+			EndDate:   model.CustomDate{Time: at("2024-07-15 00:00")},
+			Reason:    model.AbsenceVacation,
+		}, "admin-uid")
+		assert.NoError(t, err)
+		assert.Equal(t, "654321-RJ", exception.CRVM)
+		assert.Equal(t, "admin-uid", exception.CreatedBy)
+		assert.True(t, exception.IsFullDay())
+	})
+
+	t.Run("rejects invalid absences", func(t *testing.T) {
+		scheduleRepo, scheduleService := newScheduleService(nil, nil)
+		valid := model.AvailabilityException{
+			StartDate: model.CustomDate{Time: at("2024-07-01 00:00")},
+			EndDate:   model.CustomDate{Time: at("2024-07-01 00:00")},
+			Reason:    model.AbsenceCourse,
+		}
+
+		reversed := valid
+		reversed.StartDate = model.CustomDate{Time: at("2024-07-02 00:00")}
+		_, err := scheduleService.AddException(ctx, "654321-RJ", reversed, "")
+		assert.ErrorIs(t, err, service.ErrInvalidException)
+
+		unknownReason := valid
+		unknownReason.Reason = "holiday"
+		_, err = scheduleService.AddException(ctx, "654321-RJ", unknownReason, "")
+		assert.ErrorIs(t, err, service.ErrInvalidException)
+
+		halfInterval := valid
+		halfInterval.StartTime = "14:00"
+		_, err = scheduleService.AddException(ctx, "654321-RJ", halfInterval, "")
+		assert.ErrorIs(t, err, service.ErrInvalidException)
+		scheduleRepo.AssertNotCalled(t, "SaveException", mock.Anything, mock.Anything)
+	})
+
+	t.Run("only removes absences of the veterinarian", func(t *testing.T) {
+		scheduleRepo, scheduleService := newScheduleService(nil, nil)
+		id := uuid.New()
+		scheduleRepo.On("FindExceptionByID", ctx, id).Return(&model.AvailabilityException{ID: id, CRVM: "1234-SP"}, nil)
+
+		err := scheduleService.DeleteException(ctx, "654321-RJ", id)
+		assert.ErrorIs(t, err, service.ErrExceptionNotFound)
+		scheduleRepo.AssertNotCalled(t, "DeleteException", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCheckAvailability(t *testing.T) {
+	sickAfternoon := model.AvailabilityException{
+		CRVM:      "654321-RJ",
+		StartDate: model.CustomDate{Time: at("2024-05-08 00:00")},
+		EndDate:   model.CustomDate{Time: at("2024-05-08 00:00")},
+		StartTime: "14:00",
+		EndTime:   "16:00",
+		Reason:    model.AbsenceSickLeave,
+	}
+
+	t.Run("accepts bookings inside working hours", func(t *testing.T) {
+		_, scheduleService := newScheduleService(weekdayHours(), nil)
+		// 2024-05-06 é uma segunda-feira
+		assert.NoError(t, scheduleService.CheckAvailability("654321-RJ", at("2024-05-06 08:00")))
+		assert.NoError(t, scheduleService.CheckAvailability("654321-RJ", at("2024-05-06 17:45")))
+	})
+
+	t.Run("rejects bookings outside working hours", func(t *testing.T) {
+		_, scheduleService := newScheduleService(weekdayHours(), nil)
+		assert.ErrorIs(t, scheduleService.CheckAvailability("654321-RJ", at("2024-05-06 03:00")), service.ErrOutsideWorkingHours)
+		assert.ErrorIs(t, scheduleService.CheckAvailability("654321-RJ", at("2024-05-06 11:50")), service.ErrOutsideWorkingHours)
+		assert.ErrorIs(t, scheduleService.CheckAvailability("654321-RJ", at("2024-05-05 10:00")), service.ErrOutsideWorkingHours)
+	})
+
+	t.Run("rejects bookings during absences", func(t *testing.T) {
+		_, scheduleService := newScheduleService(weekdayHours(), []model.AvailabilityException{sickAfternoon})
+		assert.ErrorIs(t, scheduleService.CheckAvailability("654321-RJ", at("2024-05-08 15:00")), service.ErrVeterinaryUnavailable)
+		assert.NoError(t, scheduleService.CheckAvailability("654321-RJ", at("2024-05-08 16:00")))
+		assert.NoError(t, scheduleService.CheckAvailability("654321-RJ", at("2024-05-08 09:00")))
+	})
+
+	t.Run("veterinarians without working hours are available all day except when absent", func(t *testing.T) {
+		_, scheduleService := newScheduleService([]model.WorkingHours{}, []model.AvailabilityException{sickAfternoon})
+		assert.NoError(t, scheduleService.CheckAvailability("654321-RJ", at("2024-05-08 07:00")))
+		assert.NoError(t, scheduleService.CheckAvailability("654321-RJ", at("2024-05-08 20:00")))
+		assert.ErrorIs(t, scheduleService.CheckAvailability("654321-RJ", at("2024-05-08 14:30")), service.ErrVeterinaryUnavailable)
+	})
+
+	t.Run("veterinarians whose working hours were cleared are not bookable", func(t *testing.T) {
+		ctx := context.Background()
+		veterinary := &model.Veterinary{CRVM: "654321-RJ"}
+		scheduleRepo := new(MockScheduleRepo)
+		scheduleRepo.On("FindWorkingHours", ctx, "654321-RJ").Return([]model.WorkingHours{}, nil)
+		scheduleRepo.On("FindExceptions", ctx, "654321-RJ", mock.Anything, mock.Anything).Return([]model.AvailabilityException{}, nil)
+		// Como o repositório, a gravação marca o horário como cadastrado
+		scheduleRepo.On("ReplaceWorkingHours", ctx, "654321-RJ", mock.Anything).Run(func(args mock.Arguments) {
+			setAt := at("2024-05-01 10:00")
+			veterinary.HoursSetAt = &setAt
+		}).Return(nil)
+		vetRepo := new(MockVeterinaryRepo)
+		vetRepo.On("FindVeterinaryByCRVM", ctx, "654321-RJ").Return(veterinary, nil)
+		scheduleService := service.NewScheduleService(scheduleRepo, vetRepo)
+
+		_, err := scheduleService.SetWorkingHours(ctx, "654321-RJ", []model.WorkingHours{})
+		assert.NoError(t, err)
+		assert.ErrorIs(t, scheduleService.CheckAvailability("654321-RJ", at("2024-05-08 09:00")), service.ErrOutsideWorkingHours)
+
+		availability, err := scheduleService.GetDayAvailability(ctx, "654321-RJ", at("2024-05-08 00:00"))
+		assert.NoError(t, err)
+		assert.Empty(t, availability.Intervals)
+	})
+
+	t.Run("day availability discounts absences", func(t *testing.T) {
+		_, scheduleService := newScheduleService(weekdayHours(), []model.AvailabilityException{sickAfternoon})
+
+		availability, err := scheduleService.GetDayAvailability(context.Background(), "654321-RJ", at("2024-05-08 00:00"))
+		assert.NoError(t, err)
+		assert.Equal(t, []service.TimeInterval{{Start: "08:00", End: "12:00"}, {Start: "16:00", End: "18:00"}}, availability.Intervals)
+	})
+
+	t.Run("AddConsultation rejects bookings outside availability", func(t *testing.T) {
+		_, scheduleService := newScheduleService(weekdayHours(), nil)
+		consultationRepo := new(MockConsultationRepo)
+		consultation := &model.Consultation{
+			ID:               uuid.New(),
+			AnimalID:         uuid.New(),
+			CRVM:             "valid-crvm",
+			ConsultationDate: model.CustomDate{Time: at("2024-05-06 00:00")},
+			ConsultationHour: "03:00",
+		}
+		consultationRepo.On("FindConsultationByID", mock.Anything, consultation.ID).Return(nil, nil)
+		checkAvailability := func(crvm string, start time.Time) error {
+			return scheduleService.CheckAvailability("654321-RJ", start)
+		}
+
+		err := service.AddConsultation(consultationRepo, consultation, MockGetVeterinaryByCRVM, MockGetAnimalByID, checkAvailability)
+		assert.ErrorIs(t, err, service.ErrOutsideWorkingHours)
+		consultationRepo.AssertNotCalled(t, "SaveConsultation", mock.Anything, mock.Anything)
+	})
+}
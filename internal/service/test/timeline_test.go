@@ -0,0 +1,243 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// Mock do repositório de dosagens
+type MockDosageRepo struct {
+	mock.Mock
+}
+
+var _ repository.DosageRepository = (*MockDosageRepo)(nil)
+
+func (m *MockDosageRepo) Create(ctx context.Context, dosage *model.Dosage, medicationId uuid.UUID, quantity int) error {
+	args := m.Called(ctx, dosage, medicationId, quantity)
+	return args.Error(0)
+}
+
+func (m *MockDosageRepo) Update(ctx context.Context, dosage *model.Dosage) error {
+	args := m.Called(ctx, dosage)
+	return args.Error(0)
+}
+
+func (m *MockDosageRepo) Delete(ctx context.Context, dosageID uuid.UUID) error {
+	args := m.Called(ctx, dosageID)
+	return args.Error(0)
+}
+
+func (m *MockDosageRepo) FindByID(ctx context.Context, dosageID uuid.UUID) (*model.Dosage, error) {
+	args := m.Called(ctx, dosageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Dosage), args.Error(1)
+}
+
+func (m *MockDosageRepo) FindByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.Dosage, error) {
+	args := m.Called(ctx, animalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Dosage), args.Error(1)
+}
+
+// Mock do repositório de hospitalizações
+type MockHospitalizationRepo struct {
+	mock.Mock
+}
+
+var _ repository.HospitalizationRepositoryInterface = (*MockHospitalizationRepo)(nil)
+
+func (m *MockHospitalizationRepo) SaveHospitalization(hospitalization *model.Hospitalization) error {
+	args := m.Called(hospitalization)
+	return args.Error(0)
+}
+
+func (m *MockHospitalizationRepo) FindHospitalizationByID(id string) (*model.Hospitalization, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Hospitalization), args.Error(1)
+}
+
+func (m *MockHospitalizationRepo) DeleteHospitalization(id string) (string, error) {
+	args := m.Called(id)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockHospitalizationRepo) GetHospitalizationByID(id uuid.UUID) (*model.Hospitalization, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Hospitalization), args.Error(1)
+}
+
+func (m *MockHospitalizationRepo) FindHospitalizationsByPatientID(patientID uuid.UUID) ([]model.Hospitalization, error) {
+	args := m.Called(patientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Hospitalization), args.Error(1)
+}
+
+// Mock do repositório de imagens
+type MockImageRepo struct {
+	mock.Mock
+}
+
+var _ repository.ImageRepositoryInterface = (*MockImageRepo)(nil)
+
+func (m *MockImageRepo) SaveImage(image *model.ImageModel) error {
+	args := m.Called(image)
+	return args.Error(0)
+}
+
+func (m *MockImageRepo) FindImageByID(id uuid.UUID) (*model.ImageModel, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImageModel), args.Error(1)
+}
+
+func (m *MockImageRepo) DeleteImage(id uuid.UUID) (string, error) {
+	args := m.Called(id)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockImageRepo) UpdateImage(id uuid.UUID, updatedImage model.ImageModel) error {
+	args := m.Called(id, updatedImage)
+	return args.Error(0)
+}
+
+func (m *MockImageRepo) FindImageMetadata(id uuid.UUID) (*model.ImageModel, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImageModel), args.Error(1)
+}
+
+type timelineMocks struct {
+	consultations    *MockConsultationRepo
+	dosages          *MockDosageRepo
+	hospitalizations *MockHospitalizationRepo
+	weights          *MockWeightRepo
+	images           *MockImageRepo
+}
+
+func newTimelineService() (*timelineMocks, *service.TimelineService) {
+	m := &timelineMocks{
+		consultations:    new(MockConsultationRepo),
+		dosages:          new(MockDosageRepo),
+		hospitalizations: new(MockHospitalizationRepo),
+		weights:          new(MockWeightRepo),
+		images:           new(MockImageRepo),
+	}
+	return m, service.NewTimelineService(m.consultations, m.dosages, m.hospitalizations, m.weights, m.images)
+}
+
+func TestGetTimeline(t *testing.T) {
+	animalID := uuid.New()
+	consultation := model.Consultation{ID: uuid.New(), AnimalID: animalID, ConsultationDate: model.CustomDate{Time: day(2024, 3, 10)}, ConsultationHour: "14:30"}
+	dosage := model.Dosage{ID: uuid.New(), AnimalID: animalID, StartDate: model.CustomDate{Time: day(2024, 3, 10)}}
+	hospitalization := model.Hospitalization{ID: uuid.New(), PatientID: animalID, StartDate: model.CustomDate{Time: day(2024, 2, 1)}}
+	weights := []model.WeightRecord{
+		{ID: uuid.New(), AnimalID: animalID, Date: model.CustomDate{Time: day(2024, 1, 5)}, Weight: 10, Unit: model.WeightUnitKg},
+		{ID: uuid.New(), AnimalID: animalID, Date: model.CustomDate{Time: day(2024, 3, 1)}, Weight: 11, Unit: model.WeightUnitKg},
+	}
+	image := &model.ImageModel{ID: animalID, CreatedAt: day(2024, 1, 1), UpdatedAt: day(2024, 2, 20)}
+
+	setup := func() (*timelineMocks, *service.TimelineService) {
+		m, timelineService := newTimelineService()
+		m.consultations.On("FindConsultationByAnimalID", mock.Anything, animalID).Return([]model.Consultation{consultation}, nil)
+		m.dosages.On("FindByAnimalID", mock.Anything, animalID).Return([]model.Dosage{dosage}, nil)
+		m.hospitalizations.On("FindHospitalizationsByPatientID", animalID).Return([]model.Hospitalization{hospitalization}, nil)
+		m.weights.On("FindByAnimalID", mock.Anything, animalID).Return(weights, nil)
+		m.images.On("FindImageMetadata", animalID).Return(image, nil)
+		return m, timelineService
+	}
+
+	t.Run("Merges every source newest first", func(t *testing.T) {
+		_, timelineService := setup()
+
+		page, err := timelineService.GetTimeline(context.Background(), animalID, service.TimelineQuery{})
+		assert.NoError(t, err)
+		assert.Empty(t, page.NextCursor)
+
+		var types []string
+		for _, entry := range page.Entries {
+			types = append(types, entry.Type)
+		}
+		// A consulta das 14:30 vem antes da dosagem iniciada no mesmo dia
+		assert.Equal(t, []string{"consultation", "dosage", "weight", "image", "hospitalization", "weight"}, types)
+		assert.Equal(t, time.Date(2024, 3, 10, 14, 30, 0, 0, time.UTC), page.Entries[0].OccurredAt)
+
+		latestWeight := page.Entries[2].Data.(service.WeightPoint)
+		assert.Equal(t, 10.0, *latestWeight.PercentChange)
+		assert.Equal(t, "/api/v1/animals/"+animalID.String()+"/image", page.Entries[3].Data.(service.TimelineImageData).URL)
+	})
+
+	t.Run("Pages with a cursor without repeating entries", func(t *testing.T) {
+		_, timelineService := setup()
+
+		var seen []uuid.UUID
+		cursor := ""
+		for pages := 0; pages < 10; pages++ {
+			page, err := timelineService.GetTimeline(context.Background(), animalID, service.TimelineQuery{Cursor: cursor, Limit: 4})
+			assert.NoError(t, err)
+			for _, entry := range page.Entries {
+				seen = append(seen, entry.ID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+		assert.Len(t, seen, 6)
+		assert.Equal(t, consultation.ID, seen[0])
+		assert.Equal(t, weights[0].ID, seen[5])
+	})
+
+	t.Run("Loads only the requested types", func(t *testing.T) {
+		m, timelineService := setup()
+
+		page, err := timelineService.GetTimeline(context.Background(), animalID, service.TimelineQuery{Types: []string{"hospitalization", "dosage"}})
+		assert.NoError(t, err)
+		assert.Len(t, page.Entries, 2)
+		m.consultations.AssertNotCalled(t, "FindConsultationByAnimalID", mock.Anything, mock.Anything)
+		m.images.AssertNotCalled(t, "FindImageMetadata", mock.Anything)
+	})
+
+	t.Run("Animal without image", func(t *testing.T) {
+		m, timelineService := newTimelineService()
+		m.images.On("FindImageMetadata", animalID).Return(nil, gorm.ErrRecordNotFound)
+
+		page, err := timelineService.GetTimeline(context.Background(), animalID, service.TimelineQuery{Types: []string{"image"}})
+		assert.NoError(t, err)
+		assert.Empty(t, page.Entries)
+	})
+
+	t.Run("Rejects unknown types and invalid cursors", func(t *testing.T) {
+		_, timelineService := newTimelineService()
+
+		_, err := timelineService.GetTimeline(context.Background(), animalID, service.TimelineQuery{Types: []string{"surgery"}})
+		assert.ErrorIs(t, err, service.ErrUnknownTimelineType)
+
+		_, err = timelineService.GetTimeline(context.Background(), animalID, service.TimelineQuery{Cursor: "not-a-cursor"})
+		assert.ErrorIs(t, err, service.ErrInvalidTimelineCursor)
+	})
+}
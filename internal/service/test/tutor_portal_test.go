@@ -0,0 +1,146 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestResolveTutor(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("binds the firebase user to the tutor on first login", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("FindTutorByUID", ctx, "uid-1").Return(nil, gorm.ErrRecordNotFound)
+		tutorRepo.On("FindUnboundTutorByEmail", ctx, "maria@example.com").
+			Return(&model.Tutor{User: model.User{Email: "maria@example.com"}, CPFTutor: "52998224725", Active: true}, nil)
+		tutorRepo.On("UpdateTutor", ctx, mock.MatchedBy(func(tutor *model.Tutor) bool {
+			return tutor.FirebaseUID != nil && *tutor.FirebaseUID == "uid-1"
+		})).Return(nil)
+
+		tutor, err := service.NewTutorService(tutorRepo).ResolveTutor(ctx, "uid-1", "maria@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, "52998224725", tutor.CPFTutor)
+		tutorRepo.AssertExpectations(t)
+	})
+
+	t.Run("does not look up by e-mail without a verified e-mail", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("FindTutorByUID", ctx, "uid-1").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.NewTutorService(tutorRepo).ResolveTutor(ctx, "uid-1", "")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		tutorRepo.AssertNotCalled(t, "FindUnboundTutorByEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects inactive tutors", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("FindTutorByUID", ctx, "uid-1").Return(&model.Tutor{CPFTutor: "52998224725", Active: false}, nil)
+
+		_, err := service.NewTutorService(tutorRepo).ResolveTutor(ctx, "uid-1", "maria@example.com")
+		assert.ErrorIs(t, err, service.ErrTutorInactive)
+	})
+}
+
+func TestTutorPortal(t *testing.T) {
+	ctx := context.Background()
+	now := day(2024, 5, 10)
+	cpf := "52998224725"
+	rex := model.Animal{ID: uuid.New(), Name: "Rex", CPFTutor: cpf}
+	mia := model.Animal{ID: uuid.New(), Name: "Mia", CPFTutor: "11144477735"}
+
+	newPortal := func(transfers ...model.OwnershipTransfer) (*MockConsultationRepo, *MockDosageRepo, *MockVaccinationRepo, *service.TutorPortalService) {
+		animalRepo := new(repository.MockAnimalRepository)
+		animalRepo.On("FindAnimalsOwnedBy", cpf).Return([]model.Animal{rex}, nil)
+		animalRepo.On("FindOwnershipTransfers", rex.ID).Return(transfers, nil)
+		consultationRepo := new(MockConsultationRepo)
+		dosageRepo := new(MockDosageRepo)
+		vaccinationRepo := new(MockVaccinationRepo)
+		portal := service.NewTutorPortalService(new(MockTutorRepo), animalRepo, consultationRepo, dosageRepo, vaccinationRepo)
+		return consultationRepo, dosageRepo, vaccinationRepo, portal
+	}
+
+	t.Run("hides animals of other tutors", func(t *testing.T) {
+		_, _, vaccinationRepo, portal := newPortal()
+
+		pet, err := portal.GetPet(ctx, cpf, rex.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Rex", pet.Name)
+
+		_, err = portal.GetPet(ctx, cpf, mia.ID)
+		assert.ErrorIs(t, err, service.ErrAnimalNotOwned)
+
+		_, err = portal.GetVaccinationCard(ctx, cpf, mia.ID)
+		assert.ErrorIs(t, err, service.ErrAnimalNotOwned)
+		vaccinationRepo.AssertNotCalled(t, "FindRecordsByAnimalID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("lists upcoming scheduled consultations in order", func(t *testing.T) {
+		consultationRepo, _, _, portal := newPortal()
+		consultationRepo.On("FindConsultationByAnimalID", ctx, rex.ID).Return([]model.Consultation{
+			{ID: uuid.New(), ConsultationDate: model.CustomDate{Time: day(2024, 6, 1)}, ConsultationHour: "09:00", ConsultationStatus: "scheduled"},
+			{ID: uuid.New(), ConsultationDate: model.CustomDate{Time: day(2024, 5, 10)}, ConsultationHour: "14:30", ConsultationStatus: "scheduled"},
+			{ID: uuid.New(), ConsultationDate: model.CustomDate{Time: day(2024, 5, 2)}, ConsultationHour: "10:00", ConsultationStatus: "scheduled"},
+			{ID: uuid.New(), ConsultationDate: model.CustomDate{Time: day(2024, 5, 20)}, ConsultationHour: "10:00", ConsultationStatus: "canceled"},
+		}, nil)
+
+		upcoming, err := portal.ListConsultations(ctx, cpf, false, now)
+		assert.NoError(t, err)
+		assert.Len(t, upcoming, 2)
+		assert.Equal(t, day(2024, 5, 10), upcoming[0].ConsultationDate.Time)
+		assert.Equal(t, day(2024, 6, 1), upcoming[1].ConsultationDate.Time)
+
+		all, err := portal.ListConsultations(ctx, cpf, true, now)
+		assert.NoError(t, err)
+		assert.Len(t, all, 4)
+	})
+
+	t.Run("lists active prescriptions", func(t *testing.T) {
+		_, dosageRepo, _, portal := newPortal()
+		dosageRepo.On("FindByAnimalID", ctx, rex.ID).Return([]model.Dosage{
+			{ID: uuid.New(), StartDate: model.CustomDate{Time: day(2024, 4, 1)}, EndDate: model.CustomDate{Time: day(2024, 4, 10)}},
+			{ID: uuid.New(), StartDate: model.CustomDate{Time: day(2024, 5, 5)}, EndDate: model.CustomDate{Time: day(2024, 5, 15)}},
+		}, nil)
+
+		active, err := portal.ListPrescriptions(ctx, cpf, true, now)
+		assert.NoError(t, err)
+		assert.Len(t, active, 1)
+		assert.Equal(t, day(2024, 5, 5), active[0].StartDate.Time)
+
+		all, err := portal.ListPrescriptions(ctx, cpf, false, now)
+		assert.NoError(t, err)
+		assert.Len(t, all, 2)
+		assert.Equal(t, day(2024, 5, 5), all[0].StartDate.Time)
+	})
+
+	t.Run("hides the history from before the animal was transferred to the tutor", func(t *testing.T) {
+		consultationRepo, dosageRepo, _, portal := newPortal(model.OwnershipTransfer{
+			AnimalID: rex.ID, PreviousCPF: "11144477735", NewCPF: cpf, EffectiveDate: model.CustomDate{Time: day(2024, 3, 1)},
+		})
+		consultationRepo.On("FindConsultationByAnimalID", ctx, rex.ID).Return([]model.Consultation{
+			{ID: uuid.New(), ConsultationDate: model.CustomDate{Time: day(2024, 2, 10)}, ConsultationHour: "09:00", ConsultationStatus: "completed"},
+			{ID: uuid.New(), ConsultationDate: model.CustomDate{Time: day(2024, 4, 10)}, ConsultationHour: "09:00", ConsultationStatus: "completed"},
+		}, nil)
+		dosageRepo.On("FindByAnimalID", ctx, rex.ID).Return([]model.Dosage{
+			{ID: uuid.New(), StartDate: model.CustomDate{Time: day(2024, 2, 10)}, EndDate: model.CustomDate{Time: day(2024, 2, 20)}},
+			{ID: uuid.New(), StartDate: model.CustomDate{Time: day(2024, 4, 10)}, EndDate: model.CustomDate{Time: day(2024, 4, 20)}},
+		}, nil)
+
+		consultations, err := portal.ListConsultations(ctx, cpf, true, now)
+		assert.NoError(t, err)
+		assert.Len(t, consultations, 1)
+		assert.Equal(t, day(2024, 4, 10), consultations[0].ConsultationDate.Time)
+
+		prescriptions, err := portal.ListPrescriptions(ctx, cpf, false, now)
+		assert.NoError(t, err)
+		assert.Len(t, prescriptions, 1)
+		assert.Equal(t, day(2024, 4, 10), prescriptions[0].StartDate.Time)
+	})
+}
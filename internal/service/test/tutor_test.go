@@ -0,0 +1,224 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// Mock do repositório de tutores
+type MockTutorRepo struct {
+	mock.Mock
+}
+
+var _ repository.TutorRepository = (*MockTutorRepo)(nil)
+
+func (m *MockTutorRepo) FindTutorByCPF(ctx context.Context, cpf string) (*model.Tutor, error) {
+	args := m.Called(ctx, cpf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tutor), args.Error(1)
+}
+
+func (m *MockTutorRepo) CreateTutor(ctx context.Context, tutor *model.Tutor) error {
+	args := m.Called(ctx, tutor)
+	return args.Error(0)
+}
+
+func (m *MockTutorRepo) CreateTutorWithAnimal(ctx context.Context, tutor *model.Tutor, animal *model.Animal) error {
+	args := m.Called(ctx, tutor, animal)
+	return args.Error(0)
+}
+
+func (m *MockTutorRepo) ListTutors(ctx context.Context, query repository.TutorQuery) ([]model.Tutor, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]model.Tutor), args.Error(1)
+}
+
+func (m *MockTutorRepo) UpdateTutor(ctx context.Context, tutor *model.Tutor) error {
+	args := m.Called(ctx, tutor)
+	return args.Error(0)
+}
+
+func (m *MockTutorRepo) FindTutorByUID(ctx context.Context, uid string) (*model.Tutor, error) {
+	args := m.Called(ctx, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tutor), args.Error(1)
+}
+
+func (m *MockTutorRepo) FindUnboundTutorByEmail(ctx context.Context, email string) (*model.Tutor, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tutor), args.Error(1)
+}
+
+func (m *MockTutorRepo) FindLegalRepresentatives(ctx context.Context, cnpj string) ([]model.LegalRepresentative, error) {
+	args := m.Called(ctx, cnpj)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.LegalRepresentative), args.Error(1)
+}
+
+func (m *MockTutorRepo) SaveLegalRepresentative(ctx context.Context, representative *model.LegalRepresentative) error {
+	args := m.Called(ctx, representative)
+	return args.Error(0)
+}
+
+func (m *MockTutorRepo) DeleteLegalRepresentative(ctx context.Context, representative *model.LegalRepresentative) error {
+	args := m.Called(ctx, representative)
+	return args.Error(0)
+}
+
+func (m *MockTutorRepo) CountTutorsByRegion(ctx context.Context, uf, city string) ([]repository.TutorRegionCount, error) {
+	args := m.Called(ctx, uf, city)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.TutorRegionCount), args.Error(1)
+}
+
+func (m *MockTutorRepo) FindTutorsWithLegacyAddress(ctx context.Context) ([]model.Tutor, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Tutor), args.Error(1)
+}
+
+// Endereço completo usado nos cadastros de tutores dos testes
+var floresAddress = model.Address{CEP: "01310-100", Street: "Rua das Flores", Number: "10", Neighborhood: "Bela Vista", City: "São Paulo", UF: "SP"}
+
+func TestTutorService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates a tutor with a formatted CPF", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorService := service.NewTutorService(tutorRepo)
+		tutorRepo.On("FindTutorByCPF", ctx, "52998224725").Return(nil, gorm.ErrRecordNotFound)
+		tutorRepo.On("CreateTutor", ctx, mock.AnythingOfType("*model.Tutor")).Return(nil)
+
+		tutor, err := tutorService.CreateTutor(ctx, "529.982.247-25", "Maria Souza", "maria@example.com", "11987654321", floresAddress)
+		assert.NoError(t, err)
+		assert.Equal(t, "52998224725", tutor.CPFTutor)
+		assert.True(t, tutor.Active)
+		assert.Equal(t, model.TutorType, tutor.UserType)
+		tutorRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects duplicated, invalid and incomplete tutors", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorService := service.NewTutorService(tutorRepo)
+		tutorRepo.On("FindTutorByCPF", ctx, "52998224725").Return(&model.Tutor{CPFTutor: "52998224725"}, nil)
+
+		_, err := tutorService.CreateTutor(ctx, "52998224725", "Maria Souza", "maria@example.com", "11987654321", floresAddress)
+		assert.ErrorIs(t, err, service.ErrTutorExists)
+
+		_, err = tutorService.CreateTutor(ctx, "11111111111", "João", "joao@example.com", "11912345678", model.Address{CEP: "20040-020", Street: "Rua B", Number: "20", Neighborhood: "Centro", City: "Rio de Janeiro", UF: "RJ"})
+		assert.ErrorIs(t, err, service.ErrInvalidCPF)
+
+		_, err = tutorService.CreateTutor(ctx, "11144477735", "Ana Lima", "ana", "11955554444", model.Address{CEP: "70040-010", Street: "Av. Central", Number: "300", Neighborhood: "Asa Sul", City: "Brasília", UF: "DF"})
+		assert.IsType(t, validator.ValidationErrors{}, err)
+		tutorRepo.AssertNotCalled(t, "CreateTutor", mock.Anything, mock.Anything)
+	})
+
+	t.Run("searches by phone digits and caps the page size", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorService := service.NewTutorService(tutorRepo)
+		tutorRepo.On("ListTutors", ctx, repository.TutorQuery{Phone: "11987654321", Limit: service.MaxTutorPageSize}).
+			Return([]model.Tutor{{CPFTutor: "52998224725"}}, nil)
+
+		tutors, err := tutorService.SearchTutors(ctx, repository.TutorQuery{Phone: "(11) 98765-4321", Limit: 1000})
+		assert.NoError(t, err)
+		assert.Len(t, tutors, 1)
+		tutorRepo.AssertExpectations(t)
+	})
+
+	t.Run("updates only the informed fields", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorService := service.NewTutorService(tutorRepo)
+		existing, _ := model.NewTutor("52998224725", "Maria Souza", "maria@example.com", "11987654321", floresAddress, "")
+		tutorRepo.On("FindTutorByCPF", ctx, "52998224725").Return(existing, nil)
+		tutorRepo.On("UpdateTutor", ctx, existing).Return(nil)
+
+		phone := "11911112222"
+		tutor, err := tutorService.UpdateTutor(ctx, "52998224725", service.TutorPatch{Phone: &phone})
+		assert.NoError(t, err)
+		assert.Equal(t, "11911112222", tutor.Phone)
+		assert.Equal(t, "Maria Souza", tutor.Name)
+		tutorRepo.AssertExpectations(t)
+	})
+
+	t.Run("deactivates and reactivates a tutor", func(t *testing.T) {
+		tutorRepo := new(MockTutorRepo)
+		tutorService := service.NewTutorService(tutorRepo)
+		existing := &model.Tutor{CPFTutor: "52998224725", Active: true}
+		tutorRepo.On("FindTutorByCPF", ctx, "52998224725").Return(existing, nil)
+		tutorRepo.On("UpdateTutor", ctx, existing).Return(nil)
+
+		tutor, err := tutorService.SetTutorActive(ctx, "52998224725", false)
+		assert.NoError(t, err)
+		assert.False(t, tutor.Active)
+		assert.ErrorIs(t, tutorService.CheckTutor(ctx, "52998224725"), service.ErrTutorInactive)
+
+		tutor, err = tutorService.SetTutorActive(ctx, "52998224725", true)
+		assert.NoError(t, err)
+		assert.True(t, tutor.Active)
+		assert.NoError(t, tutorService.CheckTutor(ctx, "52998224725"))
+		tutorRepo.AssertNumberOfCalls(t, "UpdateTutor", 2)
+	})
+}
+
+func TestAnimalTutorIntegrity(t *testing.T) {
+	newServices := func() (*repository.MockAnimalRepository, *service.AnimalService) {
+		tutorRepo := new(MockTutorRepo)
+		tutorRepo.On("FindTutorByCPF", mock.Anything, "52998224725").Return(&model.Tutor{CPFTutor: "52998224725", Active: true}, nil)
+		tutorRepo.On("FindTutorByCPF", mock.Anything, "11144477735").Return(nil, gorm.ErrRecordNotFound)
+		tutorRepo.On("FindTutorByCPF", mock.Anything, "12345678909").Return(&model.Tutor{CPFTutor: "12345678909"}, nil)
+
+		animalRepo := new(repository.MockAnimalRepository)
+		return animalRepo, service.NewAnimalService(animalRepo, service.WithTutors(service.NewTutorService(tutorRepo)))
+	}
+
+	t.Run("AddAnimal requires a registered and active tutor", func(t *testing.T) {
+		animalRepo, animalService := newServices()
+		animalRepo.On("FindByUniqueAttributes", mock.Anything).Return(nil, nil)
+		animalRepo.On("SaveAnimal", mock.Anything).Return(nil)
+
+		assert.NoError(t, animalService.AddAnimal(model.Animal{Name: "Rex", Species: "Dog", CPFTutor: "52998224725"}))
+		assert.ErrorIs(t, animalService.AddAnimal(model.Animal{Name: "Mia", Species: "Cat", CPFTutor: "11144477735"}), service.ErrUnknownTutor)
+		assert.ErrorIs(t, animalService.AddAnimal(model.Animal{Name: "Thor", Species: "Dog", CPFTutor: "12345678909"}), service.ErrTutorInactive)
+		animalRepo.AssertNumberOfCalls(t, "SaveAnimal", 1)
+	})
+
+	t.Run("PatchAnimal rejects tutor changes and TransferOwnership rejects unknown tutors", func(t *testing.T) {
+		animalRepo, animalService := newServices()
+		animalID := uuid.New()
+		animalRepo.On("FindAnimalByID", animalID).Return(&model.Animal{ID: animalID, Name: "Rex", Species: "Dog", CPFTutor: "52998224725"}, nil)
+
+		cpf := "11144477735"
+		_, err := animalService.PatchAnimal(animalID, service.AnimalPatch{CPFTutor: &cpf}, "vet-uid")
+		assert.ErrorIs(t, err, service.ErrTutorChangeNotAllowed)
+
+		_, err = animalService.TransferOwnership(animalID, cpf, day(2024, 3, 1), "Adoção", "vet-uid")
+		assert.ErrorIs(t, err, service.ErrUnknownTutor)
+
+		_, err = animalService.TransferOwnership(animalID, "12345678909", day(2024, 3, 1), "Adoção", "vet-uid")
+		assert.ErrorIs(t, err, service.ErrTutorInactive)
+		animalRepo.AssertNotCalled(t, "UpdateAnimal", mock.Anything, mock.Anything)
+		animalRepo.AssertNotCalled(t, "TransferOwnership", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
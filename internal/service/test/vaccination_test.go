@@ -0,0 +1,262 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock do repositório de vacinação
+type MockVaccinationRepo struct {
+	mock.Mock
+}
+
+var _ repository.VaccinationRepository = (*MockVaccinationRepo)(nil)
+
+func (m *MockVaccinationRepo) ListProtocols(ctx context.Context, species string) ([]model.VaccinationProtocol, error) {
+	args := m.Called(ctx, species)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.VaccinationProtocol), args.Error(1)
+}
+
+func (m *MockVaccinationRepo) FindProtocolByID(ctx context.Context, id uuid.UUID) (*model.VaccinationProtocol, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.VaccinationProtocol), args.Error(1)
+}
+
+func (m *MockVaccinationRepo) SaveProtocol(ctx context.Context, protocol *model.VaccinationProtocol) error {
+	args := m.Called(ctx, protocol)
+	return args.Error(0)
+}
+
+func (m *MockVaccinationRepo) ListProducts(ctx context.Context) ([]model.VaccineProduct, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.VaccineProduct), args.Error(1)
+}
+
+func (m *MockVaccinationRepo) FindProductByID(ctx context.Context, id uuid.UUID) (*model.VaccineProduct, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.VaccineProduct), args.Error(1)
+}
+
+func (m *MockVaccinationRepo) SaveProduct(ctx context.Context, product *model.VaccineProduct) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+func (m *MockVaccinationRepo) FindBatch(ctx context.Context, medicationID uuid.UUID) (*model.Medication, error) {
+	args := m.Called(ctx, medicationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Medication), args.Error(1)
+}
+
+func (m *MockVaccinationRepo) CreateRecord(ctx context.Context, record *model.VaccinationRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockVaccinationRepo) FindRecordsByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.VaccinationRecord, error) {
+	args := m.Called(ctx, animalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.VaccinationRecord), args.Error(1)
+}
+
+func (m *MockVaccinationRepo) FindDue(ctx context.Context, until time.Time, cpfTutor string) ([]repository.VaccinationDue, error) {
+	args := m.Called(ctx, until, cpfTutor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.VaccinationDue), args.Error(1)
+}
+
+var v10Protocol = model.VaccinationProtocol{ID: uuid.New(), Code: "dog_v10", Name: "V10", Species: "Dog", Doses: 3, DoseIntervalDays: 21, BoosterIntervalDays: 365}
+
+func TestVaccinationProtocolNextDueDate(t *testing.T) {
+	applied := day(2024, 3, 1)
+
+	assert.Equal(t, day(2024, 3, 22), v10Protocol.NextDueDate(1, applied).Time)
+	assert.Equal(t, day(2024, 3, 22), v10Protocol.NextDueDate(2, applied).Time)
+	assert.Equal(t, day(2025, 3, 1), v10Protocol.NextDueDate(3, applied).Time) // fim da série: reforço anual
+	assert.Equal(t, day(2025, 3, 1), v10Protocol.NextDueDate(4, applied).Time)
+
+	singleDose := model.VaccinationProtocol{Doses: 1}
+	assert.Nil(t, singleDose.NextDueDate(1, applied))
+}
+
+func TestRecordVaccination(t *testing.T) {
+	now := day(2024, 3, 22)
+	dog := &model.Animal{ID: uuid.New(), Name: "Rex", Species: "Dog"}
+	product := &model.VaccineProduct{ID: uuid.New(), Name: "Vanguard Plus", ProtocolID: v10Protocol.ID}
+
+	setup := func() (*MockVaccinationRepo, *service.VaccinationService) {
+		vaccinationRepo := new(MockVaccinationRepo)
+		animalRepo := new(repository.MockAnimalRepository)
+		animalRepo.On("FindAnimalByID", dog.ID).Return(dog, nil)
+		vaccinationRepo.On("FindProductByID", mock.Anything, product.ID).Return(product, nil)
+		vaccinationRepo.On("FindProtocolByID", mock.Anything, v10Protocol.ID).Return(&v10Protocol, nil)
+		return vaccinationRepo, service.NewVaccinationService(vaccinationRepo, animalRepo)
+	}
+
+	t.Run("Continues the series and computes the next due date", func(t *testing.T) {
+		vaccinationRepo, vaccinationService := setup()
+		vaccinationRepo.On("FindRecordsByAnimalID", mock.Anything, dog.ID).Return([]model.VaccinationRecord{
+			{ProtocolID: v10Protocol.ID, DoseNumber: 1},
+			{ProtocolID: uuid.New(), DoseNumber: 5}, // outro protocolo
+		}, nil)
+		vaccinationRepo.On("CreateRecord", mock.Anything, mock.AnythingOfType("*model.VaccinationRecord")).Return(nil)
+
+		record := &model.VaccinationRecord{AnimalID: dog.ID, ProductID: product.ID, LotNumber: "A123"}
+		err := vaccinationService.RecordVaccination(context.Background(), record, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, record.DoseNumber)
+		assert.Equal(t, v10Protocol.ID, record.ProtocolID)
+		assert.Equal(t, now, record.AppliedAt.Time)
+		assert.Equal(t, day(2024, 4, 12), record.NextDueDate.Time)
+	})
+
+	t.Run("Copies the lot number from the stock batch", func(t *testing.T) {
+		vaccinationRepo, vaccinationService := setup()
+		batch := &model.Medication{ID: uuid.New(), BatchNumber: "L-2024-07", Quantity: 10, Expiration: day(2025, 1, 1), VaccineProductID: &product.ID}
+		vaccinationRepo.On("FindBatch", mock.Anything, batch.ID).Return(batch, nil)
+		vaccinationRepo.On("CreateRecord", mock.Anything, mock.AnythingOfType("*model.VaccinationRecord")).Return(nil)
+
+		record := &model.VaccinationRecord{AnimalID: dog.ID, ProductID: product.ID, MedicationID: &batch.ID, DoseNumber: 3}
+		err := vaccinationService.RecordVaccination(context.Background(), record, now)
+		assert.NoError(t, err)
+		assert.Equal(t, "L-2024-07", record.LotNumber)
+		assert.Equal(t, day(2025, 3, 22), record.NextDueDate.Time)
+	})
+
+	t.Run("Rejects a batch of another product", func(t *testing.T) {
+		vaccinationRepo, vaccinationService := setup()
+		otherProduct := uuid.New()
+		batch := &model.Medication{ID: uuid.New(), BatchNumber: "X", Quantity: 10, Expiration: day(2025, 1, 1), VaccineProductID: &otherProduct}
+		vaccinationRepo.On("FindBatch", mock.Anything, batch.ID).Return(batch, nil)
+
+		record := &model.VaccinationRecord{AnimalID: dog.ID, ProductID: product.ID, MedicationID: &batch.ID, DoseNumber: 1}
+		err := vaccinationService.RecordVaccination(context.Background(), record, now)
+		assert.ErrorIs(t, err, service.ErrBatchNotOfProduct)
+	})
+
+	t.Run("Rejects an expired batch", func(t *testing.T) {
+		vaccinationRepo, vaccinationService := setup()
+		batch := &model.Medication{ID: uuid.New(), BatchNumber: "X", Quantity: 10, Expiration: day(2024, 1, 1), VaccineProductID: &product.ID}
+		vaccinationRepo.On("FindBatch", mock.Anything, batch.ID).Return(batch, nil)
+
+		record := &model.VaccinationRecord{AnimalID: dog.ID, ProductID: product.ID, MedicationID: &batch.ID, DoseNumber: 1}
+		err := vaccinationService.RecordVaccination(context.Background(), record, now)
+		assert.ErrorIs(t, err, service.ErrBatchExpired)
+	})
+
+	t.Run("Rejects a batch that runs out before the record is saved", func(t *testing.T) {
+		vaccinationRepo, vaccinationService := setup()
+		batch := &model.Medication{ID: uuid.New(), BatchNumber: "X", Quantity: 1, Expiration: day(2025, 1, 1), VaccineProductID: &product.ID}
+		vaccinationRepo.On("FindBatch", mock.Anything, batch.ID).Return(batch, nil)
+		vaccinationRepo.On("CreateRecord", mock.Anything, mock.AnythingOfType("*model.VaccinationRecord")).Return(repository.ErrBatchOutOfStock)
+
+		record := &model.VaccinationRecord{AnimalID: dog.ID, ProductID: product.ID, MedicationID: &batch.ID, DoseNumber: 1}
+		err := vaccinationService.RecordVaccination(context.Background(), record, now)
+		assert.ErrorIs(t, err, service.ErrBatchOutOfStock)
+	})
+
+	t.Run("Rejects a protocol of another species", func(t *testing.T) {
+		vaccinationRepo := new(MockVaccinationRepo)
+		animalRepo := new(repository.MockAnimalRepository)
+		cat := &model.Animal{ID: uuid.New(), Species: "Cat"}
+		animalRepo.On("FindAnimalByID", cat.ID).Return(cat, nil)
+		vaccinationRepo.On("FindProductByID", mock.Anything, product.ID).Return(product, nil)
+		vaccinationRepo.On("FindProtocolByID", mock.Anything, v10Protocol.ID).Return(&v10Protocol, nil)
+		vaccinationService := service.NewVaccinationService(vaccinationRepo, animalRepo)
+
+		record := &model.VaccinationRecord{AnimalID: cat.ID, ProductID: product.ID, LotNumber: "A123"}
+		err := vaccinationService.RecordVaccination(context.Background(), record, now)
+		assert.ErrorIs(t, err, service.ErrProtocolSpeciesMismatch)
+		vaccinationRepo.AssertNotCalled(t, "CreateRecord", mock.Anything, mock.Anything)
+	})
+}
+
+func TestListDueVaccinations(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)
+	due := []repository.VaccinationDue{
+		{AnimalName: "Rex", ProtocolName: "Antirrábica", NextDueDate: day(2024, 3, 1)},
+		{AnimalName: "Mia", ProtocolName: "V4", NextDueDate: day(2024, 3, 10)},
+		{AnimalName: "Thor", ProtocolName: "V10", NextDueDate: day(2024, 3, 25)},
+	}
+
+	vaccinationRepo := new(MockVaccinationRepo)
+	vaccinationService := service.NewVaccinationService(vaccinationRepo, new(repository.MockAnimalRepository))
+	vaccinationRepo.On("FindDue", mock.Anything, day(2024, 4, 9), "12345678909").Return(due, nil)
+
+	items, err := vaccinationService.ListDue(context.Background(), "12345678909", 30, now)
+	assert.NoError(t, err)
+	assert.Len(t, items, 3)
+	assert.Equal(t, service.VaccinationOverdue, items[0].Status)
+	assert.Equal(t, -9, items[0].DaysUntilDue)
+	assert.Equal(t, service.VaccinationUpcoming, items[1].Status)
+	assert.Equal(t, 0, items[1].DaysUntilDue)
+	assert.Equal(t, 15, items[2].DaysUntilDue)
+}
+
+func TestVaccinationReminders(t *testing.T) {
+	ctx := context.Background()
+	now := day(2024, 5, 1)
+	consenting, silent := "52998224725", "11144477735"
+	due := []repository.VaccinationDue{
+		{AnimalID: uuid.New(), AnimalName: "Rex", CPFTutor: consenting, ProtocolName: "V10", NextDueDate: day(2024, 5, 8)},
+		{AnimalID: uuid.New(), AnimalName: "Bob", CPFTutor: consenting, ProtocolName: "Antirrábica", NextDueDate: day(2024, 5, 1)},
+		{AnimalID: uuid.New(), AnimalName: "Mel", CPFTutor: consenting, ProtocolName: "V10", NextDueDate: day(2024, 5, 4)},
+		{AnimalID: uuid.New(), AnimalName: "Thor", CPFTutor: consenting, ProtocolName: "V10", NextDueDate: day(2024, 4, 20)},
+		{AnimalID: uuid.New(), AnimalName: "Mia", CPFTutor: silent, ProtocolName: "V5", NextDueDate: day(2024, 5, 8)},
+	}
+	vaccinationRepo := new(MockVaccinationRepo)
+	vaccinationRepo.On("FindDue", ctx, day(2024, 5, 8), "").Return(due, nil)
+
+	consentRepo := new(MockConsentRepo)
+	consentRepo.On("FindConsentsByTutor", ctx, consenting).Return([]model.TutorConsent{
+		{ID: uuid.New(), CPFTutor: consenting, Channel: model.ConsentChannelEmail, Purpose: model.ConsentPurposeClinicalReminders, Source: model.ConsentSourcePortal},
+	}, nil)
+	consentRepo.On("FindConsentsByTutor", ctx, silent).Return([]model.TutorConsent{}, nil)
+	tutorRepo := new(MockTutorRepo)
+	tutorRepo.On("FindTutorByCPF", ctx, consenting).Return(&model.Tutor{CPFTutor: consenting, Active: true}, nil)
+	tutorRepo.On("FindTutorByCPF", ctx, silent).Return(&model.Tutor{CPFTutor: silent, Active: true}, nil)
+
+	email := new(MockNotificationSender)
+	var sent []service.Notification
+	email.On("Send", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sent = append(sent, args.Get(2).(service.Notification))
+	}).Return(nil)
+	notifications := service.NewNotificationService(service.NewConsentService(consentRepo, tutorRepo), map[string]service.NotificationSender{
+		model.ConsentChannelEmail: email,
+	})
+	reminders := service.NewVaccinationReminderService(service.NewVaccinationService(vaccinationRepo, new(repository.MockAnimalRepository)), notifications)
+
+	report, err := reminders.SendReminders(ctx, 7, now)
+	assert.NoError(t, err)
+	assert.Equal(t, &service.VaccinationReminderReport{Sent: 2, NoConsent: 1}, report)
+	assert.Len(t, sent, 2)
+	assert.Equal(t, "Lembrete de vacina: Rex", sent[0].Subject)
+	assert.Contains(t, sent[0].Message, "vence em 08/05/2024")
+	assert.Contains(t, sent[1].Message, "vence hoje")
+}
@@ -0,0 +1,151 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func statsConsultation(animalID uuid.UUID, date, status, kind, reason string, price float64) model.Consultation {
+	return model.Consultation{
+		ID:                 uuid.New(),
+		AnimalID:           animalID,
+		CRVM:               "654321-RJ",
+		ConsultationDate:   model.CustomDate{Time: at(date + " 00:00")},
+		ConsultationHour:   "10:00",
+		ConsultationType:   kind,
+		ConsultationStatus: status,
+		ConsultationPrice:  price,
+		Reason:             reason,
+	}
+}
+
+func TestBuildVeterinaryStats(t *testing.T) {
+	rex, mia, bob := uuid.New(), uuid.New(), uuid.New()
+	consultations := []model.Consultation{
+		statsConsultation(rex, "2024-04-29", model.ConsultationCompleted, "rotina", "Vacinação anual", 150),
+		statsConsultation(rex, "2024-05-02", model.ConsultationCompleted, "retorno", "vacinação  ANUAL", 80),
+		statsConsultation(mia, "2024-05-02", model.ConsultationNoShow, "rotina", "Dermatite", 150),
+		statsConsultation(bob, "2024-05-06", model.ConsultationCanceled, "rotina", "Dermatite", 150),
+		statsConsultation(mia, "2024-05-07", model.ConsultationScheduled, "rotina", "Dermatite", 200),
+	}
+
+	stats := service.BuildVeterinaryStats("654321-RJ", at("2024-04-29 00:00"), at("2024-05-12 00:00"), consultations)
+	assert.Equal(t, 5, stats.Total)
+	assert.Equal(t, map[string]int{"completed": 2, "no_show": 1, "canceled": 1, "scheduled": 1}, stats.ByStatus)
+	assert.Equal(t, map[string]int{"rotina": 4, "retorno": 1}, stats.ByType)
+	assert.Equal(t, 230.0, stats.Revenue)
+	assert.Equal(t, 200.0, stats.ExpectedRevenue)
+	assert.Equal(t, 2, stats.DistinctPatients, "canceled consultations do not count as patients")
+	assert.InDelta(t, 0.3333, stats.NoShowRate, 0.0001)
+	assert.Equal(t, []service.ReasonCount{{Reason: "Dermatite", Count: 3}, {Reason: "Vacinação anual", Count: 2}}, stats.TopReasons)
+
+	t.Run("series cover the whole period", func(t *testing.T) {
+		assert.Len(t, stats.PerDay, 14)
+		assert.Equal(t, "2024-05-02", stats.PerDay[3].Period)
+		assert.Equal(t, 2, stats.PerDay[3].Total)
+		assert.Equal(t, 80.0, stats.PerDay[3].Revenue)
+		assert.Equal(t, 0, stats.PerDay[1].Total)
+
+		// 2024-04-29 é uma segunda-feira
+		assert.Equal(t, []string{"2024-04-29", "2024-05-06"}, []string{stats.PerWeek[0].Period, stats.PerWeek[1].Period})
+		assert.Equal(t, 3, stats.PerWeek[0].Total)
+		assert.Equal(t, 1, stats.PerWeek[1].ByStatus[model.ConsultationScheduled])
+
+		assert.Equal(t, []string{"2024-04", "2024-05"}, []string{stats.PerMonth[0].Period, stats.PerMonth[1].Period})
+		assert.Equal(t, 150.0, stats.PerMonth[0].Revenue)
+		assert.Equal(t, 4, stats.PerMonth[1].Total)
+	})
+
+	t.Run("empty period", func(t *testing.T) {
+		empty := service.BuildVeterinaryStats("654321-RJ", at("2024-05-01 00:00"), at("2024-05-01 00:00"), nil)
+		assert.Equal(t, 0, empty.Total)
+		assert.Equal(t, 0.0, empty.NoShowRate)
+		assert.Empty(t, empty.TopReasons)
+		assert.Len(t, empty.PerDay, 1)
+	})
+}
+
+func TestGetVeterinaryStats(t *testing.T) {
+	ctx := context.Background()
+	vetRepo := new(MockVeterinaryRepo)
+	vetRepo.On("FindVeterinaryByCRVM", ctx, "654321-RJ").Return(&model.Veterinary{CRVM: "654321-RJ"}, nil)
+	consultationRepo := new(MockConsultationRepo)
+	consultationRepo.On("FindConsultationByVeterinaryCRVMAndDateRange", ctx, "654321-RJ", "2024-05-01", "2024-05-31").
+		Return([]model.Consultation{statsConsultation(uuid.New(), "2024-05-02", model.ConsultationCompleted, "rotina", "Check-up geral", 120)}, nil)
+	statsService := service.NewVeterinaryStatsService(consultationRepo, vetRepo)
+
+	stats, err := statsService.GetVeterinaryStats(ctx, "crmv-rj 654321", at("2024-05-01 00:00"), at("2024-05-31 00:00"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.Total)
+	assert.Equal(t, 120.0, stats.Revenue)
+
+	_, err = statsService.GetVeterinaryStats(ctx, "654321-RJ", at("2024-05-31 00:00"), at("2024-05-01 00:00"))
+	assert.ErrorIs(t, err, service.ErrInvalidStatsRange)
+	_, err = statsService.GetVeterinaryStats(ctx, "654321-RJ", at("2023-01-01 00:00"), at("2024-05-01 00:00"))
+	assert.ErrorIs(t, err, service.ErrInvalidStatsRange)
+}
+
+func TestUpdateConsultationStatus(t *testing.T) {
+	consultation := statsConsultation(uuid.New(), "2024-05-06", model.ConsultationScheduled, "rotina", "Check-up geral", 120)
+	newRepo := func() *MockConsultationRepo {
+		repo := new(MockConsultationRepo)
+		scheduled := consultation
+		repo.On("FindConsultationByID", mock.Anything, consultation.ID).Return(&scheduled, nil)
+		repo.On("SaveConsultation", mock.Anything, mock.AnythingOfType("*model.Consultation")).Return(nil)
+		return repo
+	}
+
+	t.Run("marks a past consultation as no-show", func(t *testing.T) {
+		updated, err := service.UpdateConsultationStatus(newRepo(), consultation.ID, model.ConsultationNoShow, at("2024-05-06 10:30"))
+		assert.NoError(t, err)
+		assert.Equal(t, model.ConsultationNoShow, updated.ConsultationStatus)
+	})
+
+	t.Run("rejects no-show before the scheduled time and unknown statuses", func(t *testing.T) {
+		repo := newRepo()
+		_, err := service.UpdateConsultationStatus(repo, consultation.ID, model.ConsultationNoShow, at("2024-05-06 09:59"))
+		assert.ErrorIs(t, err, service.ErrConsultationNotStarted)
+		_, err = service.UpdateConsultationStatus(repo, consultation.ID, "missed", at("2024-05-07 10:00"))
+		assert.ErrorIs(t, err, service.ErrInvalidConsultationStatus)
+		repo.AssertNotCalled(t, "SaveConsultation", mock.Anything, mock.Anything)
+
+		_, err = service.UpdateConsultationStatus(repo, consultation.ID, model.ConsultationCanceled, at("2024-05-01 10:00"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("closed consultations do not change status", func(t *testing.T) {
+		closed := []string{model.ConsultationCompleted, model.ConsultationCanceled, model.ConsultationNoShow}
+		targets := []string{model.ConsultationScheduled, model.ConsultationCompleted, model.ConsultationCanceled, model.ConsultationNoShow}
+		for _, from := range closed {
+			for _, to := range targets {
+				t.Run(from+" to "+to, func(t *testing.T) {
+					repo := new(MockConsultationRepo)
+					current := consultation
+					current.ConsultationStatus = from
+					repo.On("FindConsultationByID", mock.Anything, consultation.ID).Return(&current, nil)
+
+					_, err := service.UpdateConsultationStatus(repo, consultation.ID, to, at("2024-05-07 10:00"))
+					assert.ErrorIs(t, err, service.ErrConsultationNotReopenable)
+					repo.AssertNotCalled(t, "SaveConsultation", mock.Anything, mock.Anything)
+				})
+			}
+		}
+	})
+
+	t.Run("compares the scheduled hour in the clinic time zone", func(t *testing.T) {
+		saoPaulo := time.FixedZone("BRT", -3*60*60)
+		// 12:30 UTC são 09:30 em Brasília, antes das 10:00 agendadas
+		_, err := service.UpdateConsultationStatus(newRepo(), consultation.ID, model.ConsultationCompleted, at("2024-05-06 12:30").In(saoPaulo))
+		assert.ErrorIs(t, err, service.ErrConsultationNotStarted)
+
+		_, err = service.UpdateConsultationStatus(newRepo(), consultation.ID, model.ConsultationCompleted, at("2024-05-06 13:05").In(saoPaulo))
+		assert.NoError(t, err)
+	})
+}
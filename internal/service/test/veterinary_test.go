@@ -0,0 +1,210 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockVeterinaryRepo struct {
+	mock.Mock
+}
+
+var _ repository.VeterinaryRepository = (*MockVeterinaryRepo)(nil)
+
+func (m *MockVeterinaryRepo) FindVeterinaryByCRVM(ctx context.Context, crvm string) (*model.Veterinary, error) {
+	args := m.Called(ctx, crvm)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Veterinary), args.Error(1)
+}
+
+func (m *MockVeterinaryRepo) FindVeterinaryIncludingDeleted(ctx context.Context, crvm string) (*model.Veterinary, error) {
+	args := m.Called(ctx, crvm)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Veterinary), args.Error(1)
+}
+
+func (m *MockVeterinaryRepo) FindVeterinaryByUID(ctx context.Context, uid string) (*model.Veterinary, error) {
+	args := m.Called(ctx, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Veterinary), args.Error(1)
+}
+
+func (m *MockVeterinaryRepo) FindUnboundVeterinaryByEmail(ctx context.Context, email string) (*model.Veterinary, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Veterinary), args.Error(1)
+}
+
+func (m *MockVeterinaryRepo) ListVeterinaries(ctx context.Context, query repository.VeterinaryQuery) ([]model.Veterinary, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]model.Veterinary), args.Error(1)
+}
+
+func (m *MockVeterinaryRepo) CreateVeterinary(ctx context.Context, veterinary *model.Veterinary) error {
+	args := m.Called(ctx, veterinary)
+	return args.Error(0)
+}
+
+func (m *MockVeterinaryRepo) UpdateVeterinary(ctx context.Context, veterinary *model.Veterinary) error {
+	args := m.Called(ctx, veterinary)
+	return args.Error(0)
+}
+
+func (m *MockVeterinaryRepo) DeleteVeterinary(ctx context.Context, veterinary *model.Veterinary) error {
+	args := m.Called(ctx, veterinary)
+	return args.Error(0)
+}
+
+func (m *MockVeterinaryRepo) RestoreVeterinary(ctx context.Context, veterinary *model.Veterinary) error {
+	args := m.Called(ctx, veterinary)
+	return args.Error(0)
+}
+
+func (m *MockVeterinaryRepo) FindLicenses(ctx context.Context, crvm string) ([]model.VetLicense, error) {
+	args := m.Called(ctx, crvm)
+	return args.Get(0).([]model.VetLicense), args.Error(1)
+}
+
+func (m *MockVeterinaryRepo) SaveLicense(ctx context.Context, license *model.VetLicense) error {
+	args := m.Called(ctx, license)
+	return args.Error(0)
+}
+
+func (m *MockVeterinaryRepo) DeleteLicense(ctx context.Context, license *model.VetLicense) error {
+	args := m.Called(ctx, license)
+	return args.Error(0)
+}
+
+func (m *MockVeterinaryRepo) FindLicensesExpiringBy(ctx context.Context, date string) ([]model.VetLicense, error) {
+	args := m.Called(ctx, date)
+	return args.Get(0).([]model.VetLicense), args.Error(1)
+}
+
+func TestCRMVValidation(t *testing.T) {
+	assert.Equal(t, "123456-SP", model.CleanCRMV("123456-SP"))
+	assert.Equal(t, "123456-SP", model.CleanCRMV(" crmv-sp 123456"))
+	assert.Equal(t, "123456-SP", model.CleanCRMV("SP-123456"))
+	assert.Equal(t, "123456-SP", model.CleanCRMV("123456/sp"))
+	assert.True(t, model.IsValidCRMV("1234567-MG"))
+	assert.False(t, model.IsValidCRMV("123456-XX"))
+	assert.False(t, model.IsValidCRMV("12345-SP"))
+	assert.False(t, model.IsValidCRMV(model.CleanCRMV("123456")))
+
+	vet, err := model.NewVeterinarian("CRMV-RJ 654321", "Ana", "ana@example.com", "21987654321", "Felinos")
+	assert.NoError(t, err)
+	assert.Equal(t, "654321-RJ", vet.CRVM)
+	assert.Equal(t, model.VeterinarianType, vet.UserType)
+	_, err = model.NewVeterinarian("654321", "Ana", "ana@example.com", "21987654321", "")
+	assert.Error(t, err)
+}
+
+func TestVeterinaryService(t *testing.T) {
+	ctx := context.Background()
+	newVeterinary := func() *model.Veterinary {
+		return &model.Veterinary{
+			User: model.User{Email: "ana@example.com", Phone: "21987654321"},
+			CRVM: "crmv-rj 654321", Name: " Ana ", LastName: "Lima", Specialty: "Felinos",
+		}
+	}
+
+	t.Run("creates a veterinary with the CRMV normalized", func(t *testing.T) {
+		repo := new(MockVeterinaryRepo)
+		repo.On("FindVeterinaryIncludingDeleted", ctx, "654321-RJ").Return(nil, gorm.ErrRecordNotFound)
+		repo.On("CreateVeterinary", ctx, mock.AnythingOfType("*model.Veterinary")).Return(nil)
+		repo.On("SaveLicense", ctx, mock.MatchedBy(func(license *model.VetLicense) bool {
+			return license.CRVM == "654321-RJ" && license.UF == "RJ" && license.Number == "654321-RJ" && license.ExpiresAt == nil
+		})).Return(nil).Once()
+
+		vet, err := service.NewVeterinaryService(repo).CreateVeterinary(ctx, newVeterinary())
+		assert.NoError(t, err)
+		assert.Equal(t, "654321-RJ", vet.CRVM)
+		assert.Equal(t, "Ana", vet.Name)
+		assert.Equal(t, model.VeterinarianType, vet.UserType)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid and duplicate CRMVs", func(t *testing.T) {
+		repo := new(MockVeterinaryRepo)
+		repo.On("FindVeterinaryIncludingDeleted", ctx, "654321-RJ").Return(&model.Veterinary{CRVM: "654321-RJ"}, nil)
+		vetService := service.NewVeterinaryService(repo)
+
+		invalid := newVeterinary()
+		invalid.CRVM = "654321"
+		_, err := vetService.CreateVeterinary(ctx, invalid)
+		assert.ErrorIs(t, err, service.ErrInvalidCRMV)
+		_, err = vetService.CreateVeterinary(ctx, newVeterinary())
+		assert.ErrorIs(t, err, service.ErrVeterinaryExists)
+
+		noEmail := newVeterinary()
+		noEmail.Email = ""
+		_, err = vetService.CreateVeterinary(ctx, noEmail)
+		assert.IsType(t, validator.ValidationErrors{}, err)
+		repo.AssertNotCalled(t, "CreateVeterinary", mock.Anything, mock.Anything)
+	})
+
+	t.Run("updates only the informed fields", func(t *testing.T) {
+		repo := new(MockVeterinaryRepo)
+		existing := &model.Veterinary{User: model.User{Email: "ana@example.com", Phone: "21987654321", UserType: model.VeterinarianType},
+			CRVM: "654321-RJ", Name: "Ana", LastName: "Lima"}
+		repo.On("FindVeterinaryByCRVM", ctx, "654321-RJ").Return(existing, nil)
+		repo.On("UpdateVeterinary", ctx, existing).Return(nil)
+
+		specialty := " Dermatologia "
+		vet, err := service.NewVeterinaryService(repo).UpdateVeterinary(ctx, "654321/rj", service.VeterinaryPatch{Specialty: &specialty})
+		assert.NoError(t, err)
+		assert.Equal(t, "Dermatologia", vet.Specialty)
+		assert.Equal(t, "Ana", vet.Name)
+	})
+
+	t.Run("deleted veterinarians are not accepted for new records", func(t *testing.T) {
+		repo := new(MockVeterinaryRepo)
+		repo.On("FindVeterinaryByCRVM", ctx, "654321-RJ").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.NewVeterinaryService(repo).FindVeterinary("654321-rj")
+		assert.ErrorIs(t, err, service.ErrUnknownVeterinarian)
+	})
+
+	t.Run("resolves the veterinarian of an authenticated user", func(t *testing.T) {
+		repo := new(MockVeterinaryRepo)
+		unbound := &model.Veterinary{User: model.User{Email: "ana@example.com"}, CRVM: "654321-RJ"}
+		repo.On("FindVeterinaryByUID", ctx, "uid-1").Return(nil, gorm.ErrRecordNotFound)
+		repo.On("FindUnboundVeterinaryByEmail", ctx, "ana@example.com").Return(unbound, nil)
+		repo.On("UpdateVeterinary", ctx, unbound).Return(nil).Once()
+		vetService := service.NewVeterinaryService(repo)
+
+		vet, err := vetService.ResolveVeterinary(ctx, "uid-1", "ana@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, "uid-1", *vet.FirebaseUID)
+
+		_, err = vetService.ResolveVeterinary(ctx, "uid-1", "")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("restores a deleted veterinary", func(t *testing.T) {
+		repo := new(MockVeterinaryRepo)
+		deleted := &model.Veterinary{CRVM: "654321-RJ", DeletedAt: gorm.DeletedAt{Valid: true}}
+		repo.On("FindVeterinaryIncludingDeleted", ctx, "654321-RJ").Return(deleted, nil)
+		repo.On("RestoreVeterinary", ctx, deleted).Return(nil).Once()
+
+		_, err := service.NewVeterinaryService(repo).RestoreVeterinary(ctx, "654321-RJ")
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+}
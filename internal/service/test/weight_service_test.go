@@ -0,0 +1,132 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+	"vetblock/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock do repositório de pesagens
+type MockWeightRepo struct {
+	mock.Mock
+}
+
+var _ repository.WeightRepository = (*MockWeightRepo)(nil)
+
+func (m *MockWeightRepo) Create(ctx context.Context, record *model.WeightRecord, updateAnimalWeight bool) error {
+	args := m.Called(ctx, record, updateAnimalWeight)
+	return args.Error(0)
+}
+
+func (m *MockWeightRepo) FindByAnimalID(ctx context.Context, animalID uuid.UUID) ([]model.WeightRecord, error) {
+	args := m.Called(ctx, animalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.WeightRecord), args.Error(1)
+}
+
+func weightOn(day int, weight float64, unit string) model.WeightRecord {
+	return model.WeightRecord{
+		Date:   model.CustomDate{Time: time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)},
+		Weight: weight,
+		Unit:   unit,
+	}
+}
+
+func TestAddWeightRecord(t *testing.T) {
+	animalID := uuid.New()
+	now := time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Pesagem mais recente atualiza o peso do animal", func(t *testing.T) {
+		mockRepo := new(MockWeightRepo)
+		weightService := service.NewWeightService(mockRepo)
+
+		record := weightOn(10, 12, "")
+		record.AnimalID = animalID
+
+		mockRepo.On("FindByAnimalID", mock.Anything, animalID).Return([]model.WeightRecord{weightOn(1, 11, "kg")}, nil)
+		mockRepo.On("Create", mock.Anything, &record, true).Return(nil)
+
+		err := weightService.AddWeightRecord(context.Background(), &record, now)
+		assert.NoError(t, err)
+		assert.Equal(t, model.WeightUnitKg, record.Unit)
+		assert.NotEqual(t, uuid.Nil, record.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Pesagem retroativa não altera o peso do animal", func(t *testing.T) {
+		mockRepo := new(MockWeightRepo)
+		weightService := service.NewWeightService(mockRepo)
+
+		record := weightOn(5, 11.5, "kg")
+		record.AnimalID = animalID
+
+		mockRepo.On("FindByAnimalID", mock.Anything, animalID).Return([]model.WeightRecord{weightOn(10, 12, "kg")}, nil)
+		mockRepo.On("Create", mock.Anything, &record, false).Return(nil)
+
+		err := weightService.AddWeightRecord(context.Background(), &record, now)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Pesagem com data futura", func(t *testing.T) {
+		mockRepo := new(MockWeightRepo)
+		weightService := service.NewWeightService(mockRepo)
+
+		record := weightOn(21, 13, "kg")
+		record.AnimalID = animalID
+
+		err := weightService.AddWeightRecord(context.Background(), &record, now)
+		assert.ErrorIs(t, err, service.ErrFutureWeightRecord)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unidade inválida", func(t *testing.T) {
+		mockRepo := new(MockWeightRepo)
+		weightService := service.NewWeightService(mockRepo)
+
+		record := weightOn(5, 11.5, "oz")
+		record.AnimalID = animalID
+
+		err := weightService.AddWeightRecord(context.Background(), &record, now)
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Erro ao buscar pesagens", func(t *testing.T) {
+		mockRepo := new(MockWeightRepo)
+		weightService := service.NewWeightService(mockRepo)
+
+		record := weightOn(5, 11.5, "kg")
+		record.AnimalID = animalID
+
+		mockRepo.On("FindByAnimalID", mock.Anything, animalID).Return(nil, errors.New("erro ao buscar"))
+
+		err := weightService.AddWeightRecord(context.Background(), &record, now)
+		assert.EqualError(t, err, "erro ao buscar")
+	})
+}
+
+func TestBuildWeightSeries(t *testing.T) {
+	series := service.BuildWeightSeries([]model.WeightRecord{
+		weightOn(1, 10, "kg"),
+		weightOn(15, 11000, "g"),
+		weightOn(30, 22, "lb"),
+	})
+
+	assert.Len(t, series, 3)
+	assert.Nil(t, series[0].PercentChange)
+	assert.Equal(t, 11.0, series[1].WeightKg)
+	assert.Equal(t, 10.0, *series[1].PercentChange)
+	assert.Equal(t, 9.979, series[2].WeightKg)
+	assert.Equal(t, -9.28, *series[2].PercentChange)
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tipos de evento da linha do tempo clínica
+const (
+	TimelineConsultation    = "consultation"
+	TimelineDosage          = "dosage"
+	TimelineHospitalization = "hospitalization"
+	TimelineWeight          = "weight"
+	TimelineImage           = "image"
+)
+
+var TimelineTypes = []string{TimelineConsultation, TimelineDosage, TimelineHospitalization, TimelineWeight, TimelineImage}
+
+const (
+	DefaultTimelinePageSize = 20
+	MaxTimelinePageSize     = 100
+)
+
+var (
+	ErrInvalidTimelineCursor = errors.New("cursor da linha do tempo inválido")
+	ErrUnknownTimelineType   = errors.New("tipo de evento da linha do tempo desconhecido")
+)
+
+// TimelineEntry é um evento clínico do animal. Type indica qual registro está
+// em Data: a consulta, a dosagem, a hospitalização, a pesagem (com a variação
+// em relação à anterior) ou os dados da foto.
+type TimelineEntry struct {
+	Type       string      `json:"type"`
+	ID         uuid.UUID   `json:"id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// TimelineImageData descreve a foto do animal sem o conteúdo, que é obtido pela rota da imagem
+type TimelineImageData struct {
+	AnimalID  uuid.UUID `json:"animal_id"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TimelineQuery filtra os tipos de evento e pagina a linha do tempo; Types vazio inclui todos
+type TimelineQuery struct {
+	Types  []string
+	Cursor string
+	Limit  int
+}
+
+// TimelinePage é uma página da linha do tempo; NextCursor fica vazio na última página
+type TimelinePage struct {
+	Entries    []TimelineEntry `json:"entries"`
+	NextCursor string          `json:"next_cursor"`
+}
+
+// timelineCursor guarda a posição do último evento retornado
+type timelineCursor struct {
+	OccurredAt time.Time `json:"t"`
+	Type       string    `json:"k"`
+	ID         uuid.UUID `json:"id"`
+}
+
+type TimelineService struct {
+	consultations    repository.ConsultationRepository
+	dosages          repository.DosageRepository
+	hospitalizations repository.HospitalizationRepositoryInterface
+	weights          repository.WeightRepository
+	images           repository.ImageRepositoryInterface
+}
+
+// Cria uma nova instância do TimelineService com os repositórios de cada tipo de evento
+func NewTimelineService(
+	consultations repository.ConsultationRepository,
+	dosages repository.DosageRepository,
+	hospitalizations repository.HospitalizationRepositoryInterface,
+	weights repository.WeightRepository,
+	images repository.ImageRepositoryInterface,
+) *TimelineService {
+	return &TimelineService{
+		consultations:    consultations,
+		dosages:          dosages,
+		hospitalizations: hospitalizations,
+		weights:          weights,
+		images:           images,
+	}
+}
+
+// GetTimeline junta consultas, dosagens, hospitalizações, pesagens e a foto do
+// animal em uma única lista, do evento mais recente para o mais antigo
+func (s *TimelineService) GetTimeline(ctx context.Context, animalID uuid.UUID, query TimelineQuery) (*TimelinePage, error) {
+	types, err := timelineTypeSet(query.Types)
+	if err != nil {
+		return nil, err
+	}
+
+	var after *timelineCursor
+	if query.Cursor != "" {
+		after, err = decodeTimelineCursor(query.Cursor)
+		if err != nil {
+			return nil, ErrInvalidTimelineCursor
+		}
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultTimelinePageSize
+	}
+	if limit > MaxTimelinePageSize {
+		limit = MaxTimelinePageSize
+	}
+
+	entries, err := s.collectTimeline(ctx, animalID, types)
+	if err != nil {
+		return nil, err
+	}
+	return paginateTimeline(entries, after, limit), nil
+}
+
+func (s *TimelineService) collectTimeline(ctx context.Context, animalID uuid.UUID, types map[string]bool) ([]TimelineEntry, error) {
+	var entries []TimelineEntry
+
+	if types[TimelineConsultation] {
+		consultations, err := s.consultations.FindConsultationByAnimalID(ctx, animalID)
+		if err != nil {
+			return nil, err
+		}
+		for _, consultation := range consultations {
+			entries = append(entries, TimelineEntry{
+				Type:       TimelineConsultation,
+				ID:         consultation.ID,
+				OccurredAt: consultationTime(consultation),
+				Data:       consultation,
+			})
+		}
+	}
+
+	if types[TimelineDosage] {
+		dosages, err := s.dosages.FindByAnimalID(ctx, animalID)
+		if err != nil {
+			return nil, err
+		}
+		for _, dosage := range dosages {
+			entries = append(entries, TimelineEntry{Type: TimelineDosage, ID: dosage.ID, OccurredAt: dosage.StartDate.Time, Data: dosage})
+		}
+	}
+
+	if types[TimelineHospitalization] {
+		hospitalizations, err := s.hospitalizations.FindHospitalizationsByPatientID(animalID)
+		if err != nil {
+			return nil, err
+		}
+		for _, hospitalization := range hospitalizations {
+			entries = append(entries, TimelineEntry{Type: TimelineHospitalization, ID: hospitalization.ID, OccurredAt: hospitalization.StartDate.Time, Data: hospitalization})
+		}
+	}
+
+	if types[TimelineWeight] {
+		records, err := s.weights.FindByAnimalID(ctx, animalID)
+		if err != nil {
+			return nil, err
+		}
+		for _, point := range BuildWeightSeries(records) {
+			entries = append(entries, TimelineEntry{Type: TimelineWeight, ID: point.ID, OccurredAt: point.Date.Time, Data: point})
+		}
+	}
+
+	if types[TimelineImage] {
+		image, err := s.images.FindImageMetadata(animalID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if image != nil {
+			entries = append(entries, TimelineEntry{
+				Type:       TimelineImage,
+				ID:         image.ID,
+				OccurredAt: image.UpdatedAt,
+				Data: TimelineImageData{
+					AnimalID:  animalID,
+					URL:       fmt.Sprintf("/api/v1/animals/%s/image", animalID),
+					CreatedAt: image.CreatedAt,
+					UpdatedAt: image.UpdatedAt,
+				},
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// paginateTimeline ordena os eventos do mais recente para o mais antigo e
+// retorna os que vêm depois do cursor. Eventos no mesmo instante são
+// desempatados por tipo e ID, para que a ordem seja estável entre páginas.
+func paginateTimeline(entries []TimelineEntry, after *timelineCursor, limit int) *TimelinePage {
+	sort.Slice(entries, func(i, j int) bool {
+		return timelineLess(entries[i].OccurredAt, entries[i].Type, entries[i].ID, entries[j].OccurredAt, entries[j].Type, entries[j].ID)
+	})
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(entries), func(i int) bool {
+			return timelineLess(after.OccurredAt, after.Type, after.ID, entries[i].OccurredAt, entries[i].Type, entries[i].ID)
+		})
+	}
+
+	page := &TimelinePage{Entries: []TimelineEntry{}}
+	end := start + limit
+	if end > len(entries) {
+		end = len(entries)
+	}
+	page.Entries = append(page.Entries, entries[start:end]...)
+	if end < len(entries) {
+		last := page.Entries[len(page.Entries)-1]
+		page.NextCursor = encodeTimelineCursor(timelineCursor{OccurredAt: last.OccurredAt, Type: last.Type, ID: last.ID})
+	}
+	return page
+}
+
+// timelineLess indica se o evento a vem antes do evento b na linha do tempo
+func timelineLess(aTime time.Time, aType string, aID uuid.UUID, bTime time.Time, bType string, bID uuid.UUID) bool {
+	if !aTime.Equal(bTime) {
+		return aTime.After(bTime)
+	}
+	if aType != bType {
+		return aType < bType
+	}
+	return aID.String() < bID.String()
+}
+
+func timelineTypeSet(types []string) (map[string]bool, error) {
+	if len(types) == 0 {
+		types = TimelineTypes
+	}
+	set := make(map[string]bool, len(types))
+	for _, t := range types {
+		known := false
+		for _, candidate := range TimelineTypes {
+			if t == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownTimelineType, t)
+		}
+		set[t] = true
+	}
+	return set, nil
+}
+
+// consultationTime combina a data e o horário da consulta; sem horário válido usa só a data
+func consultationTime(consultation model.Consultation) time.Time {
+	date := consultation.ConsultationDate.Time
+	hour, err := time.Parse("15:04", consultation.ConsultationHour)
+	if err != nil {
+		return date
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), hour.Hour(), hour.Minute(), 0, 0, date.Location())
+}
+
+func encodeTimelineCursor(cursor timelineCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeTimelineCursor(encoded string) (*timelineCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var cursor timelineCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
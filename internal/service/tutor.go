@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultTutorPageSize = 50
+	MaxTutorPageSize     = 200
+)
+
+var (
+	ErrTutorExists     = errors.New("tutor já cadastrado")
+	ErrUnknownTutor    = errors.New("tutor não cadastrado")
+	ErrTutorInactive   = errors.New("tutor desativado")
+	ErrTutorAnonymized = errors.New("tutor anonimizado; o cadastro não pode ser alterado")
+
+	ErrInvalidOwnerType       = errors.New("tipo de tutor inválido: use person ou organization")
+	ErrNotOrganization        = errors.New("o tutor não é uma organização")
+	ErrRepresentativeContact  = errors.New("informe o e-mail ou o telefone do representante")
+	ErrRepresentativeNotFound = errors.New("representante legal não encontrado")
+)
+
+var tutorValidator = newTutorValidator()
+
+func newTutorValidator() *validator.Validate {
+	v := validator.New()
+	_ = v.RegisterValidation("cpf", func(fl validator.FieldLevel) bool {
+		return model.IsValidCPF(fl.Field().String())
+	})
+	_ = v.RegisterValidation("document", func(fl validator.FieldLevel) bool {
+		return model.IsValidDocument(fl.Field().String())
+	})
+	_ = v.RegisterValidation("cep", func(fl validator.FieldLevel) bool {
+		return model.IsValidCEP(fl.Field().String())
+	})
+	_ = v.RegisterValidation("uf", func(fl validator.FieldLevel) bool {
+		return model.IsValidUF(fl.Field().String())
+	})
+	return v
+}
+
+// TutorPatch guarda os campos de uma alteração parcial; campos nulos não são alterados.
+// O CPF identifica o tutor e não pode ser alterado. O endereço é substituído por inteiro.
+type TutorPatch struct {
+	Name    *string        `json:"name"`
+	Email   *string        `json:"email"`
+	Phone   *string        `json:"phone"`
+	Address *model.Address `json:"address"`
+}
+
+type TutorService struct {
+	repo repository.TutorRepository
+	ceps *CEPDirectory
+}
+
+// TutorServiceOption configura uma dependência opcional do TutorService
+type TutorServiceOption func(*TutorService)
+
+// WithCEPDirectory faz o serviço completar e conferir os endereços pela tabela
+// local de CEPs. Sem ela, os endereços são validados apenas pelo formato.
+func WithCEPDirectory(ceps *CEPDirectory) TutorServiceOption {
+	return func(s *TutorService) {
+		s.ceps = ceps
+	}
+}
+
+// Cria uma nova instância do TutorService com o repositório de tutores
+func NewTutorService(repo repository.TutorRepository, opts ...TutorServiceOption) *TutorService {
+	s := &TutorService{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreateTutor valida e cadastra um tutor: uma pessoa, pelo CPF, ou uma organização,
+// pelo CNPJ. O documento é gravado sem pontuação e o endereço estruturado é obrigatório.
+func (s *TutorService) CreateTutor(ctx context.Context, cpf, name, email, phone string, address model.Address) (*model.Tutor, error) {
+	cpf = model.CleanDocument(cpf)
+	tutor, err := model.NewTutor(cpf, strings.TrimSpace(name), strings.TrimSpace(email), strings.TrimSpace(phone), address, "")
+	if err != nil {
+		return nil, ErrInvalidCPF
+	}
+	if tutor.Address, err = prepareAddress(s.ceps, tutor.Address); err != nil {
+		return nil, err
+	}
+	if err := tutorValidator.Struct(tutor); err != nil {
+		return nil, err
+	}
+
+	_, err = s.repo.FindTutorByCPF(ctx, cpf)
+	if err == nil {
+		return nil, ErrTutorExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("erro ao buscar tutor: %w", err)
+	}
+
+	if err := s.repo.CreateTutor(ctx, tutor); err != nil {
+		return nil, fmt.Errorf("erro ao salvar tutor: %w", err)
+	}
+	log.Printf("Tutor %s cadastrado", cpf)
+	return tutor, nil
+}
+
+// GetTutor busca o tutor pelo CPF, inclusive desativado; retorna gorm.ErrRecordNotFound se não existir
+func (s *TutorService) GetTutor(ctx context.Context, cpf string) (*model.Tutor, error) {
+	return s.repo.FindTutorByCPF(ctx, model.CleanDocument(cpf))
+}
+
+// SearchTutors busca tutores por nome, telefone ou e-mail, limitando o número de resultados
+func (s *TutorService) SearchTutors(ctx context.Context, query repository.TutorQuery) ([]model.Tutor, error) {
+	query.Name = strings.TrimSpace(query.Name)
+	query.Email = strings.TrimSpace(query.Email)
+	query.Phone = digitsOnly(query.Phone)
+	if query.OwnerType != "" && query.OwnerType != model.OwnerTypePerson && query.OwnerType != model.OwnerTypeOrganization {
+		return nil, ErrInvalidOwnerType
+	}
+	if query.Limit <= 0 {
+		query.Limit = DefaultTutorPageSize
+	}
+	if query.Limit > MaxTutorPageSize {
+		query.Limit = MaxTutorPageSize
+	}
+	return s.repo.ListTutors(ctx, query)
+}
+
+// UpdateTutor aplica os campos não nulos de patch ao tutor e valida o resultado
+func (s *TutorService) UpdateTutor(ctx context.Context, cpf string, patch TutorPatch) (*model.Tutor, error) {
+	tutor, err := s.repo.FindTutorByCPF(ctx, model.CleanDocument(cpf))
+	if err != nil {
+		return nil, err
+	}
+	if tutor.AnonymizedAt != nil {
+		return nil, ErrTutorAnonymized
+	}
+
+	if patch.Name != nil {
+		tutor.Name = strings.TrimSpace(*patch.Name)
+	}
+	if patch.Email != nil {
+		tutor.Email = strings.TrimSpace(*patch.Email)
+	}
+	if patch.Phone != nil {
+		tutor.Phone = strings.TrimSpace(*patch.Phone)
+	}
+	if patch.Address != nil {
+		address, err := prepareAddress(s.ceps, *patch.Address)
+		if err != nil {
+			return nil, err
+		}
+		// O endereço estruturado substitui o texto livre dos cadastros antigos
+		tutor.Address, tutor.AddressText = address, ""
+	}
+	if err := tutorValidator.Struct(tutor); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateTutor(ctx, tutor); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar tutor: %w", err)
+	}
+	return tutor, nil
+}
+
+// SetTutorActive desativa ou reativa o tutor. Tutores não são excluídos: os
+// animais e o histórico continuam apontando para o CPF.
+func (s *TutorService) SetTutorActive(ctx context.Context, cpf string, active bool) (*model.Tutor, error) {
+	tutor, err := s.repo.FindTutorByCPF(ctx, model.CleanDocument(cpf))
+	if err != nil {
+		return nil, err
+	}
+	if tutor.AnonymizedAt != nil {
+		return nil, ErrTutorAnonymized
+	}
+	if tutor.Active == active {
+		return tutor, nil
+	}
+
+	tutor.Active = active
+	if err := s.repo.UpdateTutor(ctx, tutor); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar tutor: %w", err)
+	}
+	log.Printf("Tutor %s ativo: %t", tutor.CPFTutor, active)
+	return tutor, nil
+}
+
+// CheckTutor confirma que o CPF pertence a um tutor cadastrado e ativo, que
+// pode receber animais
+func (s *TutorService) CheckTutor(ctx context.Context, cpf string) error {
+	tutor, err := s.repo.FindTutorByCPF(ctx, cpf)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUnknownTutor
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao buscar tutor: %w", err)
+	}
+	if !tutor.Active {
+		return ErrTutorInactive
+	}
+	return nil
+}
+
+// ResolveTutor identifica o tutor de um usuário autenticado: pelo UID do Firebase
+// ou, no primeiro acesso, pelo e-mail verificado do token, vinculando o UID ao
+// cadastro. Retorna gorm.ErrRecordNotFound quando o usuário não é tutor.
+func (s *TutorService) ResolveTutor(ctx context.Context, uid, verifiedEmail string) (*model.Tutor, error) {
+	tutor, err := s.repo.FindTutorByUID(ctx, uid)
+	if errors.Is(err, gorm.ErrRecordNotFound) && verifiedEmail != "" {
+		tutor, err = s.repo.FindUnboundTutorByEmail(ctx, verifiedEmail)
+		if err == nil {
+			tutor.FirebaseUID = &uid
+			if err := s.repo.UpdateTutor(ctx, tutor); err != nil {
+				return nil, fmt.Errorf("erro ao vincular usuário ao tutor: %w", err)
+			}
+			log.Printf("Usuário %s vinculado ao tutor %s", uid, tutor.CPFTutor)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !tutor.Active {
+		return nil, ErrTutorInactive
+	}
+	return tutor, nil
+}
+
+// ListLegalRepresentatives lista os representantes legais de um tutor organização
+func (s *TutorService) ListLegalRepresentatives(ctx context.Context, cnpj string) ([]model.LegalRepresentative, error) {
+	tutor, err := s.findOrganization(ctx, cnpj)
+	if err != nil {
+		return nil, err
+	}
+	representatives, err := s.repo.FindLegalRepresentatives(ctx, tutor.CPFTutor)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar representantes: %w", err)
+	}
+	if representatives == nil {
+		representatives = []model.LegalRepresentative{}
+	}
+	return representatives, nil
+}
+
+// AddLegalRepresentative cadastra um representante legal da organização, com ao
+// menos um contato (e-mail ou telefone)
+func (s *TutorService) AddLegalRepresentative(ctx context.Context, cnpj string, representative model.LegalRepresentative) (*model.LegalRepresentative, error) {
+	tutor, err := s.findOrganization(ctx, cnpj)
+	if err != nil {
+		return nil, err
+	}
+	if tutor.AnonymizedAt != nil {
+		return nil, ErrTutorAnonymized
+	}
+
+	representative.ID = uuid.New()
+	representative.CNPJ = tutor.CPFTutor
+	representative.Name = strings.TrimSpace(representative.Name)
+	representative.CPF = digitsOnly(representative.CPF)
+	representative.Position = strings.TrimSpace(representative.Position)
+	representative.Email = strings.TrimSpace(representative.Email)
+	representative.Phone = strings.TrimSpace(representative.Phone)
+	if err := tutorValidator.Struct(representative); err != nil {
+		return nil, err
+	}
+	if representative.Email == "" && representative.Phone == "" {
+		return nil, ErrRepresentativeContact
+	}
+
+	if err := s.repo.SaveLegalRepresentative(ctx, &representative); err != nil {
+		return nil, fmt.Errorf("erro ao salvar representante: %w", err)
+	}
+	log.Printf("Representante legal %s cadastrado para %s", representative.ID, tutor.CPFTutor)
+	return &representative, nil
+}
+
+// RemoveLegalRepresentative remove um representante legal da organização
+func (s *TutorService) RemoveLegalRepresentative(ctx context.Context, cnpj string, id uuid.UUID) error {
+	representatives, err := s.ListLegalRepresentatives(ctx, cnpj)
+	if err != nil {
+		return err
+	}
+	for i := range representatives {
+		if representatives[i].ID == id {
+			if err := s.repo.DeleteLegalRepresentative(ctx, &representatives[i]); err != nil {
+				return fmt.Errorf("erro ao remover representante: %w", err)
+			}
+			return nil
+		}
+	}
+	return ErrRepresentativeNotFound
+}
+
+func (s *TutorService) findOrganization(ctx context.Context, cnpj string) (*model.Tutor, error) {
+	tutor, err := s.repo.FindTutorByCPF(ctx, model.CleanDocument(cnpj))
+	if err != nil {
+		return nil, err
+	}
+	if tutor.OwnerType != model.OwnerTypeOrganization {
+		return nil, ErrNotOrganization
+	}
+	return tutor, nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+
+	"github.com/google/uuid"
+)
+
+// ErrAnimalNotOwned indica que o animal não existe ou não pertence ao tutor; os
+// dois casos são tratados igualmente para não revelar animais de outros tutores
+var ErrAnimalNotOwned = errors.New("animal não encontrado entre os animais do tutor")
+
+// TutorPortalService atende o portal do tutor. Todas as consultas partem dos
+// animais de que o tutor é tutor principal ou co-tutor.
+type TutorPortalService struct {
+	tutors        repository.TutorRepository
+	animals       repository.AnimalRepositoryInterface
+	consultations repository.ConsultationRepository
+	dosages       repository.DosageRepository
+	vaccinations  repository.VaccinationRepository
+}
+
+// Cria uma nova instância do TutorPortalService com os repositórios consultados pelo portal
+func NewTutorPortalService(
+	tutors repository.TutorRepository,
+	animals repository.AnimalRepositoryInterface,
+	consultations repository.ConsultationRepository,
+	dosages repository.DosageRepository,
+	vaccinations repository.VaccinationRepository,
+) *TutorPortalService {
+	return &TutorPortalService{
+		tutors:        tutors,
+		animals:       animals,
+		consultations: consultations,
+		dosages:       dosages,
+		vaccinations:  vaccinations,
+	}
+}
+
+// GetProfile devolve o cadastro do tutor
+func (s *TutorPortalService) GetProfile(ctx context.Context, cpf string) (*model.Tutor, error) {
+	return s.tutors.FindTutorByCPF(ctx, cpf)
+}
+
+// ListPets lista os animais do tutor, em ordem alfabética
+func (s *TutorPortalService) ListPets(ctx context.Context, cpf string) ([]model.Animal, error) {
+	animals, err := s.animals.FindAnimalsOwnedBy(cpf)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar animais do tutor: %w", err)
+	}
+	if animals == nil {
+		animals = []model.Animal{}
+	}
+	return animals, nil
+}
+
+// GetPet devolve um animal do tutor; retorna ErrAnimalNotOwned para animais de outros tutores
+func (s *TutorPortalService) GetPet(ctx context.Context, cpf string, animalID uuid.UUID) (*model.Animal, error) {
+	animals, err := s.ListPets(ctx, cpf)
+	if err != nil {
+		return nil, err
+	}
+	for i := range animals {
+		if animals[i].ID == animalID {
+			return &animals[i], nil
+		}
+	}
+	return nil, ErrAnimalNotOwned
+}
+
+// ownershipPeriods devolve os períodos de responsabilidade do animal e o CPF cujos
+// períodos limitam o histórico mostrado ao tutor
+func (s *TutorPortalService) ownershipPeriods(animal model.Animal, cpf string) ([]OwnershipPeriod, string, error) {
+	transfers, err := s.animals.FindOwnershipTransfers(animal.ID)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao buscar transferências do animal: %w", err)
+	}
+	return BuildOwnershipPeriods(animal.CPFTutor, transfers), TutorViewOf(animal, cpf), nil
+}
+
+// ListConsultations lista as consultas agendadas dos animais do tutor a partir de
+// hoje, da mais próxima à mais distante. Com includePast, inclui também as
+// consultas anteriores e as já realizadas ou canceladas. Consultas de antes de o
+// animal passar ao tutor não aparecem.
+func (s *TutorPortalService) ListConsultations(ctx context.Context, cpf string, includePast bool, now time.Time) ([]model.Consultation, error) {
+	animals, err := s.ListPets(ctx, cpf)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	consultations := []model.Consultation{}
+	for _, animal := range animals {
+		found, err := s.consultations.FindConsultationByAnimalID(ctx, animal.ID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar consultas: %w", err)
+		}
+		periods, viewer, err := s.ownershipPeriods(animal, cpf)
+		if err != nil {
+			return nil, err
+		}
+		for _, consultation := range ConsultationsVisibleToTutor(found, periods, viewer) {
+			if !includePast && (consultation.ConsultationStatus != "scheduled" || consultation.ConsultationDate.Before(today)) {
+				continue
+			}
+			consultations = append(consultations, consultation)
+		}
+	}
+
+	sort.SliceStable(consultations, func(i, j int) bool {
+		return consultationTime(consultations[i]).Before(consultationTime(consultations[j]))
+	})
+	return consultations, nil
+}
+
+// ListPrescriptions lista as dosagens prescritas aos animais do tutor, da mais
+// recente à mais antiga. Com activeOnly, apenas as que ainda não terminaram. Assim
+// como nas consultas, as prescrições de antes da transferência não aparecem.
+func (s *TutorPortalService) ListPrescriptions(ctx context.Context, cpf string, activeOnly bool, now time.Time) ([]model.Dosage, error) {
+	animals, err := s.ListPets(ctx, cpf)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	prescriptions := []model.Dosage{}
+	for _, animal := range animals {
+		dosages, err := s.dosages.FindByAnimalID(ctx, animal.ID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar prescrições: %w", err)
+		}
+		periods, viewer, err := s.ownershipPeriods(animal, cpf)
+		if err != nil {
+			return nil, err
+		}
+		for _, dosage := range DosagesVisibleToTutor(dosages, periods, viewer) {
+			if activeOnly && dosage.EndDate.Before(today) {
+				continue
+			}
+			prescriptions = append(prescriptions, dosage)
+		}
+	}
+
+	sort.SliceStable(prescriptions, func(i, j int) bool {
+		return prescriptions[i].StartDate.After(prescriptions[j].StartDate.Time)
+	})
+	return prescriptions, nil
+}
+
+// GetVaccinationCard devolve a carteira de vacinação de um animal do tutor
+func (s *TutorPortalService) GetVaccinationCard(ctx context.Context, cpf string, animalID uuid.UUID) ([]model.VaccinationRecord, error) {
+	if _, err := s.GetPet(ctx, cpf, animalID); err != nil {
+		return nil, err
+	}
+	records, err := s.vaccinations.FindRecordsByAnimalID(ctx, animalID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar vacinas: %w", err)
+	}
+	if records == nil {
+		records = []model.VaccinationRecord{}
+	}
+	return records, nil
+}
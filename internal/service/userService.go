@@ -6,17 +6,24 @@ import (
     "vetblock/internal/db/model"
 )
 
-// Cria um usuário genérico (Tutor ou Veterinarian)
+// Cria um usuário genérico (Tutor ou Veterinary)
 func CreateUser(user interface{}) error {
     switch u := user.(type) {
     case *model.Tutor:
-        if err := db.NewDb().Save(u).Error; err != nil {
+        if err := db.GetDB().Save(u).Error; err != nil {
             return err
         }
-    case *model.Veterinarian:
-        if err := db.NewDb().Save(u).Error; err != nil {
+    case *model.Veterinary:
+        if err := db.GetDB().Save(u).Error; err != nil {
             return err
         }
+        // O CRMV do cadastro é a inscrição principal do veterinário
+        if model.IsValidCRMV(u.CRVM) {
+            primary := model.NewPrimaryLicense(u.CRVM)
+            if err := db.GetDB().Where("crvm = ? AND uf = ?", primary.CRVM, primary.UF).FirstOrCreate(&primary).Error; err != nil {
+                return err
+            }
+        }
     default:
         return errors.New("invalid user type")
     }
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProtocolSpeciesMismatch = errors.New("o protocolo vacinal não é da espécie do animal")
+	ErrBatchNotOfProduct       = errors.New("o lote informado não pertence ao produto vacinal")
+	ErrBatchExpired            = errors.New("o lote da vacina está vencido")
+	ErrBatchOutOfStock         = repository.ErrBatchOutOfStock // Também retornado quando o estoque acaba durante a gravação
+	ErrFutureVaccination       = errors.New("a data de aplicação não pode estar no futuro")
+)
+
+// Situação de uma vacina na listagem de vencimentos
+const (
+	VaccinationOverdue  = "overdue"
+	VaccinationUpcoming = "upcoming"
+)
+
+// DefaultVaccinationDueDays é quantos dias à frente a listagem de vencimentos olha por padrão
+const DefaultVaccinationDueDays = 30
+
+// VaccinationDueItem é uma vacina atrasada ou a vencer, com os dias até o vencimento
+// (negativos quando atrasada)
+type VaccinationDueItem struct {
+	repository.VaccinationDue
+	Status       string `json:"status"`
+	DaysUntilDue int    `json:"days_until_due"`
+}
+
+type VaccinationService struct {
+	repo    repository.VaccinationRepository
+	animals repository.AnimalRepositoryInterface
+}
+
+// Cria uma nova instância do VaccinationService com os repositórios de vacinação e de animais
+func NewVaccinationService(repo repository.VaccinationRepository, animals repository.AnimalRepositoryInterface) *VaccinationService {
+	return &VaccinationService{repo: repo, animals: animals}
+}
+
+var vaccinationValidator = validator.New()
+
+// ListProtocols lista os protocolos vacinais, opcionalmente filtrando pela espécie
+func (s *VaccinationService) ListProtocols(ctx context.Context, species string) ([]model.VaccinationProtocol, error) {
+	return s.repo.ListProtocols(ctx, species)
+}
+
+// CreateProtocol cadastra um protocolo. Sem código informado, ele é gerado a partir
+// da espécie e do nome.
+func (s *VaccinationService) CreateProtocol(ctx context.Context, protocol *model.VaccinationProtocol) error {
+	protocol.ID = uuid.New()
+	if protocol.Code == "" {
+		protocol.Code = model.CatalogCode(protocol.Species + " " + protocol.Name)
+	}
+	if err := vaccinationValidator.Struct(protocol); err != nil {
+		return fmt.Errorf("protocolo inválido: %w", err)
+	}
+	return s.repo.SaveProtocol(ctx, protocol)
+}
+
+func (s *VaccinationService) ListProducts(ctx context.Context) ([]model.VaccineProduct, error) {
+	return s.repo.ListProducts(ctx)
+}
+
+// CreateProduct cadastra um produto vacinal ligado a um protocolo existente
+func (s *VaccinationService) CreateProduct(ctx context.Context, product *model.VaccineProduct) error {
+	product.ID = uuid.New()
+	if err := vaccinationValidator.Struct(product); err != nil {
+		return fmt.Errorf("produto vacinal inválido: %w", err)
+	}
+	if _, err := s.repo.FindProtocolByID(ctx, product.ProtocolID); err != nil {
+		return fmt.Errorf("erro ao buscar protocolo: %w", err)
+	}
+	return s.repo.SaveProduct(ctx, product)
+}
+
+// RecordVaccination registra a aplicação de uma vacina no animal e calcula o vencimento
+// da próxima dose pelo protocolo do produto. Sem número de dose informado, a dose
+// segue a última registrada para o mesmo protocolo; doses além da série inicial são
+// reforços. Quando um lote do estoque é informado, ele precisa ser do produto, estar
+// dentro da validade e ter estoque, e o número do lote é copiado dele.
+func (s *VaccinationService) RecordVaccination(ctx context.Context, record *model.VaccinationRecord, now time.Time) error {
+	animal, err := s.animals.FindAnimalByID(record.AnimalID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar animal: %w", err)
+	}
+	if animal.IsDeceased() {
+		return ErrAnimalDeceased
+	}
+	product, err := s.repo.FindProductByID(ctx, record.ProductID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar produto vacinal: %w", err)
+	}
+	protocol, err := s.repo.FindProtocolByID(ctx, product.ProtocolID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar protocolo: %w", err)
+	}
+	if model.NormalizeName(protocol.Species) != model.NormalizeName(animal.Species) {
+		return ErrProtocolSpeciesMismatch
+	}
+
+	if record.AppliedAt.IsZero() {
+		record.AppliedAt = model.CustomDate{Time: now.Truncate(24 * time.Hour)}
+	}
+	if record.AppliedAt.After(now) {
+		return ErrFutureVaccination
+	}
+
+	if record.MedicationID != nil {
+		batch, err := s.repo.FindBatch(ctx, *record.MedicationID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar lote: %w", err)
+		}
+		if batch.VaccineProductID == nil || *batch.VaccineProductID != product.ID {
+			return ErrBatchNotOfProduct
+		}
+		if batch.Expiration.Before(record.AppliedAt.Time) {
+			return ErrBatchExpired
+		}
+		if batch.Quantity < 1 {
+			return ErrBatchOutOfStock
+		}
+		record.LotNumber = batch.BatchNumber
+	}
+
+	if record.DoseNumber == 0 {
+		previous, err := s.repo.FindRecordsByAnimalID(ctx, animal.ID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar vacinas do animal: %w", err)
+		}
+		record.DoseNumber = 1
+		for _, r := range previous {
+			if r.ProtocolID == protocol.ID && r.DoseNumber >= record.DoseNumber {
+				record.DoseNumber = r.DoseNumber + 1
+			}
+		}
+	}
+
+	record.ID = uuid.New()
+	record.ProtocolID = protocol.ID
+	record.NextDueDate = protocol.NextDueDate(record.DoseNumber, record.AppliedAt.Time)
+	if err := vaccinationValidator.Struct(record); err != nil {
+		return fmt.Errorf("vacinação inválida: %w", err)
+	}
+	return s.repo.CreateRecord(ctx, record)
+}
+
+// GetVaccinationCard devolve as vacinas aplicadas no animal, da mais antiga à mais recente
+func (s *VaccinationService) GetVaccinationCard(ctx context.Context, animalID uuid.UUID) ([]model.VaccinationRecord, error) {
+	if _, err := s.animals.FindAnimalByID(animalID); err != nil {
+		return nil, fmt.Errorf("erro ao buscar animal: %w", err)
+	}
+	return s.repo.FindRecordsByAnimalID(ctx, animalID)
+}
+
+// ListDue lista as vacinas atrasadas e as que vencem nos próximos "days" dias, de um
+// tutor ou da clínica toda (cpfTutor vazio)
+func (s *VaccinationService) ListDue(ctx context.Context, cpfTutor string, days int, now time.Time) ([]VaccinationDueItem, error) {
+	if days < 0 {
+		return nil, errors.New("days não pode ser negativo")
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	due, err := s.repo.FindDue(ctx, today.AddDate(0, 0, days), cpfTutor)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar vacinas a vencer: %w", err)
+	}
+	return BuildVaccinationDueItems(due, today), nil
+}
+
+// BuildVaccinationDueItems classifica cada vencimento como atrasado ou a vencer em relação a "today"
+func BuildVaccinationDueItems(due []repository.VaccinationDue, today time.Time) []VaccinationDueItem {
+	items := make([]VaccinationDueItem, 0, len(due))
+	for _, d := range due {
+		dueDate := time.Date(d.NextDueDate.Year(), d.NextDueDate.Month(), d.NextDueDate.Day(), 0, 0, 0, 0, time.UTC)
+		item := VaccinationDueItem{VaccinationDue: d, DaysUntilDue: int(dueDate.Sub(today).Hours() / 24), Status: VaccinationUpcoming}
+		if item.DaysUntilDue < 0 {
+			item.Status = VaccinationOverdue
+		}
+		items = append(items, item)
+	}
+	return items
+}
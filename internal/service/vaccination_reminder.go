@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"vetblock/internal/db/model"
+)
+
+// DefaultReminderDays é a antecedência do lembrete de vacina; um segundo lembrete
+// é enviado no dia do vencimento
+const DefaultReminderDays = 7
+
+// VaccinationReminderReport resume uma rodada de lembretes de vacina
+type VaccinationReminderReport struct {
+	Sent      int `json:"sent"`
+	NoConsent int `json:"no_consent"` // Tutores sem consentimento para lembretes por e-mail
+	Failed    int `json:"failed"`
+}
+
+// VaccinationReminderService avisa os tutores, por e-mail e pelo NotificationService,
+// das vacinas que estão para vencer
+type VaccinationReminderService struct {
+	vaccinations  *VaccinationService
+	notifications *NotificationService
+}
+
+// Cria uma nova instância do VaccinationReminderService
+func NewVaccinationReminderService(vaccinations *VaccinationService, notifications *NotificationService) *VaccinationReminderService {
+	return &VaccinationReminderService{vaccinations: vaccinations, notifications: notifications}
+}
+
+// SendReminders envia um lembrete para cada vacina que vence daqui a exatamente
+// "days" dias ou hoje, de modo que uma rodada diária avisa cada vencimento no
+// máximo duas vezes. Vacinas já atrasadas não geram lembrete.
+func (s *VaccinationReminderService) SendReminders(ctx context.Context, days int, now time.Time) (*VaccinationReminderReport, error) {
+	due, err := s.vaccinations.ListDue(ctx, "", days, now)
+	if err != nil {
+		return nil, err
+	}
+	report := &VaccinationReminderReport{}
+	for _, item := range due {
+		if item.DaysUntilDue != days && item.DaysUntilDue != 0 {
+			continue
+		}
+		err := s.notifications.Notify(ctx, vaccinationReminder(item))
+		switch {
+		case err == nil:
+			report.Sent++
+		case errors.Is(err, ErrNoConsent):
+			report.NoConsent++
+		default:
+			report.Failed++
+			log.Printf("Falha no lembrete da vacina %s do animal %s: %v", item.ProtocolName, item.AnimalID, err)
+		}
+	}
+	return report, nil
+}
+
+// RunVaccinationReminders envia os lembretes uma vez por intervalo até ctx terminar
+func (s *VaccinationReminderService) RunVaccinationReminders(ctx context.Context, interval time.Duration, days int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := s.SendReminders(ctx, days, time.Now())
+		if err != nil {
+			log.Print(err)
+		} else if report.Sent+report.Failed > 0 {
+			log.Printf("Lembretes de vacina: %d enviados, %d sem consentimento, %d com falha", report.Sent, report.NoConsent, report.Failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func vaccinationReminder(item VaccinationDueItem) Notification {
+	when := "vence hoje"
+	if item.DaysUntilDue > 0 {
+		when = fmt.Sprintf("vence em %s", item.NextDueDate.Format("02/01/2006"))
+	}
+	return Notification{
+		CPFTutor: item.CPFTutor,
+		Channel:  model.ConsentChannelEmail,
+		Purpose:  model.ConsentPurposeClinicalReminders,
+		Subject:  fmt.Sprintf("Lembrete de vacina: %s", item.AnimalName),
+		Message:  fmt.Sprintf("A próxima dose da vacina %s de %s %s. Entre em contato com a clínica para agendar a aplicação.", item.ProtocolName, item.AnimalName, when),
+	}
+}
@@ -1,28 +1,216 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
-	"time"
+	"strings"
 	"vetblock/internal/db/model"
 	"vetblock/internal/db/repository"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCRMV      = errors.New("CRMV inválido: use o número e a UF do conselho, como 123456-SP")
+	ErrVeterinaryExists = errors.New("veterinário já cadastrado")
 )
 
-func getVeterinaryRepo() *repository.VeterinaryRepository {
-	return repository.NewVeterinaryRepository()
+var veterinaryValidator = newVeterinaryValidator()
+
+func newVeterinaryValidator() *validator.Validate {
+	v := validator.New()
+	_ = v.RegisterValidation("crmv", func(fl validator.FieldLevel) bool {
+		return model.IsValidCRMV(fl.Field().String())
+	})
+	_ = v.RegisterValidation("uf", func(fl validator.FieldLevel) bool {
+		return model.IsValidUF(fl.Field().String())
+	})
+	return v
+}
+
+// VeterinaryPatch guarda os campos de uma alteração parcial; campos nulos não são
+// alterados. O CRMV identifica o veterinário e não pode ser alterado.
+type VeterinaryPatch struct {
+	Name      *string `json:"name"`
+	LastName  *string `json:"last_name"`
+	Email     *string `json:"email"`
+	Phone     *string `json:"phone"`
+	Specialty *string `json:"specialty"`
+}
+
+type VeterinaryService struct {
+	repo     repository.VeterinaryRepository
+	clinicUF string
+}
+
+// VeterinaryServiceOption configura uma dependência opcional do VeterinaryService
+type VeterinaryServiceOption func(*VeterinaryService)
+
+// WithClinicUF informa a UF da clínica: consultas e prescrições exigem inscrição
+// ativa do veterinário nessa UF. Sem ela, basta uma inscrição ativa em qualquer UF.
+func WithClinicUF(uf string) VeterinaryServiceOption {
+	return func(s *VeterinaryService) {
+		s.clinicUF = uf
+	}
 }
 
-func GetVeterinaryByCRVM(crvm string) (*model.Veterinary, error) {
-	repo := getVeterinaryRepo()
-	return repo.FindVeterinaryByCRVM(crvm)
+// Cria uma nova instância do VeterinaryService com o repositório de veterinários
+func NewVeterinaryService(repo repository.VeterinaryRepository, opts ...VeterinaryServiceOption) *VeterinaryService {
+	s := &VeterinaryService{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-func AddVeterinary(veterinary model.Veterinary) error {
+// CreateVeterinary valida e cadastra um veterinário. O CRMV é gravado no formato
+// número-UF; um CRMV removido anteriormente deve ser restaurado, não recadastrado.
+func (s *VeterinaryService) CreateVeterinary(ctx context.Context, veterinary *model.Veterinary) (*model.Veterinary, error) {
+	veterinary.CRVM = model.CleanCRMV(veterinary.CRVM)
+	if !model.IsValidCRMV(veterinary.CRVM) {
+		return nil, ErrInvalidCRMV
+	}
+	veterinary.Name = strings.TrimSpace(veterinary.Name)
+	veterinary.LastName = strings.TrimSpace(veterinary.LastName)
+	veterinary.Email = strings.TrimSpace(veterinary.Email)
+	veterinary.Phone = strings.TrimSpace(veterinary.Phone)
+	veterinary.Specialty = strings.TrimSpace(veterinary.Specialty)
+	veterinary.UserType = model.VeterinarianType
+	if err := veterinaryValidator.Struct(veterinary); err != nil {
+		return nil, err
+	}
+
+	_, err := s.repo.FindVeterinaryIncludingDeleted(ctx, veterinary.CRVM)
+	if err == nil {
+		return nil, ErrVeterinaryExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("erro ao buscar veterinário: %w", err)
+	}
+
+	if err := s.repo.CreateVeterinary(ctx, veterinary); err != nil {
+		return nil, fmt.Errorf("erro ao salvar veterinário: %w", err)
+	}
+	// O CRMV do cadastro é a inscrição principal, com validade a informar
+	primary := model.NewPrimaryLicense(veterinary.CRVM)
+	if err := s.repo.SaveLicense(ctx, &primary); err != nil {
+		return nil, fmt.Errorf("erro ao salvar inscrição do veterinário: %w", err)
+	}
+	log.Printf("Veterinário %s cadastrado", veterinary.CRVM)
+	return veterinary, nil
+}
 
-	veterinary.CreatedAt = time.Now()
-	veterinary.UpdatedAt = time.Now()
-	
+// GetVeterinary busca o veterinário pelo CRMV; retorna gorm.ErrRecordNotFound se não existir
+func (s *VeterinaryService) GetVeterinary(ctx context.Context, crvm string) (*model.Veterinary, error) {
+	return s.repo.FindVeterinaryByCRVM(ctx, model.CleanCRMV(crvm))
+}
+
+// ListVeterinaries lista os veterinários ativos, filtrando por nome e especialidade
+func (s *VeterinaryService) ListVeterinaries(ctx context.Context, query repository.VeterinaryQuery) ([]model.Veterinary, error) {
+	query.Name = strings.TrimSpace(query.Name)
+	query.Specialty = strings.TrimSpace(query.Specialty)
+	veterinaries, err := s.repo.ListVeterinaries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if veterinaries == nil {
+		veterinaries = []model.Veterinary{}
+	}
+	return veterinaries, nil
+}
+
+// UpdateVeterinary aplica os campos não nulos de patch ao veterinário e valida o resultado
+func (s *VeterinaryService) UpdateVeterinary(ctx context.Context, crvm string, patch VeterinaryPatch) (*model.Veterinary, error) {
+	veterinary, err := s.repo.FindVeterinaryByCRVM(ctx, model.CleanCRMV(crvm))
+	if err != nil {
+		return nil, err
+	}
+
+	set := func(field *string, value *string) {
+		if value != nil {
+			*field = strings.TrimSpace(*value)
+		}
+	}
+	set(&veterinary.Name, patch.Name)
+	set(&veterinary.LastName, patch.LastName)
+	set(&veterinary.Email, patch.Email)
+	set(&veterinary.Phone, patch.Phone)
+	set(&veterinary.Specialty, patch.Specialty)
+	if err := veterinaryValidator.Struct(veterinary); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateVeterinary(ctx, veterinary); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar veterinário: %w", err)
+	}
+	return veterinary, nil
+}
+
+// DeleteVeterinary remove o veterinário da listagem e impede novos atendimentos.
+// As consultas e os óbitos já registrados continuam apontando para o CRMV.
+func (s *VeterinaryService) DeleteVeterinary(ctx context.Context, crvm string) error {
+	veterinary, err := s.repo.FindVeterinaryByCRVM(ctx, model.CleanCRMV(crvm))
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteVeterinary(ctx, veterinary); err != nil {
+		return fmt.Errorf("erro ao remover veterinário: %w", err)
+	}
+	log.Printf("Veterinário %s removido", veterinary.CRVM)
+	return nil
+}
+
+// RestoreVeterinary desfaz a remoção do veterinário
+func (s *VeterinaryService) RestoreVeterinary(ctx context.Context, crvm string) (*model.Veterinary, error) {
+	veterinary, err := s.repo.FindVeterinaryIncludingDeleted(ctx, model.CleanCRMV(crvm))
+	if err != nil {
+		return nil, err
+	}
+	if !veterinary.DeletedAt.Valid {
+		return veterinary, nil
+	}
+	if err := s.repo.RestoreVeterinary(ctx, veterinary); err != nil {
+		return nil, fmt.Errorf("erro ao restaurar veterinário: %w", err)
+	}
+	log.Printf("Veterinário %s restaurado", veterinary.CRVM)
+	return veterinary, nil
+}
+
+// ResolveVeterinary identifica o veterinário de um usuário autenticado: pelo UID do
+// Firebase ou, no primeiro acesso, pelo e-mail verificado do token, vinculando o
+// UID ao cadastro. Retorna gorm.ErrRecordNotFound quando o usuário não é um
+// veterinário cadastrado e não removido.
+func (s *VeterinaryService) ResolveVeterinary(ctx context.Context, uid, verifiedEmail string) (*model.Veterinary, error) {
+	veterinary, err := s.repo.FindVeterinaryByUID(ctx, uid)
+	if errors.Is(err, gorm.ErrRecordNotFound) && verifiedEmail != "" {
+		veterinary, err = s.repo.FindUnboundVeterinaryByEmail(ctx, verifiedEmail)
+		if err == nil {
+			veterinary.FirebaseUID = &uid
+			if err := s.repo.UpdateVeterinary(ctx, veterinary); err != nil {
+				return nil, fmt.Errorf("erro ao vincular usuário ao veterinário: %w", err)
+			}
+			log.Printf("Usuário %s vinculado ao veterinário %s", uid, veterinary.CRVM)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return veterinary, nil
+}
 
-	log.Println("adding veterinary transaction")
-	repo := getVeterinaryRepo()
-	return repo.SaveVeterinary(veterinary)
+// FindVeterinary busca o veterinário responsável por uma consulta ou um óbito.
+// Veterinários removidos não são aceitos; retorna ErrUnknownVeterinarian se o
+// CRMV não estiver cadastrado.
+func (s *VeterinaryService) FindVeterinary(crvm string) (*model.Veterinary, error) {
+	veterinary, err := s.repo.FindVeterinaryByCRVM(context.Background(), model.CleanCRMV(crvm))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUnknownVeterinarian
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar veterinário: %w", err)
+	}
+	return veterinary, nil
 }
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+)
+
+const (
+	DefaultStatsDays = 30  // Período padrão das estatísticas, terminando hoje
+	MaxStatsDays     = 366 // Maior período aceito, para limitar a série diária
+	TopReasonsLimit  = 10
+)
+
+var ErrInvalidStatsRange = errors.New("período inválido: a data inicial deve ser anterior à final e o período deve ter no máximo 366 dias")
+
+// StatsBucket resume as consultas de um dia, de uma semana (iniciada na
+// segunda-feira) ou de um mês
+type StatsBucket struct {
+	Period   string         `json:"period"` // 2006-01-02 para dias e semanas, 2006-01 para meses
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"by_status"`
+	ByType   map[string]int `json:"by_type"`
+	Revenue  float64        `json:"revenue"`
+}
+
+// ReasonCount é um motivo de consulta e quantas vezes ele apareceu no período
+type ReasonCount struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// VeterinaryStats reúne os indicadores do painel do veterinário no período
+type VeterinaryStats struct {
+	CRVM             string         `json:"crvm"`
+	From             string         `json:"from"`
+	To               string         `json:"to"`
+	Total            int            `json:"total"`
+	ByStatus         map[string]int `json:"by_status"`
+	ByType           map[string]int `json:"by_type"`
+	Revenue          float64        `json:"revenue"`          // Soma dos preços das consultas concluídas
+	ExpectedRevenue  float64        `json:"expected_revenue"` // Soma dos preços das consultas ainda agendadas
+	DistinctPatients int            `json:"distinct_patients"`
+	NoShowRate       float64        `json:"no_show_rate"` // Faltas sobre consultas concluídas ou com falta, de 0 a 1
+	TopReasons       []ReasonCount  `json:"top_reasons"`
+	PerDay           []StatsBucket  `json:"per_day"`
+	PerWeek          []StatsBucket  `json:"per_week"`
+	PerMonth         []StatsBucket  `json:"per_month"`
+}
+
+type VeterinaryStatsService struct {
+	consultations repository.ConsultationRepository
+	veterinaries  repository.VeterinaryRepository
+}
+
+// Cria uma nova instância do VeterinaryStatsService com os repositórios de
+// consultas e de veterinários
+func NewVeterinaryStatsService(consultations repository.ConsultationRepository, veterinaries repository.VeterinaryRepository) *VeterinaryStatsService {
+	return &VeterinaryStatsService{consultations: consultations, veterinaries: veterinaries}
+}
+
+// GetVeterinaryStats calcula os indicadores das consultas do veterinário entre from
+// e to, inclusive. As séries diária, semanal e mensal cobrem todo o período, com
+// zero nos intervalos sem consultas.
+func (s *VeterinaryStatsService) GetVeterinaryStats(ctx context.Context, crvm string, from, to time.Time) (*VeterinaryStats, error) {
+	from = truncateDay(from)
+	to = truncateDay(to)
+	if to.Before(from) || to.Sub(from).Hours()/24 >= MaxStatsDays {
+		return nil, ErrInvalidStatsRange
+	}
+	veterinary, err := s.veterinaries.FindVeterinaryByCRVM(ctx, model.CleanCRMV(crvm))
+	if err != nil {
+		return nil, err
+	}
+	consultations, err := s.consultations.FindConsultationByVeterinaryCRVMAndDateRange(ctx, veterinary.CRVM, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	return BuildVeterinaryStats(veterinary.CRVM, from, to, consultations), nil
+}
+
+// BuildVeterinaryStats agrega as consultas do período. Os motivos são agrupados sem
+// diferenciar maiúsculas e espaços, mantendo a primeira grafia encontrada.
+func BuildVeterinaryStats(crvm string, from, to time.Time, consultations []model.Consultation) *VeterinaryStats {
+	from = truncateDay(from)
+	to = truncateDay(to)
+	stats := &VeterinaryStats{
+		CRVM:       crvm,
+		From:       from.Format("2006-01-02"),
+		To:         to.Format("2006-01-02"),
+		ByStatus:   map[string]int{},
+		ByType:     map[string]int{},
+		TopReasons: []ReasonCount{},
+	}
+
+	days, dayIndex := newStatsBuckets(from, to, func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }, "2006-01-02")
+	weeks, weekIndex := newStatsBuckets(weekStart(from), to, func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }, "2006-01-02")
+	months, monthIndex := newStatsBuckets(time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC), to, func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }, "2006-01")
+
+	patients := map[string]bool{}
+	reasons := map[string]*ReasonCount{}
+	var reasonOrder []string
+	for _, c := range consultations {
+		date := truncateDay(c.ConsultationDate.Time)
+		if date.Before(from) || date.After(to) {
+			continue
+		}
+		stats.add(c)
+		for _, bucket := range []*StatsBucket{
+			&days[dayIndex[date.Format("2006-01-02")]],
+			&weeks[weekIndex[weekStart(date).Format("2006-01-02")]],
+			&months[monthIndex[date.Format("2006-01")]],
+		} {
+			bucket.add(c)
+		}
+
+		if c.ConsultationStatus != model.ConsultationCanceled {
+			patients[c.AnimalID.String()] = true
+		}
+		key := strings.ToLower(strings.Join(strings.Fields(c.Reason), " "))
+		if key == "" {
+			continue
+		}
+		if reasons[key] == nil {
+			reasons[key] = &ReasonCount{Reason: strings.TrimSpace(c.Reason)}
+			reasonOrder = append(reasonOrder, key)
+		}
+		reasons[key].Count++
+	}
+
+	stats.DistinctPatients = len(patients)
+	if attended := stats.ByStatus[model.ConsultationCompleted] + stats.ByStatus[model.ConsultationNoShow]; attended > 0 {
+		stats.NoShowRate = math.Round(float64(stats.ByStatus[model.ConsultationNoShow])/float64(attended)*10000) / 10000
+	}
+	for _, key := range reasonOrder {
+		stats.TopReasons = append(stats.TopReasons, *reasons[key])
+	}
+	sort.SliceStable(stats.TopReasons, func(i, j int) bool { return stats.TopReasons[i].Count > stats.TopReasons[j].Count })
+	if len(stats.TopReasons) > TopReasonsLimit {
+		stats.TopReasons = stats.TopReasons[:TopReasonsLimit]
+	}
+	stats.PerDay, stats.PerWeek, stats.PerMonth = days, weeks, months
+	return stats
+}
+
+func (s *VeterinaryStats) add(c model.Consultation) {
+	s.Total++
+	s.ByStatus[c.ConsultationStatus]++
+	s.ByType[c.ConsultationType]++
+	switch c.ConsultationStatus {
+	case model.ConsultationCompleted:
+		s.Revenue += c.ConsultationPrice
+	case model.ConsultationScheduled:
+		s.ExpectedRevenue += c.ConsultationPrice
+	}
+}
+
+func (b *StatsBucket) add(c model.Consultation) {
+	b.Total++
+	b.ByStatus[c.ConsultationStatus]++
+	b.ByType[c.ConsultationType]++
+	if c.ConsultationStatus == model.ConsultationCompleted {
+		b.Revenue += c.ConsultationPrice
+	}
+}
+
+// newStatsBuckets cria os intervalos vazios de start até to e o índice de cada um pelo período
+func newStatsBuckets(start, to time.Time, next func(time.Time) time.Time, layout string) ([]StatsBucket, map[string]int) {
+	var buckets []StatsBucket
+	index := map[string]int{}
+	for t := start; !t.After(to); t = next(t) {
+		period := t.Format(layout)
+		index[period] = len(buckets)
+		buckets = append(buckets, StatsBucket{Period: period, ByStatus: map[string]int{}, ByType: map[string]int{}})
+	}
+	return buckets, index
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// weekStart retorna a segunda-feira da semana da data
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return truncateDay(t).AddDate(0, 0, -offset)
+}
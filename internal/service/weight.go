@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+	"vetblock/internal/db/model"
+	"vetblock/internal/db/repository"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type WeightService struct {
+	repo repository.WeightRepository
+}
+
+// Cria uma nova instância do WeightService com um repositório de pesagens
+func NewWeightService(repo repository.WeightRepository) *WeightService {
+	return &WeightService{repo: repo}
+}
+
+// WeightPoint é uma pesagem da série, já convertida para kg, com a variação
+// percentual em relação à pesagem anterior (nula na primeira)
+type WeightPoint struct {
+	model.WeightRecord
+	WeightKg      float64  `json:"weight_kg"`
+	PercentChange *float64 `json:"percent_change"`
+}
+
+var weightValidator = validator.New()
+
+var ErrFutureWeightRecord = errors.New("a data da pesagem não pode estar no futuro")
+
+// AddWeightRecord registra uma pesagem. O peso atual do animal só é atualizado
+// quando a pesagem é a mais recente da série, para que lançamentos retroativos
+// não sobrescrevam um valor mais novo. Pesagens com data futura são recusadas.
+func (s *WeightService) AddWeightRecord(ctx context.Context, record *model.WeightRecord, now time.Time) error {
+	if record == nil {
+		return errors.New("pesagem não pode ser nula")
+	}
+	if record.ID == uuid.Nil {
+		record.ID = uuid.New()
+	}
+	if record.Unit == "" {
+		record.Unit = model.WeightUnitKg
+	}
+	if err := weightValidator.Struct(record); err != nil {
+		return fmt.Errorf("pesagem inválida: %w", err)
+	}
+	if record.Date.After(now) {
+		return ErrFutureWeightRecord
+	}
+
+	existing, err := s.repo.FindByAnimalID(ctx, record.AnimalID)
+	if err != nil {
+		return err
+	}
+	isLatest := true
+	for _, r := range existing {
+		if r.Date.After(record.Date.Time) {
+			isLatest = false
+			break
+		}
+	}
+
+	log.Printf("Adding weight record for animal %s (latest: %t)", record.AnimalID, isLatest)
+	return s.repo.Create(ctx, record, isLatest)
+}
+
+// GetWeightSeries retorna a curva de peso do animal em ordem cronológica
+func (s *WeightService) GetWeightSeries(ctx context.Context, animalID uuid.UUID) ([]WeightPoint, error) {
+	records, err := s.repo.FindByAnimalID(ctx, animalID)
+	if err != nil {
+		return nil, err
+	}
+	return BuildWeightSeries(records), nil
+}
+
+// BuildWeightSeries calcula a variação percentual entre pesagens consecutivas
+func BuildWeightSeries(records []model.WeightRecord) []WeightPoint {
+	series := make([]WeightPoint, 0, len(records))
+	for i, record := range records {
+		point := WeightPoint{WeightRecord: record, WeightKg: roundTo(record.WeightInKg(), 3)}
+		if i > 0 {
+			previous := records[i-1].WeightInKg()
+			if previous > 0 {
+				change := roundTo((record.WeightInKg()-previous)/previous*100, 2)
+				point.PercentChange = &change
+			}
+		}
+		series = append(series, point)
+	}
+	return series
+}
+
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}